@@ -0,0 +1,40 @@
+package agent
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// AiderRunner implements AgentRunner for Aider.
+type AiderRunner struct{}
+
+func (a *AiderRunner) ID() string     { return "aider" }
+func (a *AiderRunner) Name() string   { return "Aider" }
+func (a *AiderRunner) Binary() string { return "aider" }
+
+func (a *AiderRunner) Available() bool {
+	path, err := exec.LookPath("aider")
+	if err != nil {
+		return false
+	}
+	// `aider --version` prints "aider <version>".
+	return probeVersion(path, []string{"--version"}, "aider")
+}
+
+// BuildCommand hands Aider the task prompt as a --message, then reopens it
+// with the chat history restored so the session stays open to be attached
+// to and steered; Aider has no flag to seed an interactive session. Aider
+// finds the repository, and keeps its history, in its working directory,
+// which Spawn sets to the worktree.
+func (a *AiderRunner) BuildCommand(opts SpawnOpts) string {
+	skipFlag := ""
+	if opts.Task.SkipPermissions {
+		skipFlag = "--yes-always "
+	}
+	return fmt.Sprintf("aider %s--message %s; exec aider %s--restore-chat-history",
+		skipFlag, shellQuote(buildTaskPrompt(opts)), skipFlag)
+}
+
+func (a *AiderRunner) BuildEnrichmentCommand(opts SpawnOpts) string {
+	return "" // Aider edits files but can't be trusted to run the agentboard CLI unattended
+}
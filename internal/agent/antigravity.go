@@ -0,0 +1,38 @@
+package agent
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// AntigravityRunner implements AgentRunner for Google Antigravity. It is
+// an editor, so the agent runs in an editor window rather than in tmux.
+type AntigravityRunner struct{}
+
+func (a *AntigravityRunner) ID() string     { return "antigravity" }
+func (a *AntigravityRunner) Name() string   { return "Antigravity" }
+func (a *AntigravityRunner) Binary() string { return "antigravity" }
+
+func (a *AntigravityRunner) Available() bool {
+	path, err := exec.LookPath("antigravity")
+	if err != nil {
+		return false
+	}
+	// `antigravity --version` prints a bare version number, so only check
+	// that it runs.
+	return probeVersion(path, []string{"--version"}, "")
+}
+
+// BuildCommand opens the worktree in a new editor window and hands the
+// task prompt to its agent. --wait keeps the tmux window, and with it the
+// task's agent status, alive until the editor window is closed.
+func (a *AntigravityRunner) BuildCommand(opts SpawnOpts) string {
+	return fmt.Sprintf("antigravity --new-window --wait %s & antigravity chat --mode agent --reuse-window %s; wait",
+		shellQuote(opts.WorkDir),
+		shellQuote(buildTaskPrompt(opts)),
+	)
+}
+
+func (a *AntigravityRunner) BuildEnrichmentCommand(opts SpawnOpts) string {
+	return "" // Antigravity has no one-shot mode
+}
@@ -0,0 +1,45 @@
+package agent
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// CodexRunner implements AgentRunner for OpenAI's Codex CLI.
+type CodexRunner struct{}
+
+func (c *CodexRunner) ID() string     { return "codex" }
+func (c *CodexRunner) Name() string   { return "Codex" }
+func (c *CodexRunner) Binary() string { return "codex" }
+
+func (c *CodexRunner) Available() bool {
+	path, err := exec.LookPath("codex")
+	if err != nil {
+		return false
+	}
+	// `codex --version` prints "codex-cli <version>".
+	return probeVersion(path, []string{"--version"}, "codex")
+}
+
+// BuildCommand starts an interactive session seeded with the task prompt.
+// --cd pins Codex's workspace root, and so its sandbox, to the worktree.
+func (c *CodexRunner) BuildCommand(opts SpawnOpts) string {
+	skipFlag := ""
+	if opts.Task.SkipPermissions {
+		skipFlag = "--dangerously-bypass-approvals-and-sandbox "
+	}
+	return fmt.Sprintf("codex %s--cd %s %s",
+		skipFlag,
+		shellQuote(opts.WorkDir),
+		shellQuote(buildTaskPrompt(opts)),
+	)
+}
+
+// BuildEnrichmentCommand runs `codex exec`, the non-interactive mode.
+// --full-auto lets it write to the workspace, where the board lives.
+func (c *CodexRunner) BuildEnrichmentCommand(opts SpawnOpts) string {
+	return fmt.Sprintf("codex exec --full-auto --cd %s %s",
+		shellQuote(opts.WorkDir),
+		shellQuote(enrichmentPrompt(opts.Task)),
+	)
+}
@@ -0,0 +1,40 @@
+package agent
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// GeminiRunner implements AgentRunner for Google's Gemini CLI.
+type GeminiRunner struct{}
+
+func (g *GeminiRunner) ID() string     { return "gemini" }
+func (g *GeminiRunner) Name() string   { return "Gemini CLI" }
+func (g *GeminiRunner) Binary() string { return "gemini" }
+
+func (g *GeminiRunner) Available() bool {
+	path, err := exec.LookPath("gemini")
+	if err != nil {
+		return false
+	}
+	// `gemini --version` prints a bare version number, so only check that
+	// it runs.
+	return probeVersion(path, []string{"--version"}, "")
+}
+
+// BuildCommand starts an interactive session seeded with the task prompt.
+// Gemini CLI has no directory flag and works in the directory it is
+// started in, which Spawn sets to the worktree.
+func (g *GeminiRunner) BuildCommand(opts SpawnOpts) string {
+	skipFlag := ""
+	if opts.Task.SkipPermissions {
+		skipFlag = "--yolo "
+	}
+	return fmt.Sprintf("gemini %s--prompt-interactive %s", skipFlag, shellQuote(buildTaskPrompt(opts)))
+}
+
+// BuildEnrichmentCommand runs a one-shot --prompt. --yolo auto-approves
+// the agentboard commands it runs, since nobody is there to confirm them.
+func (g *GeminiRunner) BuildEnrichmentCommand(opts SpawnOpts) string {
+	return fmt.Sprintf("gemini --yolo --prompt %s", shellQuote(enrichmentPrompt(opts.Task)))
+}
@@ -0,0 +1,288 @@
+package agent
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/markx3/agentboard/internal/db"
+	"github.com/markx3/agentboard/internal/workflow"
+	"github.com/markx3/agentboard/internal/worktree"
+)
+
+//go:embed prompts/*.tmpl
+var builtinPromptFS embed.FS
+
+// Template names with a special meaning. Every other template is named
+// after the status it is used for.
+const (
+	commonPrompt  = "common"
+	defaultPrompt = "default"
+	reviewPrompt  = "review"
+	kickoffPrompt = "kickoff"
+)
+
+// PromptData is what prompt templates are rendered with.
+type PromptData struct {
+	Task    db.Task
+	ShortID string
+	// Stage is the task's column; Stage.Prompt is its configured
+	// instructions, if any.
+	Stage      workflow.Column
+	NextStatus db.TaskStatus
+	PrevStatus db.TaskStatus
+	WorkDir    string
+	// Review is set in review columns.
+	Review   *ReviewContext
+	Comments []db.Comment
+	// Dependencies are the tasks this one is blocked by, and Blockers
+	// those of them not yet in the last column.
+	Dependencies []db.Task
+	Blockers     []db.Task
+}
+
+// ReviewContext is what a reviewer gets to see of the task's changes.
+type ReviewContext struct {
+	// Base is the branch the task's branch is compared against.
+	Base string
+	// Stat is the `git diff --stat` summary and Diff the diff itself, cut
+	// (Truncated) to maxReviewDiff bytes and again by SpawnCommand to fit
+	// the prompt in a tmux command.
+	Stat      string
+	Diff      string
+	Truncated bool
+	// Err explains why the diff is missing, if it is.
+	Err string
+}
+
+// maxReviewDiff is what fits in a tmux command, whose limit is about
+// 16 KiB, next to a typical review prompt.
+const maxReviewDiff = 12 * 1024
+
+var promptFuncs = template.FuncMap{
+	"short":   func(id string) string { return id[:min(8, len(id))] },
+	"oneLine": func(s string) string { return strings.Join(strings.Fields(s), " ") },
+	"trim":    strings.TrimSpace,
+	"inc":     func(i int) int { return i + 1 },
+}
+
+// Prompts holds the per-stage prompt templates: the built-in ones, with a
+// project's own templates in place of any they override.
+type Prompts struct {
+	stages map[string]*template.Template
+	// custom marks the stages a project template overrides.
+	custom map[string]bool
+}
+
+var builtinPrompts = mustLoadBuiltinPrompts()
+
+func mustLoadBuiltinPrompts() *Prompts {
+	p, err := loadPrompts(builtinPromptFS, nil)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// LoadPrompts reads the project's templates from dir (one <stage>.tmpl per
+// status, plus common.tmpl to redefine shared blocks) over the built-in
+// ones. A missing dir means built-ins only. Templates that fail to parse
+// or to render a sample task are reported.
+func LoadPrompts(dir string) (*Prompts, error) {
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		return builtinPrompts, nil
+	}
+	return loadPrompts(builtinPromptFS, os.DirFS(dir))
+}
+
+func loadPrompts(builtin, project fs.FS) (*Prompts, error) {
+	sources := make(map[string]string)
+	custom := make(map[string]bool)
+	if err := readTemplates(builtin, "prompts", sources, nil); err != nil {
+		return nil, err
+	}
+	if project != nil {
+		if err := readTemplates(project, ".", sources, custom); err != nil {
+			return nil, err
+		}
+	}
+
+	base := template.New(commonPrompt).Funcs(promptFuncs)
+	if _, err := base.Parse(builtinSource(builtin, commonPrompt)); err != nil {
+		return nil, fmt.Errorf("built-in prompt %s: %w", commonPrompt, err)
+	}
+	if custom[commonPrompt] {
+		if _, err := base.Parse(sources[commonPrompt]); err != nil {
+			return nil, fmt.Errorf("prompt %s.tmpl: %w", commonPrompt, err)
+		}
+	}
+
+	p := &Prompts{stages: make(map[string]*template.Template), custom: custom}
+	for name, src := range sources {
+		if name == commonPrompt {
+			continue
+		}
+		t, err := base.Clone()
+		if err == nil {
+			_, err = t.New(name).Parse(src)
+		}
+		if err == nil {
+			err = t.ExecuteTemplate(io.Discard, name, samplePromptData)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("prompt %s.tmpl: %w", name, err)
+		}
+		p.stages[name] = t
+	}
+	return p, nil
+}
+
+// readTemplates adds every *.tmpl in dir to sources, keyed by name without
+// the extension, and marks them in custom when it is non-nil.
+func readTemplates(fsys fs.FS, dir string, sources map[string]string, custom map[string]bool) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("reading prompts: %w", err)
+	}
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".tmpl")
+		if e.IsDir() || !ok {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, filepath.ToSlash(filepath.Join(dir, e.Name())))
+		if err != nil {
+			return fmt.Errorf("reading prompts: %w", err)
+		}
+		sources[name] = string(data)
+		if custom != nil {
+			custom[name] = true
+		}
+	}
+	return nil
+}
+
+func builtinSource(fsys fs.FS, name string) string {
+	data, _ := fs.ReadFile(fsys, "prompts/"+name+".tmpl")
+	return string(data)
+}
+
+// Render returns the prompt for opts and the short first message for
+// runners that take one separately (the template's "kickoff" block). A
+// project template for the task's status wins, then the column's
+// configured prompt, then the built-in template for the status. Other
+// review columns use the review template.
+func (p *Prompts) Render(opts SpawnOpts) (prompt, kickoff string, err error) {
+	name := string(opts.Task.Status)
+	if !p.custom[name] && opts.Stage.Prompt != "" {
+		name = defaultPrompt
+	}
+	if _, ok := p.stages[name]; !ok && opts.Stage.Review {
+		name = reviewPrompt
+	}
+	t, ok := p.stages[name]
+	if !ok {
+		name, t = defaultPrompt, p.stages[defaultPrompt]
+	}
+
+	data := promptData(opts)
+	var b strings.Builder
+	if err := t.ExecuteTemplate(&b, name, data); err != nil {
+		return "", "", fmt.Errorf("rendering prompt %s: %w", name, err)
+	}
+	var k strings.Builder
+	if err := t.ExecuteTemplate(&k, kickoffPrompt, data); err != nil {
+		return "", "", fmt.Errorf("rendering prompt %s: %w", name, err)
+	}
+	return b.String(), strings.TrimSpace(k.String()), nil
+}
+
+func promptData(opts SpawnOpts) PromptData {
+	stage := opts.Stage
+	if stage.Status == "" {
+		stage = workflow.Column{Status: opts.Task.Status, Name: string(opts.Task.Status)}
+	}
+	return PromptData{
+		Task:         opts.Task,
+		ShortID:      opts.Task.ID[:8],
+		Stage:        stage,
+		NextStatus:   opts.NextStatus,
+		PrevStatus:   opts.PrevStatus,
+		WorkDir:      opts.WorkDir,
+		Review:       opts.Review,
+		Comments:     opts.Comments,
+		Dependencies: opts.Dependencies,
+		Blockers:     opts.Blockers,
+	}
+}
+
+// samplePromptData exercises every field when checking project templates.
+var samplePromptData = PromptData{
+	Task: db.Task{
+		ID:        "0123456789abcdef",
+		Title:     "Sample task",
+		Status:    db.StatusInProgress,
+		Checklist: []db.ChecklistItem{{Text: "Tests pass"}},
+	},
+	ShortID:      "01234567",
+	Stage:        workflow.Column{Status: db.StatusInProgress, Name: "In Progress", Prompt: "Sample instructions."},
+	NextStatus:   db.StatusDone,
+	PrevStatus:   db.StatusPlanning,
+	Review:       &ReviewContext{Base: "main", Stat: " a.go | 1 +", Diff: "+a"},
+	Comments:     []db.Comment{{Author: "alice", Body: "A comment"}},
+	Dependencies: []db.Task{{ID: "fedcba9876543210", Title: "Blocker", Status: db.StatusPlanning}},
+	Blockers:     []db.Task{{ID: "fedcba9876543210", Title: "Blocker", Status: db.StatusPlanning}},
+}
+
+// newReviewContext diffs the worktree at workDir against the branch it was
+// created from. Failures are recorded in Err rather than returned, so a
+// reviewer can still be spawned and look for itself.
+func newReviewContext(workDir string) *ReviewContext {
+	rc := &ReviewContext{}
+	base, err := worktree.MainBranch(workDir)
+	if err == nil && base == "" {
+		err = errors.New("the main worktree has no branch checked out")
+	}
+	if err != nil {
+		rc.Err = err.Error()
+		return rc
+	}
+	rc.Base = base
+	diff, stat, err := worktree.Diff(workDir, base)
+	if err != nil {
+		rc.Err = err.Error()
+		return rc
+	}
+	rc.Stat = stat
+	if len(diff) > maxReviewDiff {
+		diff = strings.ToValidUTF8(diff[:maxReviewDiff], "")
+		rc.Truncated = true
+	}
+	rc.Diff = diff
+	return rc
+}
+
+// renderPrompt renders opts with its templates for a runner. Spawns go
+// through PrepareSpawn, which reports a project template that fails on
+// the task, so the fallback to the built-ins only covers callers that
+// build SpawnOpts themselves.
+func renderPrompt(opts SpawnOpts) (prompt, kickoff string) {
+	p := opts.Prompts
+	if p == nil {
+		p = builtinPrompts
+	}
+	prompt, kickoff, err := p.Render(opts)
+	if err != nil && p != builtinPrompts {
+		prompt, kickoff, err = builtinPrompts.Render(opts)
+	}
+	if err != nil {
+		// The built-in templates render any task.
+		panic(err)
+	}
+	return prompt, kickoff
+}
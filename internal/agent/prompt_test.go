@@ -0,0 +1,251 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/markx3/agentboard/internal/board"
+	"github.com/markx3/agentboard/internal/config"
+	"github.com/markx3/agentboard/internal/db"
+	"github.com/markx3/agentboard/internal/workflow"
+)
+
+func writePrompts(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadPromptsMissingDir(t *testing.T) {
+	p, err := LoadPrompts(filepath.Join(t.TempDir(), "prompts"))
+	if err != nil {
+		t.Fatalf("LoadPrompts: %v", err)
+	}
+	if p != builtinPrompts {
+		t.Error("missing dir should give the built-in prompts")
+	}
+}
+
+func TestPromptOverrides(t *testing.T) {
+	dir := writePrompts(t, map[string]string{
+		"in_progress.tmpl": `{{template "header" .}}Fix it in {{.WorkDir}}.
+{{range .Blockers}}Wait for {{short .ID}}.
+{{end}}{{range .Comments}}{{.Author}} said {{.Body}}
+{{end}}{{define "kickoff"}}Go.{{end}}`,
+		"common.tmpl": `{{define "header"}}TASK {{.ShortID}}: {{.Task.Title}}
+{{end}}`,
+		"notes.txt": "ignored",
+	})
+	p, err := LoadPrompts(dir)
+	if err != nil {
+		t.Fatalf("LoadPrompts: %v", err)
+	}
+
+	opts := SpawnOpts{
+		WorkDir:  "/work/fix",
+		Task:     db.Task{ID: "abcdef1234567890", Title: "Fix", Status: db.StatusInProgress},
+		Comments: []db.Comment{{Author: "bob", Body: "see the logs"}},
+		Blockers: []db.Task{{ID: "1234567890abcdef", Title: "Blocker"}},
+		Prompts:  p,
+	}
+	prompt, kickoff, err := p.Render(opts)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	want := "TASK abcdef12: Fix\nFix it in /work/fix.\nWait for 12345678.\nbob said see the logs\n"
+	if prompt != want {
+		t.Errorf("prompt = %q, want %q", prompt, want)
+	}
+	if kickoff != "Go." {
+		t.Errorf("kickoff = %q, want Go.", kickoff)
+	}
+	if cmd := (&ClaudeRunner{}).BuildCommand(opts); !strings.HasSuffix(cmd, " 'Go.'") {
+		t.Errorf("claude command should use the template's kickoff: %s", cmd)
+	}
+
+	// Stages without an override keep the built-in text, with the
+	// project's shared blocks.
+	opts.Task.Status = db.StatusPlanning
+	prompt, kickoff, _ = p.Render(opts)
+	if !strings.HasPrefix(prompt, "TASK abcdef12: Fix\n") || !strings.Contains(prompt, "STAGE: Planning") {
+		t.Errorf("planning prompt = %q", prompt)
+	}
+	if kickoff != "Create a detailed implementation plan for this task." {
+		t.Errorf("planning kickoff = %q", kickoff)
+	}
+}
+
+func TestPromptPrecedence(t *testing.T) {
+	dir := writePrompts(t, map[string]string{"qa.tmpl": "QA template for {{.ShortID}}\n"})
+	p, err := LoadPrompts(dir)
+	if err != nil {
+		t.Fatalf("LoadPrompts: %v", err)
+	}
+	opts := SpawnOpts{
+		Task:  db.Task{ID: "abcdef1234567890", Title: "Test", Status: "qa"},
+		Stage: workflow.Column{Status: "qa", Name: "QA", Prompt: "Configured QA."},
+	}
+
+	// A project template beats the column's configured prompt...
+	if prompt, _, _ := p.Render(opts); prompt != "QA template for abcdef12\n" {
+		t.Errorf("qa prompt = %q, want the project template", prompt)
+	}
+	// ...which beats the built-in template for the status.
+	opts.Task.Status = db.StatusPlanning
+	opts.Stage = workflow.Column{Status: db.StatusPlanning, Name: "Plan", Prompt: "Configured plan."}
+	prompt, kickoff, _ := p.Render(opts)
+	if !strings.Contains(prompt, "STAGE: Plan\nConfigured plan.") || strings.Contains(prompt, "Implementation Design") {
+		t.Errorf("planning prompt = %q, want the configured one", prompt)
+	}
+	if kickoff != "Work on this task following the Plan stage instructions." {
+		t.Errorf("kickoff = %q", kickoff)
+	}
+}
+
+func TestLoadPromptsRejectsBadTemplates(t *testing.T) {
+	tests := map[string]string{
+		"parse error":   "{{if .Task.Title}}unclosed",
+		"unknown field": "{{.Task.Nmae}}",
+		"unknown block": `{{template "nope" .}}`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := writePrompts(t, map[string]string{"review.tmpl": content})
+			_, err := LoadPrompts(dir)
+			if err == nil || !strings.Contains(err.Error(), "review.tmpl") {
+				t.Errorf("err = %v, want one naming review.tmpl", err)
+			}
+		})
+	}
+}
+
+func TestPrepareSpawnRejectsTemplateFailingOnTask(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, config.Dir)
+	if err := os.MkdirAll(filepath.Join(dir, config.PromptsDirName), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// Fine for the sample task, out of range for one without comments.
+	tmpl := `{{if eq .Task.Title "Sample task"}}ok{{else}}{{(index .Comments 0).Body}}{{end}}`
+	if err := os.WriteFile(filepath.Join(dir, config.PromptsDirName, "backlog.tmpl"), []byte(tmpl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	database, err := db.Open(filepath.Join(dir, config.DBFileName))
+	if err != nil {
+		t.Fatalf("opening test db: %v", err)
+	}
+	defer database.Close()
+	origDir, _ := os.Getwd()
+	os.Chdir(root)
+	defer os.Chdir(origDir)
+
+	svc := board.NewLocalService(database)
+	ctx := context.Background()
+	task, _ := svc.CreateTask(ctx, "No comments", "")
+	_, err = PrepareSpawn(ctx, svc, *task, root, config.Default())
+	if err == nil || !strings.Contains(err.Error(), "backlog") {
+		t.Errorf("err = %v, want the backlog template's error", err)
+	}
+}
+
+func TestReviewPrompt(t *testing.T) {
+	opts := SpawnOpts{
+		Task: db.Task{
+			ID:         "abcdef1234567890",
+			Title:      "Fix login",
+			Status:     db.StatusReview,
+			BranchName: "agentboard/fix-login",
+			PRUrl:      "https://github.com/o/r/pull/7",
+		},
+		Stage:      workflow.Column{Status: db.StatusReview, Name: "Review", Review: true},
+		NextStatus: db.StatusDone,
+		PrevStatus: db.StatusInProgress,
+		Review:     &ReviewContext{Base: "main", Stat: " login.go | 2 +-", Diff: "-old\n+new"},
+	}
+	prompt, kickoff := renderPrompt(opts)
+	for _, want := range []string{
+		"STAGE: Review — Code Review",
+		"gh pr diff https://github.com/o/r/pull/7",
+		"git diff main...HEAD",
+		"login.go | 2 +-",
+		"-old\n+new",
+		"--author reviewer --body \"[blocking]",
+		"REVIEW: approved",
+		"task move abcdef12 done",
+		"task move abcdef12 in_progress",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("review prompt should contain %q", want)
+		}
+	}
+	if !strings.HasPrefix(kickoff, "Review this task's changes") {
+		t.Errorf("kickoff = %q", kickoff)
+	}
+
+	// Any column marked review gets the reviewer, even without a diff.
+	opts.Task.Status = "qa"
+	opts.Stage = workflow.Column{Status: "qa", Name: "QA", Review: true}
+	opts.Review = &ReviewContext{Err: "not a git repository"}
+	prompt, _ = renderPrompt(opts)
+	if !strings.Contains(prompt, "STAGE: QA — Code Review") || !strings.Contains(prompt, "could not be read (not a git repository)") {
+		t.Errorf("qa review prompt = %q", prompt)
+	}
+	if strings.Contains(prompt, "DIFF:") {
+		t.Error("prompt without a diff should not have a DIFF section")
+	}
+}
+
+func TestSpawnCommandFitsLargeDiff(t *testing.T) {
+	diff := strings.Repeat("+fmt.Println('it''s')\n", 2000)
+	opts := SpawnOpts{
+		WorkDir:    "/work/fix-login",
+		Task:       db.Task{ID: "abcdef1234567890", Title: "Fix login", Status: db.StatusReview},
+		Stage:      workflow.Column{Status: db.StatusReview, Name: "Review", Review: true},
+		NextStatus: db.StatusDone,
+		Review:     &ReviewContext{Base: "main", Diff: diff},
+	}
+	for _, runner := range []AgentRunner{&ClaudeRunner{}, &CursorRunner{}, &CodexRunner{}} {
+		cmd := SpawnCommand(runner, opts)
+		if len(cmd) > maxSpawnCommand {
+			t.Errorf("%s: command is %d bytes, want at most %d", runner.ID(), len(cmd), maxSpawnCommand)
+		}
+		if !strings.Contains(cmd, "DIFF:\n+fmt.Println") || !strings.Contains(cmd, "[diff truncated") {
+			t.Errorf("%s: command should keep part of the diff and say it was cut", runner.ID())
+		}
+	}
+	if opts.Review.Diff != diff || opts.Review.Truncated {
+		t.Error("SpawnCommand should not change the caller's review context")
+	}
+}
+
+func TestInProgressPromptHandsOffToReview(t *testing.T) {
+	opts := SpawnOpts{
+		Task:       db.Task{ID: "abcdef1234567890", Title: "Fix", Status: db.StatusInProgress},
+		NextStatus: db.StatusReview,
+	}
+	prompt := buildTaskPrompt(opts)
+	if !strings.Contains(prompt, "task move abcdef12 review") || strings.Contains(prompt, "task move abcdef12 done") {
+		t.Errorf("in-progress prompt should move the task to review:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "[blocking]") {
+		t.Error("prompt without comments should not mention review findings")
+	}
+	opts.Comments = []db.Comment{{Author: "reviewer", Body: "[blocking] login.go:3 nil check"}}
+	if prompt := buildTaskPrompt(opts); !strings.Contains(prompt, "reviewer: [blocking] login.go:3 nil check") {
+		t.Errorf("prompt should carry the review findings:\n%s", prompt)
+	}
+}
+
+func TestNewReviewContextOutsideRepo(t *testing.T) {
+	if rc := newReviewContext(t.TempDir()); rc.Err == "" || rc.Diff != "" {
+		t.Errorf("review context = %+v, want an error and no diff", rc)
+	}
+}
@@ -0,0 +1,99 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/markx3/agentboard/internal/board"
+	"github.com/markx3/agentboard/internal/config"
+	"github.com/markx3/agentboard/internal/db"
+)
+
+// Queue holds agents waiting for a free slot under
+// agent.max_concurrent_agents. *board.LocalService implements it; remote
+// boards have no queue.
+type Queue interface {
+	EnqueueAgent(ctx context.Context, taskID, runner string) error
+	DequeueAgent(ctx context.Context, taskID string) (bool, error)
+	ListAgentQueue(ctx context.Context) ([]db.QueuedAgent, error)
+}
+
+// ActiveAgents counts the tasks with a running agent.
+func ActiveAgents(tasks []db.Task) int {
+	n := 0
+	for _, t := range tasks {
+		if t.AgentStatus == db.AgentActive {
+			n++
+		}
+	}
+	return n
+}
+
+// StartQueued spawns queued agents, oldest first, while fewer than
+// cfg.Agent.MaxConcurrentAgents are running, and returns the tasks it
+// started them on. Entries for tasks that already have an agent are
+// dropped; entries whose runner is not available stay queued. An entry
+// whose spawn fails is dropped and its error returned with the others.
+func StartQueued(ctx context.Context, svc board.Service, queue Queue, cfg *config.Config) ([]db.Task, error) {
+	return startQueued(ctx, svc, queue, cfg, PickRunner, Spawn)
+}
+
+type spawnFunc func(context.Context, board.Service, db.Task, AgentRunner, *config.Config) error
+
+func startQueued(ctx context.Context, svc board.Service, queue Queue, cfg *config.Config, pick func(id, preferred string) (AgentRunner, error), spawn spawnFunc) ([]db.Task, error) {
+	if cfg == nil {
+		cfg = config.Default()
+	}
+	queued, err := queue.ListAgentQueue(ctx)
+	if err != nil || len(queued) == 0 {
+		return nil, err
+	}
+	tasks, err := svc.ListTasks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing tasks: %w", err)
+	}
+	byID := make(map[string]db.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	free := cfg.Agent.MaxConcurrentAgents - ActiveAgents(tasks)
+	var started []db.Task
+	var errs []error
+	for _, q := range queued {
+		if free <= 0 {
+			break
+		}
+		task, ok := byID[q.TaskID]
+		if ok && task.AgentStatus == db.AgentActive {
+			ok = false
+		}
+		if !ok {
+			if _, err := queue.DequeueAgent(ctx, q.TaskID); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		runner, err := pick(q.Runner, cfg.Agent.Preferred)
+		if err != nil || !runner.Available() {
+			continue
+		}
+		// Claim the entry first so two schedulers never spawn it twice.
+		claimed, err := queue.DequeueAgent(ctx, q.TaskID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		if err := spawn(ctx, svc, task, runner, cfg); err != nil {
+			errs = append(errs, fmt.Errorf("task %s: %w", task.ID[:8], err))
+			continue
+		}
+		free--
+		started = append(started, task)
+	}
+	return started, errors.Join(errs...)
+}
@@ -0,0 +1,92 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/markx3/agentboard/internal/board"
+	"github.com/markx3/agentboard/internal/config"
+	"github.com/markx3/agentboard/internal/db"
+)
+
+func TestStartQueued(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("opening test db: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	svc := board.NewLocalService(database)
+	ctx := context.Background()
+
+	newTask := func(title string) *db.Task {
+		task, err := svc.CreateTask(ctx, title, "")
+		if err != nil {
+			t.Fatalf("creating task: %v", err)
+		}
+		return task
+	}
+	running := newTask("Running")
+	running.AgentStatus = db.AgentActive
+	if err := svc.UpdateTask(ctx, running); err != nil {
+		t.Fatal(err)
+	}
+	waiting := newTask("Waiting for its runner")
+	first := newTask("First")
+	second := newTask("Second")
+	third := newTask("Third")
+	failing := newTask("Failing")
+	for _, q := range []struct {
+		task   *db.Task
+		runner string
+	}{{running, ""}, {waiting, "missing"}, {first, ""}, {failing, ""}, {second, "fake"}, {third, ""}} {
+		if err := svc.EnqueueAgent(ctx, q.task.ID, q.runner); err != nil {
+			t.Fatalf("enqueueing %s: %v", q.task.Title, err)
+		}
+	}
+
+	runners := map[string]AgentRunner{
+		"fake":    NewTemplateRunner(config.RunnerConfig{ID: "fake", Command: "true {prompt}"}),
+		"missing": NewTemplateRunner(config.RunnerConfig{ID: "missing", Command: "agentboard-no-such-cli {prompt}"}),
+	}
+	pick := func(id, preferred string) (AgentRunner, error) {
+		if id == "" {
+			id = "fake"
+		}
+		return runners[id], nil
+	}
+	var spawned []string
+	spawn := func(_ context.Context, _ board.Service, task db.Task, runner AgentRunner, _ *config.Config) error {
+		if task.ID == failing.ID {
+			return fmt.Errorf("no tmux")
+		}
+		spawned = append(spawned, task.Title+"/"+runner.ID())
+		return nil
+	}
+
+	cfg := config.Default()
+	cfg.Agent.MaxConcurrentAgents = 3
+	started, err := startQueued(ctx, svc, svc, cfg, pick, spawn)
+	if err == nil {
+		t.Error("expected the failed spawn to be reported")
+	}
+	// One slot is taken by the running agent: the next two that can start
+	// do, in queue order.
+	if fmt.Sprint(spawned) != "[First/fake Second/fake]" || len(started) != 2 {
+		t.Errorf("spawned %v (started %d), want First and Second", spawned, len(started))
+	}
+
+	queued, err := svc.ListAgentQueue(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var left []string
+	for _, q := range queued {
+		task, _ := svc.GetTask(ctx, q.TaskID)
+		left = append(left, task.Title)
+	}
+	if fmt.Sprint(left) != "[Waiting for its runner Third]" {
+		t.Errorf("queue left = %v, want the unavailable runner's task and Third", left)
+	}
+}
@@ -1,13 +1,24 @@
 package agent
 
-import "github.com/markx3/agentboard/internal/db"
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/markx3/agentboard/internal/config"
+	"github.com/markx3/agentboard/internal/db"
+	"github.com/markx3/agentboard/internal/workflow"
+)
 
 // AgentRunner abstracts an AI agent CLI.
 type AgentRunner interface {
-	ID() string                                  // Canonical DB identifier (e.g., "claude", "cursor")
-	Name() string                                // Display name (e.g., "Claude Code", "Cursor")
-	Binary() string                              // Executable name for PATH lookup
-	Available() bool                             // Is this agent detected and verified?
+	ID() string                                   // Canonical DB identifier (e.g., "claude", "cursor")
+	Name() string                                 // Display name (e.g., "Claude Code", "Cursor")
+	Binary() string                               // Executable name for PATH lookup
+	Available() bool                              // Is this agent detected and verified?
 	BuildCommand(opts SpawnOpts) string           // Build the full shell command for task work
 	BuildEnrichmentCommand(opts SpawnOpts) string // Build enrichment command ("" if unsupported)
 }
@@ -17,11 +28,80 @@ type SpawnOpts struct {
 	WorkDir string
 	Task    db.Task
 	ExePath string // Absolute path to agentboard binary
+	// Stage is the workflow column the task is in. A non-empty Stage.Prompt
+	// replaces the runner's built-in instructions for that column.
+	Stage workflow.Column
+	// NextStatus is the column after Stage, or "" in the last column.
+	NextStatus db.TaskStatus
+	// PrevStatus is the column before Stage, or "" in the first column.
+	PrevStatus db.TaskStatus
+	// Review holds the task's changes when Stage is a review column.
+	Review *ReviewContext
+	// Comments, Dependencies and Blockers are shown to the agent; see
+	// PromptData.
+	Comments     []db.Comment
+	Dependencies []db.Task
+	Blockers     []db.Task
+	// Prompts renders the prompt; nil means the built-in templates.
+	Prompts *Prompts
+}
+
+// buildTaskPrompt is the single prompt given to runners whose CLI takes
+// no separate system prompt.
+func buildTaskPrompt(opts SpawnOpts) string {
+	prompt, _ := renderPrompt(opts)
+	return prompt
+}
+
+// enrichmentPrompt asks a one-shot agent to flesh out a task's description.
+func enrichmentPrompt(task db.Task) string {
+	shortID := task.ID[:8]
+	return fmt.Sprintf(
+		"Enrich task %q (%s): run `git log --oneline -10` and `agentboard task list --json`, "+
+			"then update the description and leave a comment. "+
+			"Commands: `agentboard task update %s --description \"<enriched>\"` "+
+			"and `agentboard task comment %s --author enrichment --body \"<one sentence>\"`.",
+		task.Title, shortID, shortID, shortID,
+	)
 }
 
-var runners = []AgentRunner{
+// probeVersion runs path with args and reports whether it succeeds within
+// two seconds and, if match is set, prints match (case-insensitive).
+func probeVersion(path string, args []string, match string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, args...).Output()
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(out)), strings.ToLower(match))
+}
+
+var builtinRunners = []AgentRunner{
 	&ClaudeRunner{},
 	&CursorRunner{},
+	&CodexRunner{},
+	&GeminiRunner{},
+	&AiderRunner{},
+	&AntigravityRunner{},
+}
+
+var runners = builtinRunners
+
+// SetCustomRunners registers the runners declared in config alongside the
+// built-ins, replacing any earlier declarations. A declared runner whose
+// ID matches a built-in takes its place.
+func SetCustomRunners(defs []config.RunnerConfig) {
+	next := slices.Clone(builtinRunners)
+	for _, def := range defs {
+		r := NewTemplateRunner(def)
+		if i := slices.IndexFunc(next, func(b AgentRunner) bool { return b.ID() == def.ID }); i >= 0 {
+			next[i] = r
+		} else {
+			next = append(next, r)
+		}
+	}
+	runners = next
 }
 
 // AvailableRunners returns all runners whose CLI binary is detected.
@@ -44,3 +124,25 @@ func GetRunner(id string) AgentRunner {
 	}
 	return nil
 }
+
+// PickRunner returns the runner with the given ID, or when id is empty the
+// preferred runner if it is available and else the first available one.
+func PickRunner(id, preferred string) (AgentRunner, error) {
+	if id != "" {
+		runner := GetRunner(id)
+		if runner == nil {
+			return nil, fmt.Errorf("unknown runner: %s", id)
+		}
+		return runner, nil
+	}
+	available := AvailableRunners()
+	if len(available) == 0 {
+		return nil, fmt.Errorf("no agent runners available")
+	}
+	for _, r := range available {
+		if r.ID() == preferred {
+			return r, nil
+		}
+	}
+	return available[0], nil
+}
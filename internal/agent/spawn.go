@@ -14,6 +14,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/markx3/agentboard/internal/board"
+	"github.com/markx3/agentboard/internal/config"
 	"github.com/markx3/agentboard/internal/db"
 	"github.com/markx3/agentboard/internal/tmux"
 )
@@ -43,10 +44,10 @@ func WindowName(task db.Task) string {
 }
 
 // DeactivateRalphLoop sets active: false in the ralph-loop state file
-// for the given task's worktree. This prevents a respawned agent from
+// inside a task's worktree dir. This prevents a respawned agent from
 // inheriting an active loop. No-op if the file does not exist.
-func DeactivateRalphLoop(task db.Task) error {
-	stateFile := filepath.Join(TaskSlug(task.Title), ".claude", "ralph-loop.local.md")
+func DeactivateRalphLoop(workDir string) error {
+	stateFile := filepath.Join(workDir, ".claude", "ralph-loop.local.md")
 	data, err := os.ReadFile(stateFile)
 	if err != nil {
 		return nil // No state file = no ralph loop to deactivate
@@ -57,33 +58,36 @@ func DeactivateRalphLoop(task db.Task) error {
 
 // Spawn launches an AI agent in a tmux window for the given task.
 // The runner determines which CLI is used and how the command is built.
-func Spawn(ctx context.Context, svc board.Service, task db.Task, runner AgentRunner) error {
+// A nil cfg falls back to config.Default().
+func Spawn(ctx context.Context, svc board.Service, task db.Task, runner AgentRunner, cfg *config.Config) error {
+	if cfg == nil {
+		cfg = config.Default()
+	}
+
 	// Ensure tmux session
 	if err := tmux.EnsureSession(); err != nil {
 		return fmt.Errorf("tmux: %w", err)
 	}
 
-	slug := TaskSlug(task.Title)
+	workDir, branch, err := EnsureWorktree(task, cfg)
+	if err != nil {
+		return err
+	}
 	winName := WindowName(task)
+	task.BranchName = branch
 
-	opts := SpawnOpts{
-		WorkDir: slug,
-		Task:    task,
+	opts, err := PrepareSpawn(ctx, svc, task, workDir, cfg)
+	if err != nil {
+		return err
 	}
 
 	// Kill any existing window for this task (handles respawn case)
 	_ = tmux.KillWindow(winName)
 
-	cmd := runner.BuildCommand(opts)
-
-	// For Claude, working dir is passed as -w flag in the command itself.
-	// For other agents, set CWD via tmux's -c flag so the process inherits it.
-	windowDir := ""
-	if runner.ID() != "claude" {
-		windowDir = slug
-	}
+	cmd := SpawnCommand(runner, opts)
 
-	if err := tmux.NewWindow(winName, windowDir, cmd); err != nil {
+	// Every runner starts inside the task's worktree via tmux's -c flag.
+	if err := tmux.NewWindow(winName, workDir, cmd); err != nil {
 		return fmt.Errorf("creating tmux window: %w", err)
 	}
 
@@ -101,6 +105,110 @@ func Spawn(ctx context.Context, svc board.Service, task db.Task, runner AgentRun
 	return nil
 }
 
+// PrepareSpawn gathers what a runner needs to build the command for task
+// in workDir: its stage, comments, dependencies and the project's prompt
+// templates. A template that fails to render the task is an error.
+func PrepareSpawn(ctx context.Context, svc board.Service, task db.Task, workDir string, cfg *config.Config) (SpawnOpts, error) {
+	wf := cfg.BoardWorkflow()
+	opts := SpawnOpts{
+		WorkDir: workDir,
+		Task:    task,
+	}
+	if col, ok := wf.Column(task.Status); ok {
+		opts.Stage = col
+	}
+	if next := wf.Next(task.Status); next != task.Status {
+		opts.NextStatus = next
+	}
+	if prev := wf.Prev(task.Status); prev != task.Status {
+		opts.PrevStatus = prev
+	}
+	if opts.Stage.Review {
+		opts.Review = newReviewContext(workDir)
+	}
+
+	comments, err := svc.ListComments(ctx, task.ID)
+	if err != nil {
+		return opts, fmt.Errorf("listing comments: %w", err)
+	}
+	opts.Comments = comments
+	deps, err := svc.ListDependencies(ctx, task.ID)
+	if err != nil {
+		return opts, fmt.Errorf("listing dependencies: %w", err)
+	}
+	for _, id := range deps {
+		dep, err := svc.GetTask(ctx, id)
+		if err != nil {
+			return opts, fmt.Errorf("getting dependency %s: %w", id[:8], err)
+		}
+		opts.Dependencies = append(opts.Dependencies, *dep)
+		if dep.Status != wf.Last() {
+			opts.Blockers = append(opts.Blockers, *dep)
+		}
+	}
+
+	prompts, err := LoadPrompts(config.PromptsDir())
+	if err != nil {
+		return opts, err
+	}
+	opts.Prompts = prompts
+	// A template can pass the sample task and still fail on this one;
+	// stop here rather than let runners fall back to the built-ins.
+	if _, _, err := prompts.Render(opts); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// maxSpawnCommand keeps spawn commands under tmux's limit on the length
+// of a command, about 16 KiB.
+const maxSpawnCommand = 14 * 1024
+
+// SpawnCommand returns the shell command Spawn runs for runner. A review
+// diff that would push it past maxSpawnCommand is cut to the longest
+// prefix that fits; the reviewer reads the rest with git.
+func SpawnCommand(runner AgentRunner, opts SpawnOpts) string {
+	return actorCommand(runner, runner.BuildCommand(fitCommand(runner, opts)))
+}
+
+// SpawnPrompt returns the prompt in the command SpawnCommand builds.
+func SpawnPrompt(runner AgentRunner, opts SpawnOpts) string {
+	prompt, _ := renderPrompt(fitCommand(runner, opts))
+	return prompt
+}
+
+// fitCommand returns opts with the review diff cut so that runner's
+// command fits in maxSpawnCommand.
+func fitCommand(runner AgentRunner, opts SpawnOpts) SpawnOpts {
+	build := func() string { return actorCommand(runner, runner.BuildCommand(opts)) }
+	if opts.Review == nil || opts.Review.Diff == "" || len(build()) <= maxSpawnCommand {
+		return opts
+	}
+	full := opts.Review.Diff
+	review := *opts.Review
+	review.Truncated = true
+	opts.Review = &review
+
+	// Quoting makes the command grow faster than the diff, so search for
+	// the cut rather than computing it.
+	lo, hi := 0, len(full)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		review.Diff = full[:mid]
+		if len(build()) <= maxSpawnCommand {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	diff := full[:lo]
+	if i := strings.LastIndexByte(diff, '\n'); i >= 0 {
+		diff = diff[:i+1]
+	}
+	review.Diff = strings.ToValidUTF8(diff, "")
+	return opts
+}
+
 // EnrichmentWindowName returns the tmux window name for an enrichment agent.
 func EnrichmentWindowName(task db.Task) string {
 	return "enrich-" + task.ID[:8]
@@ -127,7 +235,7 @@ func SpawnEnrichment(ctx context.Context, svc board.Service, task db.Task, runne
 	// Kill any existing enrichment window for this task
 	_ = tmux.KillWindow(winName)
 
-	if err := tmux.NewWindow(winName, ".", cmd); err != nil {
+	if err := tmux.NewWindow(winName, ".", actorCommand(runner, cmd)); err != nil {
 		return fmt.Errorf("creating enrichment window: %w", err)
 	}
 
@@ -167,7 +275,7 @@ func RunEnrichment(ctx context.Context, svc board.Service, task db.Task, runner
 		EnrichmentAgentName: &runnerID,
 	})
 	return func() tea.Msg {
-		runErr := exec.CommandContext(ctx, "sh", "-c", cmd).Run()
+		runErr := exec.CommandContext(ctx, "sh", "-c", actorCommand(runner, cmd)).Run()
 		fresh, err := svc.GetTask(ctx, task.ID)
 		status := db.EnrichmentError
 		if runErr == nil && err == nil && fresh.UpdatedAt.After(task.UpdatedAt) {
@@ -199,6 +307,12 @@ func Kill(ctx context.Context, svc board.Service, task db.Task) error {
 	return nil
 }
 
+// actorCommand makes the board changes an agent makes through the CLI show
+// up under the runner's name in task history.
+func actorCommand(runner AgentRunner, cmd string) string {
+	return fmt.Sprintf("export %s=%s; %s", board.ActorEnv, shellQuote(runner.ID()), cmd)
+}
+
 func shellQuote(s string) string {
 	return "'" + strings.ReplaceAll(s, "'", "'\"'\"'") + "'"
 }
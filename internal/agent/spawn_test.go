@@ -2,11 +2,14 @@ package agent
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/markx3/agentboard/internal/config"
 	"github.com/markx3/agentboard/internal/db"
+	"github.com/markx3/agentboard/internal/workflow"
 )
 
 func TestClaudeRunnerBuildCommand(t *testing.T) {
@@ -36,9 +39,9 @@ func TestClaudeRunnerBuildCommand(t *testing.T) {
 
 	cmd := runner.BuildCommand(opts)
 
-	// Should start with claude -w
-	if !strings.HasPrefix(cmd, "claude -w ") {
-		t.Errorf("BuildCommand should start with 'claude -w', got: %s", cmd)
+	// Should start with claude; the work dir is set via tmux, not -w
+	if !strings.HasPrefix(cmd, "claude --append-system-prompt ") {
+		t.Errorf("BuildCommand should start with 'claude --append-system-prompt', got: %s", cmd)
 	}
 
 	// Should contain --append-system-prompt
@@ -46,9 +49,9 @@ func TestClaudeRunnerBuildCommand(t *testing.T) {
 		t.Error("BuildCommand should contain --append-system-prompt")
 	}
 
-	// Should contain the work dir
-	if !strings.Contains(cmd, "test-task") {
-		t.Error("BuildCommand should contain work dir")
+	// Should not pass the work dir on the command line
+	if strings.Contains(cmd, "-w ") || strings.Contains(cmd, "test-task") {
+		t.Error("BuildCommand should not contain work dir")
 	}
 
 	// Should reference the task ID
@@ -61,9 +64,12 @@ func TestClaudeRunnerBuildCommand(t *testing.T) {
 		t.Error("BuildCommand system prompt should contain stage info")
 	}
 
-	// Should contain workflow command
-	if !strings.Contains(cmd, "/workflows:work") {
-		t.Error("BuildCommand initial prompt should contain /workflows:work for in_progress stage")
+	// Should kick off with the stage's first message, not a slash command
+	if !strings.HasSuffix(cmd, " 'Implement this task based on the plan.'") {
+		t.Errorf("BuildCommand initial prompt should be the in_progress kickoff, got: %s", cmd)
+	}
+	if strings.Contains(cmd, "/workflows:") {
+		t.Error("BuildCommand should not assume /workflows: commands exist")
 	}
 }
 
@@ -134,9 +140,9 @@ func TestClaudeRunnerStagePrompts(t *testing.T) {
 		wantInitial  string
 	}{
 		{db.StatusBacklog, "Backlog", "Move it to brainstorm"},
-		{db.StatusBrainstorm, "Brainstorm", "/workflows:brainstorm"},
-		{db.StatusPlanning, "Planning", "/workflows:plan"},
-		{db.StatusInProgress, "In Progress", "/workflows:work"},
+		{db.StatusBrainstorm, "Brainstorm", "Explore ideas and approaches"},
+		{db.StatusPlanning, "Planning", "Create a detailed implementation plan"},
+		{db.StatusInProgress, "In Progress", "Implement this task"},
 		{db.StatusDone, "Done", "Verify the pull request"},
 	}
 
@@ -160,6 +166,38 @@ func TestClaudeRunnerStagePrompts(t *testing.T) {
 	}
 }
 
+func TestConfiguredStagePrompt(t *testing.T) {
+	opts := SpawnOpts{
+		WorkDir:    "test",
+		Task:       db.Task{ID: "abcdef1234567890", Title: "Test", Status: "qa"},
+		Stage:      workflow.Column{Status: "qa", Name: "QA", Prompt: "Run the manual test plan."},
+		NextStatus: "deployed",
+	}
+	for _, runner := range []AgentRunner{&ClaudeRunner{}, &CursorRunner{}} {
+		cmd := runner.BuildCommand(opts)
+		for _, want := range []string{"STAGE: QA", "Run the manual test plan.", "task move abcdef12 deployed"} {
+			if !strings.Contains(cmd, want) {
+				t.Errorf("%s: command should contain %q", runner.ID(), want)
+			}
+		}
+	}
+}
+
+func TestSystemPromptChecklist(t *testing.T) {
+	task := db.Task{ID: "abcdef1234567890", Title: "Test", Status: db.StatusInProgress}
+	if prompt := buildTaskPrompt(SpawnOpts{Task: task}); strings.Contains(prompt, "ACCEPTANCE CRITERIA") {
+		t.Error("prompt for a task without a checklist should not list acceptance criteria")
+	}
+
+	task.Checklist = []db.ChecklistItem{{Text: "Tests pass", Done: true}, {Text: "Docs updated"}}
+	prompt := buildTaskPrompt(SpawnOpts{Task: task})
+	for _, want := range []string{"ACCEPTANCE CRITERIA", "1. [x] Tests pass", "2. [ ] Docs updated", "task check tick abcdef12"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("system prompt should contain %q", want)
+		}
+	}
+}
+
 func TestClaudeRunnerBuildEnrichmentCommand(t *testing.T) {
 	runner := &ClaudeRunner{}
 
@@ -224,6 +262,69 @@ func TestCursorRunnerBuildEnrichmentCommand(t *testing.T) {
 	}
 }
 
+func TestOtherRunnersBuildCommand(t *testing.T) {
+	task := db.Task{
+		ID:     "abcdef1234567890",
+		Title:  "Test Task",
+		Status: db.StatusInProgress,
+	}
+	opts := SpawnOpts{WorkDir: "/work/test-task", Task: task}
+
+	tests := []struct {
+		runner   AgentRunner
+		prefix   string
+		skipFlag string
+		enriches bool
+	}{
+		{&CodexRunner{}, "codex --cd '/work/test-task' ", "--dangerously-bypass-approvals-and-sandbox", true},
+		{&GeminiRunner{}, "gemini --prompt-interactive ", "--yolo", true},
+		{&AiderRunner{}, "aider --message ", "--yes-always", false},
+		{&AntigravityRunner{}, "antigravity --new-window --wait '/work/test-task' & ", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.runner.ID(), func(t *testing.T) {
+			if tt.runner.Binary() != tt.runner.ID() {
+				t.Errorf("Binary() = %q, want %q", tt.runner.Binary(), tt.runner.ID())
+			}
+			cmd := tt.runner.BuildCommand(opts)
+			if !strings.HasPrefix(cmd, tt.prefix) {
+				t.Errorf("BuildCommand = %q, want prefix %q", cmd, tt.prefix)
+			}
+			if !strings.Contains(cmd, "STAGE: In Progress") || !strings.Contains(cmd, "agentboard task move abcdef12") {
+				t.Error("BuildCommand should carry the stage instructions")
+			}
+			if strings.Contains(cmd, "/workflows:") {
+				t.Error("BuildCommand should not contain /workflows: commands")
+			}
+
+			skipped := opts
+			skipped.Task.SkipPermissions = true
+			if tt.skipFlag != "" && !strings.Contains(tt.runner.BuildCommand(skipped), tt.skipFlag) {
+				t.Errorf("SkipPermissions should add %s", tt.skipFlag)
+			}
+			if tt.skipFlag != "" && strings.Contains(cmd, tt.skipFlag) {
+				t.Errorf("%s without SkipPermissions", tt.skipFlag)
+			}
+
+			enrich := tt.runner.BuildEnrichmentCommand(opts)
+			if tt.enriches != (enrich != "") {
+				t.Errorf("BuildEnrichmentCommand = %q, want enrichment %v", enrich, tt.enriches)
+			}
+			if tt.enriches && !strings.Contains(enrich, "agentboard task update abcdef12") {
+				t.Errorf("enrichment command should carry the enrichment prompt: %q", enrich)
+			}
+		})
+	}
+}
+
+func TestAiderRunnerStaysInteractive(t *testing.T) {
+	opts := SpawnOpts{Task: db.Task{ID: "abcdef1234567890", Title: "Test Task", Status: db.StatusInProgress, SkipPermissions: true}}
+	cmd := (&AiderRunner{}).BuildCommand(opts)
+	if !strings.HasSuffix(cmd, "; exec aider --yes-always --restore-chat-history") {
+		t.Errorf("BuildCommand should reopen Aider with its chat history, got: %s", cmd)
+	}
+}
+
 func TestEnrichmentWindowName(t *testing.T) {
 	task := db.Task{ID: "abcdef1234567890"}
 	name := EnrichmentWindowName(task)
@@ -255,6 +356,69 @@ func TestGetRunner(t *testing.T) {
 	}
 }
 
+func TestTemplateRunner(t *testing.T) {
+	runner := NewTemplateRunner(config.RunnerConfig{
+		ID:                "opencode",
+		Command:           "opencode --yes --message {prompt} # {short_id} in {workdir}",
+		EnrichmentCommand: "opencode --message {prompt} --exit",
+	})
+	if runner.Name() != "opencode" || runner.Binary() != "opencode" {
+		t.Errorf("name/binary = %q/%q, want both defaulted to opencode", runner.Name(), runner.Binary())
+	}
+
+	opts := SpawnOpts{
+		WorkDir: "/tmp/it's here",
+		Task:    db.Task{ID: "abcdef1234567890", Title: "Fix it", Status: db.StatusPlanning},
+	}
+	cmd := runner.BuildCommand(opts)
+	if !strings.HasPrefix(cmd, "opencode --yes --message 'You are working on an agentboard task.") {
+		t.Errorf("command should start with the quoted prompt, got %q", cmd)
+	}
+	if !strings.HasSuffix(cmd, `# 'abcdef12' in '/tmp/it'"'"'s here'`) {
+		t.Errorf("placeholders not shell-quoted: %q", cmd)
+	}
+	if !strings.Contains(cmd, "STAGE: Planning") {
+		t.Error("command should carry the stage instructions")
+	}
+
+	enrich := runner.BuildEnrichmentCommand(opts)
+	if !strings.HasPrefix(enrich, "opencode --message 'Enrich task") || !strings.HasSuffix(enrich, " --exit") {
+		t.Errorf("enrichment command = %q", enrich)
+	}
+	if NewTemplateRunner(config.RunnerConfig{ID: "x", Command: "x {prompt}"}).BuildEnrichmentCommand(opts) != "" {
+		t.Error("runner without enrichment_command should not enrich")
+	}
+}
+
+func TestSetCustomRunners(t *testing.T) {
+	t.Cleanup(func() { SetCustomRunners(nil) })
+
+	SetCustomRunners([]config.RunnerConfig{
+		{ID: "mycli", Name: "My CLI", Command: "mycli {prompt}"},
+		{ID: "cursor", Name: "My Cursor", Command: "cursor-agent {prompt}"},
+	})
+	if r := GetRunner("mycli"); r == nil || r.Name() != "My CLI" {
+		t.Errorf("GetRunner(mycli) = %v, want the declared runner", r)
+	}
+	if r := GetRunner("cursor"); r == nil || r.Name() != "My Cursor" {
+		t.Errorf("GetRunner(cursor) = %v, want the built-in replaced", r)
+	}
+	if r := GetRunner("claude"); r == nil || r.Name() != "Claude Code" {
+		t.Errorf("GetRunner(claude) = %v, want the built-in kept", r)
+	}
+	if len(runners) != len(builtinRunners)+1 {
+		t.Errorf("got %d runners, want %d", len(runners), len(builtinRunners)+1)
+	}
+
+	SetCustomRunners(nil)
+	if GetRunner("mycli") != nil {
+		t.Error("declarations should be dropped when the config no longer has them")
+	}
+	if r := GetRunner("cursor"); r == nil || r.Name() != "Cursor" {
+		t.Error("built-in cursor should be restored")
+	}
+}
+
 func TestTaskSlug(t *testing.T) {
 	tests := []struct {
 		input string
@@ -296,21 +460,13 @@ func TestShellQuote(t *testing.T) {
 func TestDeactivateRalphLoop(t *testing.T) {
 	t.Run("file exists with active true", func(t *testing.T) {
 		dir := t.TempDir()
-		// DeactivateRalphLoop uses TaskSlug(task.Title) as base dir,
-		// so we need to chdir to the temp dir and use a matching task title.
-		origDir, _ := os.Getwd()
-		os.Chdir(dir)
-		defer os.Chdir(origDir)
-
-		slug := "test-task"
-		stateDir := filepath.Join(slug, ".claude")
+		stateDir := filepath.Join(dir, ".claude")
 		os.MkdirAll(stateDir, 0755)
 
 		content := "---\nactive: true\niteration: 3\nmax_iterations: 10\n---\n"
 		os.WriteFile(filepath.Join(stateDir, "ralph-loop.local.md"), []byte(content), 0644)
 
-		task := db.Task{Title: "Test Task"}
-		err := DeactivateRalphLoop(task)
+		err := DeactivateRalphLoop(dir)
 		if err != nil {
 			t.Fatalf("DeactivateRalphLoop() error = %v", err)
 		}
@@ -326,12 +482,8 @@ func TestDeactivateRalphLoop(t *testing.T) {
 
 	t.Run("file does not exist", func(t *testing.T) {
 		dir := t.TempDir()
-		origDir, _ := os.Getwd()
-		os.Chdir(dir)
-		defer os.Chdir(origDir)
 
-		task := db.Task{Title: "Nonexistent Task"}
-		err := DeactivateRalphLoop(task)
+		err := DeactivateRalphLoop(dir)
 		if err != nil {
 			t.Fatalf("DeactivateRalphLoop() should return nil for missing file, got %v", err)
 		}
@@ -339,19 +491,13 @@ func TestDeactivateRalphLoop(t *testing.T) {
 
 	t.Run("file exists with active false", func(t *testing.T) {
 		dir := t.TempDir()
-		origDir, _ := os.Getwd()
-		os.Chdir(dir)
-		defer os.Chdir(origDir)
-
-		slug := "already-inactive"
-		stateDir := filepath.Join(slug, ".claude")
+		stateDir := filepath.Join(dir, ".claude")
 		os.MkdirAll(stateDir, 0755)
 
 		content := "---\nactive: false\niteration: 5\n---\n"
 		os.WriteFile(filepath.Join(stateDir, "ralph-loop.local.md"), []byte(content), 0644)
 
-		task := db.Task{Title: "Already Inactive"}
-		err := DeactivateRalphLoop(task)
+		err := DeactivateRalphLoop(dir)
 		if err != nil {
 			t.Fatalf("DeactivateRalphLoop() error = %v", err)
 		}
@@ -363,3 +509,102 @@ func TestDeactivateRalphLoop(t *testing.T) {
 		}
 	})
 }
+
+// initGitRepo turns dir into a git repository with one commit.
+func initGitRepo(t *testing.T, dir string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+}
+
+func TestEnsureWorktree(t *testing.T) {
+	dir, _ := filepath.EvalSymlinks(t.TempDir())
+	initGitRepo(t, dir)
+	origDir, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(origDir)
+
+	os.WriteFile(".env", []byte("SECRET=1\n"), 0600)
+
+	cfg := config.Default()
+	cfg.Worktree.InitScript = "touch initialized"
+
+	task := db.Task{ID: "abcdef1234567890", Title: "Fix Login"}
+	path, branch, err := EnsureWorktree(task, cfg)
+	if err != nil {
+		t.Fatalf("EnsureWorktree: %v", err)
+	}
+	if want := filepath.Join(dir, ".agentboard", "worktrees", "fix-login"); path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+	if branch != "agentboard/fix-login" {
+		t.Errorf("branch = %q, want %q", branch, "agentboard/fix-login")
+	}
+	if _, err := os.Stat(filepath.Join(path, ".env")); err != nil {
+		t.Error("copy_files should copy .env into the worktree")
+	}
+	if _, err := os.Stat(filepath.Join(path, "initialized")); err != nil {
+		t.Error("init_script should run inside the worktree")
+	}
+
+	// After a rename the recorded branch still resolves to the same worktree.
+	task.Title = "Fix Login Flow"
+	task.BranchName = branch
+	again, _, err := EnsureWorktree(task, cfg)
+	if err != nil {
+		t.Fatalf("EnsureWorktree after rename: %v", err)
+	}
+	if again != path {
+		t.Errorf("renamed task got %q, want reused %q", again, path)
+	}
+	if found, _ := FindWorktree(task); found != path {
+		t.Errorf("FindWorktree = %q, want %q", found, path)
+	}
+
+	// A worktree deleted by hand is added again on its branch.
+	os.RemoveAll(path)
+	if found, _ := FindWorktree(task); found != "" {
+		t.Errorf("FindWorktree after rm = %q, want none", found)
+	}
+	path, againBranch, err := EnsureWorktree(task, cfg)
+	if err != nil {
+		t.Fatalf("EnsureWorktree after rm: %v", err)
+	}
+	if againBranch != branch {
+		t.Errorf("recreated worktree on %q, want %q", againBranch, branch)
+	}
+	if _, err := os.Stat(filepath.Join(path, "initialized")); err != nil {
+		t.Error("recreated worktree should be initialized again")
+	}
+
+	// A second task with the same title gets its own branch and directory.
+	other := db.Task{ID: "1234567890abcdef", Title: "Fix Login"}
+	otherPath, otherBranch, err := EnsureWorktree(other, cfg)
+	if err != nil {
+		t.Fatalf("EnsureWorktree for duplicate title: %v", err)
+	}
+	if otherPath == path || otherBranch == branch {
+		t.Errorf("duplicate title reused %q on %q", otherPath, otherBranch)
+	}
+}
+
+func TestEnsureWorktreeNotRepo(t *testing.T) {
+	origDir, _ := os.Getwd()
+	os.Chdir(t.TempDir())
+	defer os.Chdir(origDir)
+
+	task := db.Task{ID: "abcdef1234567890", Title: "Anything"}
+	if _, _, err := EnsureWorktree(task, config.Default()); err == nil ||
+		!strings.Contains(err.Error(), "not a git repository") {
+		t.Errorf("got %v, want not-a-git-repository error", err)
+	}
+}
@@ -0,0 +1,74 @@
+package agent
+
+import (
+	"os/exec"
+	"strings"
+
+	"github.com/markx3/agentboard/internal/config"
+)
+
+// TemplateRunner implements AgentRunner for a CLI declared under
+// [[agent.runners]] in config.toml.
+type TemplateRunner struct {
+	def config.RunnerConfig
+}
+
+// NewTemplateRunner wraps a validated runner declaration.
+func NewTemplateRunner(def config.RunnerConfig) *TemplateRunner {
+	return &TemplateRunner{def: def}
+}
+
+func (t *TemplateRunner) ID() string { return t.def.ID }
+
+func (t *TemplateRunner) Name() string {
+	if t.def.Name != "" {
+		return t.def.Name
+	}
+	return t.def.ID
+}
+
+func (t *TemplateRunner) Binary() string {
+	if t.def.Binary != "" {
+		return t.def.Binary
+	}
+	if fields := strings.Fields(t.def.Command); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+func (t *TemplateRunner) Available() bool {
+	path, err := exec.LookPath(t.Binary())
+	if err != nil {
+		return false
+	}
+	if len(t.def.VersionArgs) == 0 {
+		return true
+	}
+	return probeVersion(path, t.def.VersionArgs, t.def.VersionMatch)
+}
+
+// BuildCommand fills the command template with the single task prompt,
+// since custom CLIs have no system prompt slot.
+func (t *TemplateRunner) BuildCommand(opts SpawnOpts) string {
+	return expandTemplate(t.def.Command, buildTaskPrompt(opts), opts)
+}
+
+func (t *TemplateRunner) BuildEnrichmentCommand(opts SpawnOpts) string {
+	if t.def.EnrichmentCommand == "" {
+		return ""
+	}
+	return expandTemplate(t.def.EnrichmentCommand, enrichmentPrompt(opts.Task), opts)
+}
+
+// expandTemplate replaces the config.RunnerPlaceholders in tmpl with
+// shell-quoted values.
+func expandTemplate(tmpl, prompt string, opts SpawnOpts) string {
+	return strings.NewReplacer(
+		"{workdir}", shellQuote(opts.WorkDir),
+		"{prompt}", shellQuote(prompt),
+		"{task_id}", shellQuote(opts.Task.ID),
+		"{short_id}", shellQuote(opts.Task.ID[:8]),
+		"{title}", shellQuote(opts.Task.Title),
+	).Replace(tmpl)
+}
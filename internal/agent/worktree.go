@@ -0,0 +1,169 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/markx3/agentboard/internal/config"
+	"github.com/markx3/agentboard/internal/db"
+	"github.com/markx3/agentboard/internal/worktree"
+)
+
+// WorktreesDir is where task worktrees are created, relative to the project root.
+var WorktreesDir = filepath.Join(config.Dir, "worktrees")
+
+// TaskBranch returns the branch a task's worktree uses: the recorded
+// BranchName if set, otherwise agentboard/<slug>.
+func TaskBranch(task db.Task) string {
+	if task.BranchName != "" {
+		return task.BranchName
+	}
+	return worktree.BranchPrefix + TaskSlug(task.Title)
+}
+
+// FindWorktree returns the absolute path of the task's existing worktree,
+// or "" if it has none. Lookup is by branch so renamed tasks still resolve.
+// A worktree whose directory was deleted counts as none.
+func FindWorktree(task db.Task) (string, error) {
+	root, err := worktree.RepoRoot(config.ProjectRoot())
+	if err != nil {
+		return "", err
+	}
+	wt, err := worktree.FindByBranch(root, TaskBranch(task))
+	if err != nil || wt == nil || !dirExists(wt.Path) {
+		return "", err
+	}
+	return wt.Path, nil
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// EnsureWorktree creates or reuses the git worktree for task and returns its
+// absolute path and branch. New worktrees get the configured copy_files and
+// init_script; if the init script fails the worktree is removed again.
+func EnsureWorktree(task db.Task, cfg *config.Config) (string, string, error) {
+	project, err := filepath.Abs(config.ProjectRoot())
+	if err != nil {
+		return "", "", fmt.Errorf("resolving project dir: %w", err)
+	}
+	root, err := worktree.RepoRoot(project)
+	if err != nil {
+		if errors.Is(err, worktree.ErrNotRepo) {
+			return "", "", fmt.Errorf("agents run in git worktrees, but %s is not a git repository (run `git init` first)", project)
+		}
+		return "", "", err
+	}
+
+	branch := task.BranchName
+	if branch == "" {
+		branch = TaskBranch(task)
+		// Another task with the same title already owns this branch.
+		if worktree.BranchExists(root, branch) {
+			branch += "-" + task.ID[:8]
+		}
+	}
+
+	if wt, err := worktree.FindByBranch(root, branch); err != nil {
+		return "", "", err
+	} else if wt != nil {
+		if dirExists(wt.Path) {
+			return wt.Path, branch, nil
+		}
+		// Deleted by hand: drop git's record of it and add it again.
+		if err := worktree.Prune(root); err != nil {
+			return "", "", err
+		}
+	}
+
+	dir := newWorktreeDir(project, task)
+	createdBranch := !worktree.BranchExists(root, branch)
+	if err := worktree.Add(root, dir, branch); err != nil {
+		return "", "", fmt.Errorf("creating worktree: %w", err)
+	}
+
+	if err := initWorktree(project, dir, cfg.Worktree); err != nil {
+		_ = worktree.Remove(root, dir, true)
+		if createdBranch {
+			_ = worktree.DeleteBranch(root, branch, true)
+		}
+		return "", "", err
+	}
+	return dir, branch, nil
+}
+
+// WorktreeDir returns where the task's agent runs: its existing worktree,
+// or the directory EnsureWorktree would create for it.
+func WorktreeDir(task db.Task) (string, error) {
+	if dir, err := FindWorktree(task); err != nil || dir != "" {
+		return dir, err
+	}
+	project, err := filepath.Abs(config.ProjectRoot())
+	if err != nil {
+		return "", fmt.Errorf("resolving project dir: %w", err)
+	}
+	return newWorktreeDir(project, task), nil
+}
+
+// newWorktreeDir picks the directory for a new worktree, avoiding one a
+// task with the same title already uses.
+func newWorktreeDir(project string, task db.Task) string {
+	dir := filepath.Join(project, WorktreesDir, TaskSlug(task.Title))
+	if _, err := os.Stat(dir); err == nil {
+		dir += "-" + task.ID[:8]
+	}
+	return dir
+}
+
+// initWorktree copies the configured files into a fresh worktree and runs
+// the init script there.
+func initWorktree(project, dir string, cfg config.WorktreeConfig) error {
+	if err := copyWorktreeFiles(project, dir, cfg.CopyFiles); err != nil {
+		return fmt.Errorf("copying files into worktree: %w", err)
+	}
+	if strings.TrimSpace(cfg.InitScript) == "" {
+		return nil
+	}
+	cmd := exec.Command("sh", "-c", cfg.InitScript)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("init_script failed: %w\n%s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// copyWorktreeFiles copies each file from project into dir unless it already
+// exists there. Files missing from project are skipped.
+func copyWorktreeFiles(project, dir string, files []string) error {
+	for _, name := range files {
+		dst := filepath.Join(dir, name)
+		if _, err := os.Stat(dst); err == nil {
+			continue
+		}
+		src := filepath.Join(project, name)
+		info, err := os.Stat(src)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", name, err)
+		}
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", name, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return fmt.Errorf("creating directory for %s: %w", name, err)
+		}
+		if err := os.WriteFile(dst, data, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("copying %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// TokenEnv names the environment variable that overrides the token a client
+// sends, for servers that don't use GitHub authentication.
+const TokenEnv = "AGENTBOARD_TOKEN"
+
+// SecretEnv names the environment variable holding the shared secret for
+// the "secret" mode.
+const SecretEnv = "AGENTBOARD_SECRET"
+
+// Authentication modes accepted by New.
+const (
+	ModeGitHub = "github"
+	ModeSecret = "secret"
+	ModeTokens = "tokens"
+)
+
+// ErrInvalidToken is returned when a token is not accepted.
+var ErrInvalidToken = errors.New("invalid token")
+
+// Authenticator checks the token a peer presents when it connects and
+// returns the username the peer acts as.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (string, error)
+}
+
+// New returns the authenticator for mode. secret is used by the "secret"
+// mode and tokenFile by the "tokens" mode.
+func New(mode, secret, tokenFile string) (Authenticator, error) {
+	switch mode {
+	case "", ModeGitHub:
+		return GitHub{}, nil
+	case ModeSecret:
+		if secret == "" {
+			return nil, fmt.Errorf("%s auth needs a secret (set %s)", ModeSecret, SecretEnv)
+		}
+		return SharedSecret{Secret: secret}, nil
+	case ModeTokens:
+		if tokenFile == "" {
+			return nil, fmt.Errorf("%s auth needs a token file", ModeTokens)
+		}
+		return LoadTokenFile(tokenFile)
+	default:
+		return nil, fmt.Errorf("unknown auth mode %q (want %s, %s or %s)", mode, ModeGitHub, ModeSecret, ModeTokens)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
 	"os/exec"
 	"strings"
 	"sync"
@@ -76,11 +77,23 @@ func verifyToken(ctx context.Context, token string) (string, error) {
 	return user.Login, nil
 }
 
+// GitHub authenticates peers by their GitHub token; the username is the
+// token owner's login.
+type GitHub struct{}
+
+func (GitHub) Authenticate(ctx context.Context, token string) (string, error) {
+	return verifyToken(ctx, token)
+}
+
 func VerifyTokenString(ctx context.Context, token string) (string, error) {
 	return verifyToken(ctx, token)
 }
 
-// GetToken retrieves the GitHub auth token from `gh auth token`.
+// GetToken returns the token to present to a sync server: AGENTBOARD_TOKEN
+// if set, otherwise the GitHub token from `gh auth token`.
 func GetToken(ctx context.Context) (string, error) {
+	if token := os.Getenv(TokenEnv); token != "" {
+		return token, nil
+	}
 	return getToken(ctx)
 }
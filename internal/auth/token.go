@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// defaultPeerName is the username for peers that present the shared secret
+// without a name.
+const defaultPeerName = "peer"
+
+// SharedSecret accepts any peer that knows one secret shared by the team.
+// Peers present either the bare secret or "name:secret" to choose the
+// username they act as.
+type SharedSecret struct {
+	Secret string
+}
+
+func (s SharedSecret) Authenticate(_ context.Context, token string) (string, error) {
+	if s.Secret == "" {
+		return "", ErrInvalidToken
+	}
+	if secretEqual(token, s.Secret) {
+		return defaultPeerName, nil
+	}
+	name, secret, ok := strings.Cut(token, ":")
+	if !ok || name == "" || !secretEqual(secret, s.Secret) {
+		return "", ErrInvalidToken
+	}
+	return name, nil
+}
+
+// TokenList accepts a fixed set of per-user tokens.
+type TokenList struct {
+	// users maps each token to the username it belongs to.
+	users map[string]string
+}
+
+// NewTokenList builds a TokenList from a username to token map.
+func NewTokenList(tokens map[string]string) (*TokenList, error) {
+	users := make(map[string]string, len(tokens))
+	for user, token := range tokens {
+		if user == "" || token == "" {
+			return nil, fmt.Errorf("token list entries need a username and a token")
+		}
+		if other, ok := users[token]; ok {
+			return nil, fmt.Errorf("users %q and %q share a token", other, user)
+		}
+		users[token] = user
+	}
+	return &TokenList{users: users}, nil
+}
+
+// LoadTokenFile reads a token list with one "username token" pair per line.
+// Blank lines and lines starting with # are ignored.
+func LoadTokenFile(path string) (*TokenList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening token file: %w", err)
+	}
+	defer f.Close()
+
+	tokens := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: want \"username token\"", path, n)
+		}
+		if _, ok := tokens[fields[0]]; ok {
+			return nil, fmt.Errorf("%s:%d: duplicate user %q", path, n, fields[0])
+		}
+		tokens[fields[0]] = fields[1]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading token file: %w", err)
+	}
+	list, err := NewTokenList(tokens)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return list, nil
+}
+
+// Authenticate compares token against every entry so the time taken does
+// not reveal which one matched.
+func (l *TokenList) Authenticate(_ context.Context, token string) (string, error) {
+	var user string
+	for t, u := range l.users {
+		if secretEqual(token, t) {
+			user = u
+		}
+	}
+	if user == "" {
+		return "", ErrInvalidToken
+	}
+	return user, nil
+}
+
+func secretEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
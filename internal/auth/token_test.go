@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSharedSecret(t *testing.T) {
+	a := SharedSecret{Secret: "s3cret"}
+	tests := []struct {
+		token   string
+		want    string
+		wantErr bool
+	}{
+		{"s3cret", "peer", false},
+		{"alice:s3cret", "alice", false},
+		{"alice:wrong", "", true},
+		{":s3cret", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := a.Authenticate(context.Background(), tt.token)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Authenticate(%q) = %q, %v; want %q, err=%v", tt.token, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestLoadTokenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens")
+	data := "# team tokens\nalice tok-a\n\nbob   tok-b\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	list, err := LoadTokenFile(path)
+	if err != nil {
+		t.Fatalf("LoadTokenFile: %v", err)
+	}
+	ctx := context.Background()
+	if user, err := list.Authenticate(ctx, "tok-b"); err != nil || user != "bob" {
+		t.Errorf("Authenticate(tok-b) = %q, %v; want bob", user, err)
+	}
+	if _, err := list.Authenticate(ctx, "tok-c"); err != ErrInvalidToken {
+		t.Errorf("Authenticate(tok-c) error = %v, want ErrInvalidToken", err)
+	}
+
+	os.WriteFile(path, []byte("alice tok\nbob tok\n"), 0o600)
+	if _, err := LoadTokenFile(path); err == nil {
+		t.Error("LoadTokenFile accepted a token shared by two users")
+	}
+	os.WriteFile(path, []byte("alice\n"), 0o600)
+	if _, err := LoadTokenFile(path); err == nil {
+		t.Error("LoadTokenFile accepted a line without a token")
+	}
+}
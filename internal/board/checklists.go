@@ -0,0 +1,83 @@
+package board
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/markx3/agentboard/internal/db"
+)
+
+// maxChecklistText matches the CHECK constraint on checklist_items.text.
+const maxChecklistText = 500
+
+// checklistMark renders an item the way history records it.
+func checklistMark(text string, done bool) string {
+	if done {
+		return "[x] " + text
+	}
+	return "[ ] " + text
+}
+
+// AddChecklistItem appends an unchecked acceptance criterion to a task.
+func (s *LocalService) AddChecklistItem(ctx context.Context, taskID, text string) (*db.ChecklistItem, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("checklist item text is required")
+	}
+	if utf8.RuneCountInString(text) > maxChecklistText {
+		return nil, fmt.Errorf("checklist item is longer than %d characters", maxChecklistText)
+	}
+	if _, err := s.db.GetTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+	item, err := s.db.AddChecklistItem(ctx, taskID, text)
+	if err != nil {
+		return nil, err
+	}
+	s.record(ctx, db.TaskEvent{TaskID: taskID, Field: "checklist", NewValue: checklistMark(text, false)})
+	return item, nil
+}
+
+// SetChecklistItemDone ticks or unticks one of a task's checklist items.
+// Setting an item to the state it is already in is a no-op.
+func (s *LocalService) SetChecklistItemDone(ctx context.Context, taskID string, itemID int64, done bool) error {
+	item, err := s.checklistItem(ctx, taskID, itemID)
+	if err != nil {
+		return err
+	}
+	if item.Done == done {
+		return nil
+	}
+	if err := s.db.SetChecklistItemDone(ctx, taskID, itemID, done); err != nil {
+		return err
+	}
+	s.record(ctx, db.TaskEvent{TaskID: taskID, Field: "checklist", NewValue: checklistMark(item.Text, done)})
+	return nil
+}
+
+func (s *LocalService) RemoveChecklistItem(ctx context.Context, taskID string, itemID int64) error {
+	item, err := s.checklistItem(ctx, taskID, itemID)
+	if err != nil {
+		return err
+	}
+	if err := s.db.RemoveChecklistItem(ctx, taskID, itemID); err != nil {
+		return err
+	}
+	s.record(ctx, db.TaskEvent{TaskID: taskID, Field: "checklist", OldValue: item.Text})
+	return nil
+}
+
+func (s *LocalService) checklistItem(ctx context.Context, taskID string, itemID int64) (db.ChecklistItem, error) {
+	task, err := s.db.GetTask(ctx, taskID)
+	if err != nil {
+		return db.ChecklistItem{}, err
+	}
+	for _, item := range task.Checklist {
+		if item.ID == itemID {
+			return item, nil
+		}
+	}
+	return db.ChecklistItem{}, fmt.Errorf("checklist item %d not found", itemID)
+}
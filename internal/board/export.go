@@ -0,0 +1,83 @@
+package board
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/markx3/agentboard/internal/db"
+)
+
+// Export snapshots the whole board through svc, so a remote board can be
+// exported as well as a local one.
+func Export(ctx context.Context, svc Service) (*db.Snapshot, error) {
+	snap := &db.Snapshot{
+		Version:     db.SnapshotVersion,
+		ExportedAt:  time.Now().UTC(),
+		Labels:      []db.Label{},
+		Tasks:       []db.Task{},
+		Comments:    []db.Comment{},
+		Suggestions: []db.Suggestion{},
+	}
+
+	labels, err := svc.ListLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snap.Labels = append(snap.Labels, labels...)
+
+	tasks, err := svc.ListTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deps, err := svc.ListAllDependencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		task.BlockedBy = slices.Sorted(slices.Values(deps[task.ID]))
+		snap.Tasks = append(snap.Tasks, task)
+
+		comments, err := svc.ListComments(ctx, task.ID)
+		if err != nil {
+			return nil, err
+		}
+		snap.Comments = append(snap.Comments, comments...)
+	}
+
+	for _, status := range []db.SuggestionStatus{db.SuggestionPending, db.SuggestionAccepted, db.SuggestionDismissed} {
+		suggestions, err := svc.ListSuggestions(ctx, status)
+		if err != nil {
+			return nil, err
+		}
+		snap.Suggestions = append(snap.Suggestions, suggestions...)
+	}
+	return snap, nil
+}
+
+// Import loads a snapshot written by Export. Not part of Service: it
+// writes straight to the database in one transaction, so it only runs
+// against a local board.
+// Tasks without a status, or in a column this board doesn't have, go to
+// the first column, and every task created or replaced gets a "created"
+// event.
+func (s *LocalService) Import(ctx context.Context, snap *db.Snapshot, opts db.ImportOptions) (*db.ImportResult, error) {
+	if opts.DefaultStatus == "" {
+		opts.DefaultStatus = s.workflow.First()
+	}
+	if opts.ValidStatus == nil {
+		opts.ValidStatus = s.workflow.Valid
+	}
+	res, err := s.db.Import(ctx, snap, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range res.Imported {
+		task, err := s.db.GetTask(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		s.record(ctx, db.TaskEvent{TaskID: id, Field: "created", NewValue: task.Title})
+	}
+	return res, nil
+}
@@ -0,0 +1,211 @@
+package board
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/markx3/agentboard/internal/db"
+)
+
+// GitHubIssue is one issue as printed by
+// `gh issue list --json number,title,body,labels,assignees`.
+type GitHubIssue struct {
+	Number    int           `json:"number"`
+	Title     string        `json:"title"`
+	Body      string        `json:"body"`
+	Labels    []GitHubLabel `json:"labels"`
+	Assignees []GitHubUser  `json:"assignees"`
+}
+
+type GitHubLabel struct {
+	Name string `json:"name"`
+	// Color is a hex color without the leading '#'.
+	Color string `json:"color"`
+}
+
+type GitHubUser struct {
+	Login string `json:"login"`
+}
+
+// What ImportGitHubIssues did with an issue.
+const (
+	IssueCreated   = "created"
+	IssueUpdated   = "updated"
+	IssueUnchanged = "unchanged"
+	IssueSkipped   = "skipped"
+)
+
+// IssueImport reports the task an issue was imported into.
+type IssueImport struct {
+	Repo   string `json:"repo,omitempty"`
+	Number int    `json:"number"`
+	TaskID string `json:"task_id"`
+	Title  string `json:"title"`
+	Action string `json:"action"`
+}
+
+// maxTaskTitle matches the CHECK constraint on tasks.title.
+const maxTaskTitle = 500
+
+// issueKey identifies an issue across repositories. GitHub compares
+// repository names case-insensitively.
+type issueKey struct {
+	repo   string
+	number int
+}
+
+func newIssueKey(repo string, number int) issueKey {
+	return issueKey{strings.ToLower(repo), number}
+}
+
+// ImportGitHubIssues creates a task for every issue of repo (OWNER/REPO,
+// or "" when unknown) not yet on the board, linked to it by repository
+// and issue number. Issues already imported are skipped, or with update
+// have their task's title, description, assignee and labels brought up to
+// date. Labels are only ever added, so labels put on the task by hand
+// survive an update. Tasks imported before the repository was recorded
+// are claimed by the first repository that imports their number. With no
+// repo, issues match tasks by number alone.
+func (s *LocalService) ImportGitHubIssues(ctx context.Context, repo string, issues []GitHubIssue, update bool) ([]IssueImport, error) {
+	tasks, err := s.db.ListTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	linked := make(map[issueKey]string)
+	unowned := make(map[int]string)
+	byNumber := make(map[int][]string)
+	for _, t := range tasks {
+		if t.IssueNumber <= 0 {
+			continue
+		}
+		linked[newIssueKey(t.IssueRepo, t.IssueNumber)] = t.ID
+		byNumber[t.IssueNumber] = append(byNumber[t.IssueNumber], t.ID)
+		if t.IssueRepo == "" {
+			unowned[t.IssueNumber] = t.ID
+		}
+	}
+
+	results := make([]IssueImport, 0, len(issues))
+	for _, issue := range issues {
+		if issue.Number <= 0 {
+			return results, fmt.Errorf("issue without a number: %q", issue.Title)
+		}
+		title := strings.TrimSpace(issue.Title)
+		if title == "" {
+			title = fmt.Sprintf("Issue #%d", issue.Number)
+		}
+		if utf8.RuneCountInString(title) > maxTaskTitle {
+			title = string([]rune(title)[:maxTaskTitle])
+		}
+		res := IssueImport{Repo: repo, Number: issue.Number, Title: title}
+		assignee := ""
+		if len(issue.Assignees) > 0 {
+			assignee = issue.Assignees[0].Login
+		}
+
+		key := newIssueKey(repo, issue.Number)
+		id, ok := linked[key]
+		switch {
+		case ok:
+		case repo == "" && len(byNumber[issue.Number]) > 1:
+			return results, fmt.Errorf("issue #%d is linked to tasks from several repositories; name its repository", issue.Number)
+		case repo == "" && len(byNumber[issue.Number]) == 1:
+			id, ok = byNumber[issue.Number][0], true
+		default:
+			id, ok = unowned[issue.Number]
+		}
+		if !ok {
+			// Everything that links the task to its issue goes into the
+			// insert, so a failure later on can't leave an unlinked task
+			// for the next run to duplicate.
+			task := &db.Task{
+				Title:            title,
+				Description:      issue.Body,
+				Status:           s.workflow.First(),
+				Assignee:         assignee,
+				AgentStatus:      db.AgentIdle,
+				EnrichmentStatus: db.EnrichmentSkipped,
+				IssueNumber:      issue.Number,
+				IssueRepo:        repo,
+			}
+			if err := s.db.CreateTaskFrom(ctx, task); err != nil {
+				return results, fmt.Errorf("importing issue #%d: %w", issue.Number, err)
+			}
+			s.record(ctx, db.TaskEvent{TaskID: task.ID, Field: "created", NewValue: task.Title})
+			id = task.ID
+			linked[key] = id
+			byNumber[issue.Number] = append(byNumber[issue.Number], id)
+			res.Action = IssueCreated
+		} else {
+			delete(unowned, issue.Number)
+			linked[key] = id
+			res.Action = IssueUnchanged
+			if !update {
+				res.Action = IssueSkipped
+			}
+		}
+		res.TaskID = id
+
+		task, err := s.db.GetTask(ctx, id)
+		if err != nil {
+			return results, err
+		}
+		next := *task
+		if repo != "" && !strings.EqualFold(next.IssueRepo, repo) {
+			next.IssueRepo = repo
+		}
+		if update {
+			next.Title = title
+			next.Description = issue.Body
+			if assignee != "" {
+				next.Assignee = assignee
+			}
+		}
+		if next.Title != task.Title || next.Description != task.Description ||
+			next.IssueRepo != task.IssueRepo || next.Assignee != task.Assignee {
+			if err := s.UpdateTask(ctx, &next); err != nil {
+				return results, fmt.Errorf("importing issue #%d: %w", issue.Number, err)
+			}
+			if res.Action == IssueUnchanged {
+				res.Action = IssueUpdated
+			}
+		}
+		if res.Action == IssueSkipped {
+			results = append(results, res)
+			continue
+		}
+
+		for _, l := range issue.Labels {
+			name, ok := githubLabelName(l.Name)
+			if !ok || task.HasLabel(name) {
+				continue
+			}
+			color := "#" + strings.ToLower(l.Color)
+			if !labelColorPattern.MatchString(color) {
+				color = ""
+			}
+			if err := s.AddLabel(ctx, id, name, color); err != nil {
+				return results, fmt.Errorf("labeling issue #%d: %w", issue.Number, err)
+			}
+			if res.Action == IssueUnchanged {
+				res.Action = IssueUpdated
+			}
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// githubLabelName turns a GitHub label such as "good first issue" into a
+// board label ("good-first-issue"). It reports false for labels that
+// can't be made valid.
+func githubLabelName(name string) (string, bool) {
+	name = strings.Join(strings.Fields(strings.ToLower(name)), "-")
+	if utf8.RuneCountInString(name) > 32 {
+		name = string([]rune(name)[:32])
+	}
+	name, err := NormalizeLabel(name)
+	return name, err == nil
+}
@@ -0,0 +1,109 @@
+package board
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/markx3/agentboard/internal/db"
+)
+
+// ActorEnv names the variable agents and scripts set to have their changes
+// attributed to them in task history.
+const ActorEnv = "AGENTBOARD_ACTOR"
+
+// DefaultActor is recorded when nothing else identifies who made a change.
+const DefaultActor = "local"
+
+type actorKey struct{}
+
+// WithActor returns a context whose changes are recorded as made by actor.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns who is making changes under ctx: the actor set with
+// WithActor, else $AGENTBOARD_ACTOR, else DefaultActor.
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	if actor := os.Getenv(ActorEnv); actor != "" {
+		return actor
+	}
+	return DefaultActor
+}
+
+// diffTask returns one event per recorded field that differs between
+// before and after. Position and agent activity change too often to be
+// worth keeping.
+func diffTask(before, after *db.Task) []db.TaskEvent {
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"title", before.Title, after.Title},
+		{"description", before.Description, after.Description},
+		{"status", string(before.Status), string(after.Status)},
+		{"assignee", before.Assignee, after.Assignee},
+		{"branch", before.BranchName, after.BranchName},
+		{"pr_url", before.PRUrl, after.PRUrl},
+		{"pr_number", prNumber(before.PRNumber), prNumber(after.PRNumber)},
+		{"issue_number", prNumber(before.IssueNumber), prNumber(after.IssueNumber)},
+		{"issue_repo", before.IssueRepo, after.IssueRepo},
+		{"priority", string(before.Priority), string(after.Priority)},
+		{"due_date", before.DueDate, after.DueDate},
+		{"agent_name", before.AgentName, after.AgentName},
+		{"agent_status", string(before.AgentStatus), string(after.AgentStatus)},
+		{"enrichment_status", string(before.EnrichmentStatus), string(after.EnrichmentStatus)},
+		{"skip_permissions", strconv.FormatBool(before.SkipPermissions), strconv.FormatBool(after.SkipPermissions)},
+		{"reset_requested", strconv.FormatBool(before.ResetRequested), strconv.FormatBool(after.ResetRequested)},
+	}
+	var events []db.TaskEvent
+	for _, f := range fields {
+		if f.old != f.new {
+			events = append(events, db.TaskEvent{
+				TaskID:   after.ID,
+				Field:    f.name,
+				OldValue: f.old,
+				NewValue: f.new,
+			})
+		}
+	}
+	return events
+}
+
+func prNumber(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// record stores events under the context's actor. History is best effort:
+// the change it describes has already been made, so a failure is logged
+// rather than returned.
+func (s *LocalService) record(ctx context.Context, events ...db.TaskEvent) {
+	if len(events) == 0 {
+		return
+	}
+	actor := ActorFrom(ctx)
+	for i := range events {
+		events[i].Actor = actor
+	}
+	if err := s.db.AddTaskEvents(ctx, events); err != nil {
+		log.Printf("warning: recording history for task %s: %v", shortID(events[0].TaskID), err)
+	}
+}
+
+// recordChange records the difference between the task as it was and as
+// it is now in the database.
+func (s *LocalService) recordChange(ctx context.Context, before *db.Task) {
+	after, err := s.db.GetTask(ctx, before.ID)
+	if err != nil {
+		log.Printf("warning: recording history for task %s: %v", shortID(before.ID), err)
+		return
+	}
+	s.record(ctx, diffTask(before, after)...)
+}
@@ -0,0 +1,87 @@
+package board
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+
+	"github.com/markx3/agentboard/internal/db"
+)
+
+var (
+	labelNamePattern  = regexp.MustCompile(`^[a-z0-9][a-z0-9_./-]{0,31}$`)
+	labelColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+// labelPalette colors new labels that weren't given one. A name always
+// maps to the same color, so peers creating the same label agree.
+var labelPalette = []string{
+	"#ff5555", "#ffb86c", "#f1fa8c", "#50fa7b",
+	"#8be9fd", "#bd93f9", "#ff79c6", "#6272a4",
+}
+
+// NormalizeLabel lowercases a label name and checks it is usable: up to
+// 32 letters, digits, '_', '.', '/' or '-', starting with a letter or digit.
+func NormalizeLabel(name string) (string, error) {
+	n := strings.ToLower(strings.TrimSpace(name))
+	if !labelNamePattern.MatchString(n) {
+		return "", fmt.Errorf("invalid label %q: use up to 32 letters, digits, '_', '.', '/' or '-'", name)
+	}
+	return n, nil
+}
+
+func defaultLabelColor(name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return labelPalette[h.Sum32()%uint32(len(labelPalette))]
+}
+
+// AddLabel tags a task, creating the label if needed. An empty color keeps
+// the label's current color, or picks one for a new label.
+func (s *LocalService) AddLabel(ctx context.Context, taskID, name, color string) error {
+	name, err := NormalizeLabel(name)
+	if err != nil {
+		return err
+	}
+	if color != "" && !labelColorPattern.MatchString(color) {
+		return fmt.Errorf("invalid label color %q: use #rrggbb", color)
+	}
+	task, err := s.db.GetTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if color != "" {
+		err = s.db.SetLabelColor(ctx, name, strings.ToLower(color))
+	} else {
+		err = s.db.EnsureLabel(ctx, name, defaultLabelColor(name))
+	}
+	if err != nil {
+		return err
+	}
+	if task.HasLabel(name) {
+		return nil
+	}
+	if err := s.db.AddTaskLabel(ctx, taskID, name); err != nil {
+		return err
+	}
+	s.record(ctx, db.TaskEvent{TaskID: taskID, Field: "labels", NewValue: name})
+	return nil
+}
+
+func (s *LocalService) RemoveLabel(ctx context.Context, taskID, name string) error {
+	name, err := NormalizeLabel(name)
+	if err != nil {
+		return err
+	}
+	if err := s.db.RemoveTaskLabel(ctx, taskID, name); err != nil {
+		return err
+	}
+	s.record(ctx, db.TaskEvent{TaskID: taskID, Field: "labels", OldValue: name})
+	return nil
+}
+
+func (s *LocalService) ListLabels(ctx context.Context) ([]db.Label, error) {
+	return s.db.ListLabels(ctx)
+}
@@ -5,14 +5,44 @@ import (
 	"fmt"
 
 	"github.com/markx3/agentboard/internal/db"
+	"github.com/markx3/agentboard/internal/workflow"
 )
 
 type LocalService struct {
-	db *db.DB
+	db       *db.DB
+	workflow *workflow.Workflow
 }
 
-func NewLocalService(database *db.DB) *LocalService {
-	return &LocalService{db: database}
+// LocalOption configures a LocalService.
+type LocalOption func(*LocalService)
+
+// WithWorkflow sets the columns tasks can be in. Without it the built-in
+// workflow is used.
+func WithWorkflow(wf *workflow.Workflow) LocalOption {
+	return func(s *LocalService) {
+		s.workflow = wf
+	}
+}
+
+func NewLocalService(database *db.DB, opts ...LocalOption) *LocalService {
+	s := &LocalService{db: database, workflow: workflow.Default()}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Workflow returns the columns the service accepts.
+func (s *LocalService) Workflow() *workflow.Workflow {
+	return s.workflow
+}
+
+// checkStatus rejects statuses that aren't columns of the workflow.
+func (s *LocalService) checkStatus(status db.TaskStatus) error {
+	if !s.workflow.Valid(status) {
+		return fmt.Errorf("invalid status %q (use: %s)", status, s.workflow.ValidList())
+	}
+	return nil
 }
 
 func (s *LocalService) ListTasks(ctx context.Context) ([]db.Task, error) {
@@ -28,19 +58,79 @@ func (s *LocalService) GetTask(ctx context.Context, id string) (*db.Task, error)
 }
 
 func (s *LocalService) CreateTask(ctx context.Context, title, description string) (*db.Task, error) {
-	return s.db.CreateTask(ctx, title, description)
+	task, err := s.db.CreateTaskIn(ctx, s.workflow.First(), title, description)
+	if err != nil {
+		return nil, err
+	}
+	s.record(ctx, db.TaskEvent{TaskID: task.ID, Field: "created", NewValue: task.Title})
+	return task, nil
 }
 
+// UpdateTask only checks the status when it changes, so tasks left in a
+// column that was removed from the config can still be edited.
 func (s *LocalService) UpdateTask(ctx context.Context, task *db.Task) error {
-	return s.db.UpdateTask(ctx, task)
+	current, err := s.db.GetTask(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkTransition(ctx, current.Status, task); err != nil {
+		return err
+	}
+	if err := s.db.UpdateTask(ctx, task); err != nil {
+		return err
+	}
+	s.recordChange(ctx, current)
+	return nil
 }
 
+// UpdateTaskFields checks a status change against the values the task will
+// have after the update, so a PR URL can be set in the same call that moves
+// the task to a column requiring it. The task goes to the end of its new
+// column.
 func (s *LocalService) UpdateTaskFields(ctx context.Context, id string, fields db.TaskFieldUpdate) error {
-	return s.db.UpdateTaskFields(ctx, id, fields)
+	current, err := s.db.GetTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	if fields.Status == nil {
+		if err := s.db.UpdateTaskFields(ctx, id, fields); err != nil {
+			return err
+		}
+		s.recordChange(ctx, current)
+		return nil
+	}
+	next := applyFields(*current, fields)
+	if err := s.checkTransition(ctx, current.Status, &next); err != nil {
+		return err
+	}
+	if err := s.db.UpdateTaskFields(ctx, id, fields); err != nil {
+		return err
+	}
+	s.recordChange(ctx, current)
+	return nil
 }
 
 func (s *LocalService) MoveTask(ctx context.Context, id string, newStatus db.TaskStatus) error {
-	return s.db.MoveTask(ctx, id, newStatus)
+	current, err := s.db.GetTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	next := *current
+	next.Status = newStatus
+	if err := s.checkTransition(ctx, current.Status, &next); err != nil {
+		return err
+	}
+	if err := s.db.MoveTask(ctx, id, newStatus); err != nil {
+		return err
+	}
+	s.recordChange(ctx, current)
+	return nil
+}
+
+// ReorderTask places a task just before beforeID or, when that is empty,
+// just after afterID, in the same column. Order isn't kept in history.
+func (s *LocalService) ReorderTask(ctx context.Context, id, beforeID, afterID string) error {
+	return s.db.ReorderTask(ctx, id, beforeID, afterID)
 }
 
 func (s *LocalService) DeleteTask(ctx context.Context, id string) error {
@@ -55,14 +145,25 @@ func (s *LocalService) ClaimTask(ctx context.Context, id, assignee string) error
 	if task.Assignee != "" {
 		return fmt.Errorf("task already claimed by %s", task.Assignee)
 	}
+	before := *task
 	task.Assignee = assignee
-	task.Status = db.StatusBrainstorm
-	pos, err := s.db.NextPosition(ctx, db.StatusBrainstorm)
-	if err != nil {
+	// Claimed work leaves the first column; tasks further along stay put.
+	if task.Status == s.workflow.First() {
+		task.Status = s.workflow.Next(task.Status)
+		if err := s.checkTransition(ctx, before.Status, task); err != nil {
+			return err
+		}
+		pos, err := s.db.NextPosition(ctx, task.Status)
+		if err != nil {
+			return err
+		}
+		task.Position = pos
+	}
+	if err := s.db.UpdateTask(ctx, task); err != nil {
 		return err
 	}
-	task.Position = pos
-	return s.db.UpdateTask(ctx, task)
+	s.record(ctx, diffTask(&before, task)...)
+	return nil
 }
 
 func (s *LocalService) UnclaimTask(ctx context.Context, id string) error {
@@ -70,17 +171,22 @@ func (s *LocalService) UnclaimTask(ctx context.Context, id string) error {
 	if err != nil {
 		return err
 	}
+	before := *task
 	task.Assignee = ""
 	task.AgentName = ""
 	task.AgentStatus = db.AgentIdle
 	task.BranchName = ""
-	task.Status = db.StatusBacklog
-	pos, err := s.db.NextPosition(ctx, db.StatusBacklog)
+	task.Status = s.workflow.First()
+	pos, err := s.db.NextPosition(ctx, task.Status)
 	if err != nil {
 		return err
 	}
 	task.Position = pos
-	return s.db.UpdateTask(ctx, task)
+	if err := s.db.UpdateTask(ctx, task); err != nil {
+		return err
+	}
+	s.record(ctx, diffTask(&before, task)...)
+	return nil
 }
 
 func (s *LocalService) UpdateAgentActivity(ctx context.Context, id, activity string) error {
@@ -90,13 +196,30 @@ func (s *LocalService) UpdateAgentActivity(ctx context.Context, id, activity str
 // Comments
 
 func (s *LocalService) AddComment(ctx context.Context, taskID, author, body string) (*db.Comment, error) {
-	return s.db.AddComment(ctx, taskID, author, body)
+	c, err := s.db.AddComment(ctx, taskID, author, body)
+	if err != nil {
+		return nil, err
+	}
+	s.record(ctx, db.TaskEvent{TaskID: taskID, Field: "comment", NewValue: body})
+	return c, nil
 }
 
 func (s *LocalService) ListComments(ctx context.Context, taskID string) ([]db.Comment, error) {
 	return s.db.ListComments(ctx, taskID)
 }
 
+// History
+
+func (s *LocalService) ListTaskEvents(ctx context.Context, taskID string) ([]db.TaskEvent, error) {
+	return s.db.ListTaskEvents(ctx, taskID)
+}
+
+// Search
+
+func (s *LocalService) Search(ctx context.Context, query string, limit int) ([]db.SearchHit, error) {
+	return s.db.Search(ctx, query, limit)
+}
+
 // Dependencies - uses depends_on naming, includes cycle check
 
 func (s *LocalService) AddDependency(ctx context.Context, taskID, dependsOn string) error {
@@ -107,11 +230,19 @@ func (s *LocalService) AddDependency(ctx context.Context, taskID, dependsOn stri
 	if hasCycle {
 		return fmt.Errorf("adding this dependency would create a cycle")
 	}
-	return s.db.AddDependency(ctx, taskID, dependsOn)
+	if err := s.db.AddDependency(ctx, taskID, dependsOn); err != nil {
+		return err
+	}
+	s.record(ctx, db.TaskEvent{TaskID: taskID, Field: "blocked_by", NewValue: dependsOn})
+	return nil
 }
 
 func (s *LocalService) RemoveDependency(ctx context.Context, taskID, dependsOn string) error {
-	return s.db.RemoveDependency(ctx, taskID, dependsOn)
+	if err := s.db.RemoveDependency(ctx, taskID, dependsOn); err != nil {
+		return err
+	}
+	s.record(ctx, db.TaskEvent{TaskID: taskID, Field: "blocked_by", OldValue: dependsOn})
+	return nil
 }
 
 func (s *LocalService) ListDependencies(ctx context.Context, taskID string) ([]string, error) {
@@ -149,7 +280,7 @@ func (s *LocalService) AcceptSuggestion(ctx context.Context, id string) error {
 		return fmt.Errorf("suggestion is not pending (status: %s)", sug.Status)
 	}
 	if sug.Type == db.SuggestionProposal {
-		task, err := s.db.CreateTask(ctx, sug.Title, sug.Message)
+		task, err := s.db.CreateTaskIn(ctx, s.workflow.First(), sug.Title, sug.Message)
 		if err != nil {
 			return fmt.Errorf("creating task from proposal: %w", err)
 		}
@@ -159,6 +290,7 @@ func (s *LocalService) AcceptSuggestion(ctx context.Context, id string) error {
 		}); err != nil {
 			return fmt.Errorf("setting enrichment on proposed task: %w", err)
 		}
+		s.record(ctx, db.TaskEvent{TaskID: task.ID, Field: "created", NewValue: task.Title})
 	}
 	return s.db.UpdateSuggestionStatus(ctx, id, db.SuggestionAccepted)
 }
@@ -166,3 +298,18 @@ func (s *LocalService) AcceptSuggestion(ctx context.Context, id string) error {
 func (s *LocalService) DismissSuggestion(ctx context.Context, id string) error {
 	return s.db.UpdateSuggestionStatus(ctx, id, db.SuggestionDismissed)
 }
+
+// Sync event log. Not part of Service: only a server backed by a local
+// database keeps one.
+
+func (s *LocalService) AppendEvent(ctx context.Context, ev db.Event) error {
+	return s.db.AppendEvent(ctx, ev)
+}
+
+func (s *LocalService) EventsSince(ctx context.Context, seq int64) ([]db.Event, error) {
+	return s.db.EventsSince(ctx, seq)
+}
+
+func (s *LocalService) LatestEventSeq(ctx context.Context) (int64, error) {
+	return s.db.LatestEventSeq(ctx)
+}
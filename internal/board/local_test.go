@@ -2,12 +2,15 @@ package board_test
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/markx3/agentboard/internal/board"
 	"github.com/markx3/agentboard/internal/db"
+	"github.com/markx3/agentboard/internal/workflow"
 )
 
 func setupTestService(t *testing.T) board.Service {
@@ -47,6 +50,23 @@ func TestClaimTask(t *testing.T) {
 	}
 }
 
+func TestClaimKeepsLaterColumn(t *testing.T) {
+	svc := setupTestService(t)
+	ctx := context.Background()
+
+	task, _ := svc.CreateTask(ctx, "Finished", "")
+	if err := svc.MoveTask(ctx, task.ID, db.StatusDone); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.ClaimTask(ctx, task.ID, "alice"); err != nil {
+		t.Fatalf("claiming task: %v", err)
+	}
+	got, _ := svc.GetTask(ctx, task.ID)
+	if got.Assignee != "alice" || got.Status != db.StatusDone {
+		t.Errorf("got %q in %q, want alice in done", got.Assignee, got.Status)
+	}
+}
+
 func TestClaimAlreadyClaimed(t *testing.T) {
 	svc := setupTestService(t)
 	ctx := context.Background()
@@ -260,3 +280,446 @@ func TestMoveTaskService(t *testing.T) {
 		}
 	}
 }
+
+func TestCustomWorkflow(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("opening test db: %v", err)
+	}
+	defer database.Close()
+	wf, err := workflow.New([]workflow.Column{{Status: "todo"}, {Status: "doing"}, {Status: "qa"}, {Status: "deployed"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := board.NewLocalService(database, board.WithWorkflow(wf))
+	ctx := context.Background()
+
+	task, err := svc.CreateTask(ctx, "Custom", "")
+	if err != nil {
+		t.Fatalf("creating task: %v", err)
+	}
+	if task.Status != "todo" {
+		t.Errorf("new task in %q, want todo", task.Status)
+	}
+
+	if err := svc.ClaimTask(ctx, task.ID, "alice"); err != nil {
+		t.Fatalf("claiming task: %v", err)
+	}
+	got, _ := svc.GetTask(ctx, task.ID)
+	if got.Status != "doing" {
+		t.Errorf("claimed task in %q, want doing", got.Status)
+	}
+
+	if err := svc.MoveTask(ctx, task.ID, "qa"); err != nil {
+		t.Errorf("moving to qa: %v", err)
+	}
+	if err := svc.MoveTask(ctx, task.ID, db.StatusReview); err == nil {
+		t.Error("moving to a column outside the workflow should fail")
+	}
+	review := db.StatusReview
+	if err := svc.UpdateTaskFields(ctx, task.ID, db.TaskFieldUpdate{Status: &review}); err == nil {
+		t.Error("updating to a column outside the workflow should fail")
+	}
+}
+
+func TestRemovedColumnStaysEditable(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	database, err := db.Open(dbPath)
+	if err != nil {
+		t.Fatalf("opening test db: %v", err)
+	}
+	ctx := context.Background()
+	task, err := board.NewLocalService(database).CreateTask(ctx, "Orphan", "")
+	if err != nil {
+		t.Fatalf("creating task: %v", err)
+	}
+	database.Close()
+
+	// Reopen the board with the task's column gone from the workflow.
+	database, err = db.Open(dbPath)
+	if err != nil {
+		t.Fatalf("reopening test db: %v", err)
+	}
+	defer database.Close()
+	wf, err := workflow.New([]workflow.Column{{Status: db.StatusPlanning}, {Status: db.StatusDone}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := board.NewLocalService(database, board.WithWorkflow(wf))
+
+	got, _ := svc.GetTask(ctx, task.ID)
+	got.Title = "Orphan, edited"
+	got.AgentStatus = db.AgentActive
+	if err := svc.UpdateTask(ctx, got); err != nil {
+		t.Fatalf("editing a task in a removed column: %v", err)
+	}
+	title := "Orphan, edited again"
+	if err := svc.UpdateTaskFields(ctx, task.ID, db.TaskFieldUpdate{Title: &title}); err != nil {
+		t.Fatalf("updating fields of a task in a removed column: %v", err)
+	}
+	if err := svc.MoveTask(ctx, task.ID, db.StatusPlanning); err != nil {
+		t.Errorf("moving out of a removed column: %v", err)
+	}
+}
+
+func TestTransitionRules(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("opening test db: %v", err)
+	}
+	defer database.Close()
+	wf, err := workflow.New([]workflow.Column{
+		{Status: "todo"},
+		{Status: "doing", BlockersDone: true},
+		{Status: "review", From: []db.TaskStatus{"doing"}, Requires: []string{"pr_url"}},
+		{Status: "done"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := board.NewLocalService(database, board.WithWorkflow(wf))
+	ctx := context.Background()
+
+	task, _ := svc.CreateTask(ctx, "Feature", "")
+	blocker, _ := svc.CreateTask(ctx, "Prerequisite", "")
+	if err := svc.AddDependency(ctx, task.ID, blocker.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	wantCode := func(err error, code string) {
+		t.Helper()
+		var terr *board.TransitionError
+		if !errors.As(err, &terr) || terr.Code != code {
+			t.Fatalf("got error %v, want a %s TransitionError", err, code)
+		}
+	}
+
+	wantCode(svc.MoveTask(ctx, task.ID, "review"), board.TransitionNotAllowed)
+	// Claiming moves the task into doing, under the same rules.
+	wantCode(svc.ClaimTask(ctx, task.ID, "alice"), board.TransitionBlocked)
+
+	err = svc.MoveTask(ctx, task.ID, "doing")
+	wantCode(err, board.TransitionBlocked)
+	var terr *board.TransitionError
+	errors.As(err, &terr)
+	if len(terr.Blockers) != 1 || terr.Blockers[0] != blocker.ID {
+		t.Errorf("got blockers %v, want [%s]", terr.Blockers, blocker.ID)
+	}
+
+	if err := svc.MoveTask(ctx, blocker.ID, "done"); err != nil {
+		t.Fatalf("finishing blocker: %v", err)
+	}
+	if err := svc.MoveTask(ctx, task.ID, "doing"); err != nil {
+		t.Fatalf("moving unblocked task: %v", err)
+	}
+
+	wantCode(svc.MoveTask(ctx, task.ID, "review"), board.TransitionMissingFields)
+
+	// Setting the PR URL in the same update satisfies the rule.
+	review := db.TaskStatus("review")
+	url := "https://github.com/org/repo/pull/1"
+	if err := svc.UpdateTaskFields(ctx, task.ID, db.TaskFieldUpdate{Status: &review, PRUrl: &url}); err != nil {
+		t.Fatalf("moving with PR URL: %v", err)
+	}
+	got, _ := svc.GetTask(ctx, task.ID)
+	if got.Status != "review" || got.PRUrl != url {
+		t.Errorf("got %s/%q, want review with PR URL", got.Status, got.PRUrl)
+	}
+}
+
+func TestTaskHistory(t *testing.T) {
+	svc := setupTestService(t)
+	t.Setenv(board.ActorEnv, "")
+	ctx := context.Background()
+
+	task, err := svc.CreateTask(ctx, "Tracked", "")
+	if err != nil {
+		t.Fatalf("creating task: %v", err)
+	}
+	if err := svc.ClaimTask(board.WithActor(ctx, "alice"), task.ID, "alice"); err != nil {
+		t.Fatalf("claiming task: %v", err)
+	}
+	t.Setenv(board.ActorEnv, "claude")
+	if _, err := svc.AddComment(ctx, task.ID, "claude", "on it"); err != nil {
+		t.Fatalf("commenting: %v", err)
+	}
+
+	events, err := svc.ListTaskEvents(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("ListTaskEvents: %v", err)
+	}
+	type change struct{ actor, field, old, new string }
+	want := []change{
+		{board.DefaultActor, "created", "", "Tracked"},
+		{"alice", "status", "backlog", "brainstorm"},
+		{"alice", "assignee", "", "alice"},
+		{"claude", "comment", "", "on it"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events %+v, want %d", len(events), events, len(want))
+	}
+	for i, ev := range events {
+		if got := (change{ev.Actor, ev.Field, ev.OldValue, ev.NewValue}); got != want[i] {
+			t.Errorf("event %d: got %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestLabels(t *testing.T) {
+	svc := setupTestService(t)
+	ctx := context.Background()
+
+	task, _ := svc.CreateTask(ctx, "Labelled", "")
+	if err := svc.AddLabel(ctx, task.ID, " Bug ", ""); err != nil {
+		t.Fatalf("AddLabel: %v", err)
+	}
+	if err := svc.AddLabel(ctx, task.ID, "bug", ""); err != nil {
+		t.Fatalf("adding the label again: %v", err)
+	}
+	if err := svc.AddLabel(ctx, task.ID, "ui", "#BD93F9"); err != nil {
+		t.Fatalf("AddLabel with color: %v", err)
+	}
+
+	got, _ := svc.GetTask(ctx, task.ID)
+	if len(got.Labels) != 2 || got.Labels[0].Name != "bug" || got.Labels[0].Color == "" {
+		t.Fatalf("labels = %+v, want bug with a picked color, then ui", got.Labels)
+	}
+	if got.Labels[1].Color != "#bd93f9" {
+		t.Errorf("ui color = %q, want #bd93f9", got.Labels[1].Color)
+	}
+
+	for _, bad := range []struct{ name, color string }{
+		{"has space", ""},
+		{"", ""},
+		{"ok", "red"},
+	} {
+		if err := svc.AddLabel(ctx, task.ID, bad.name, bad.color); err == nil {
+			t.Errorf("AddLabel(%q, %q) succeeded, want an error", bad.name, bad.color)
+		}
+	}
+	if err := svc.AddLabel(ctx, "missing", "bug", ""); err == nil {
+		t.Error("labelling a missing task succeeded")
+	}
+
+	if err := svc.RemoveLabel(ctx, task.ID, "BUG"); err != nil {
+		t.Fatalf("RemoveLabel: %v", err)
+	}
+	got, _ = svc.GetTask(ctx, task.ID)
+	if got.HasLabel("bug") || !got.HasLabel("ui") {
+		t.Errorf("labels after removal = %+v, want only ui", got.Labels)
+	}
+
+	events, _ := svc.ListTaskEvents(ctx, task.ID)
+	var changes int
+	for _, ev := range events {
+		if ev.Field == "labels" {
+			changes++
+		}
+	}
+	if changes != 3 {
+		t.Errorf("got %d label events, want 3 (bug, ui, -bug)", changes)
+	}
+}
+
+func TestChecklistGate(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("opening test db: %v", err)
+	}
+	defer database.Close()
+	wf, err := workflow.New([]workflow.Column{
+		{Status: "todo"},
+		{Status: "doing"},
+		{Status: "done", ChecklistDone: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := board.NewLocalService(database, board.WithWorkflow(wf))
+	ctx := context.Background()
+
+	task, _ := svc.CreateTask(ctx, "Feature", "")
+	item, err := svc.AddChecklistItem(ctx, task.ID, "  Tests pass ")
+	if err != nil {
+		t.Fatalf("AddChecklistItem: %v", err)
+	}
+	if item.Text != "Tests pass" {
+		t.Errorf("item text = %q, want it trimmed", item.Text)
+	}
+	if _, err := svc.AddChecklistItem(ctx, task.ID, "   "); err == nil {
+		t.Error("adding an empty item succeeded")
+	}
+	if _, err := svc.AddChecklistItem(ctx, "missing", "Tests pass"); err == nil {
+		t.Error("adding an item to a missing task succeeded")
+	}
+
+	err = svc.MoveTask(ctx, task.ID, "done")
+	var terr *board.TransitionError
+	if !errors.As(err, &terr) || terr.Code != board.TransitionChecklist {
+		t.Fatalf("got error %v, want a checklist TransitionError", err)
+	}
+	if len(terr.Unchecked) != 1 || terr.Unchecked[0] != "Tests pass" {
+		t.Errorf("got unchecked %v, want [Tests pass]", terr.Unchecked)
+	}
+
+	if err := svc.SetChecklistItemDone(ctx, task.ID, item.ID, true); err != nil {
+		t.Fatalf("SetChecklistItemDone: %v", err)
+	}
+	if err := svc.SetChecklistItemDone(ctx, task.ID, item.ID+1, true); err == nil {
+		t.Error("ticking a missing item succeeded")
+	}
+	if err := svc.MoveTask(ctx, task.ID, "done"); err != nil {
+		t.Fatalf("moving with a finished checklist: %v", err)
+	}
+
+	if err := svc.RemoveChecklistItem(ctx, task.ID, item.ID); err != nil {
+		t.Fatalf("RemoveChecklistItem: %v", err)
+	}
+	events, _ := svc.ListTaskEvents(ctx, task.ID)
+	var changes []string
+	for _, ev := range events {
+		if ev.Field == "checklist" {
+			changes = append(changes, ev.OldValue+ev.NewValue)
+		}
+	}
+	want := []string{"[ ] Tests pass", "[x] Tests pass", "Tests pass"}
+	if !slices.Equal(changes, want) {
+		t.Errorf("checklist history = %q, want %q", changes, want)
+	}
+}
+
+func TestImportGitHubIssues(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("opening test db: %v", err)
+	}
+	defer database.Close()
+	svc := board.NewLocalService(database)
+	ctx := context.Background()
+
+	issues := []board.GitHubIssue{
+		{
+			Number: 12, Title: "Login fails", Body: "Steps to reproduce...",
+			Labels:    []board.GitHubLabel{{Name: "Good First Issue", Color: "7057FF"}, {Name: "bug!", Color: "d73a4a"}},
+			Assignees: []board.GitHubUser{{Login: "alice"}},
+		},
+		{Number: 13, Title: "Dark mode"},
+	}
+	results, err := svc.ImportGitHubIssues(ctx, "acme/app", issues, false)
+	if err != nil {
+		t.Fatalf("ImportGitHubIssues: %v", err)
+	}
+	if len(results) != 2 || results[0].Action != board.IssueCreated || results[1].Action != board.IssueCreated {
+		t.Fatalf("results = %+v, want both created", results)
+	}
+	task, _ := svc.GetTask(ctx, results[0].TaskID)
+	if task.IssueNumber != 12 || task.IssueRepo != "acme/app" || task.Assignee != "alice" || task.Description != "Steps to reproduce..." {
+		t.Errorf("task = %+v, want acme/app#12 assigned to alice", task)
+	}
+	if len(task.Labels) != 1 || task.Labels[0].Name != "good-first-issue" || task.Labels[0].Color != "#7057ff" {
+		t.Errorf("labels = %+v, want good-first-issue only (bug! is not a valid name)", task.Labels)
+	}
+
+	// Importing again skips linked issues, or with update refreshes them.
+	issues[0].Title = "Login fails on Safari"
+	results, _ = svc.ImportGitHubIssues(ctx, "acme/app", issues, false)
+	if results[0].Action != board.IssueSkipped || results[0].TaskID != task.ID {
+		t.Errorf("re-import = %+v, want skipped", results[0])
+	}
+	results, err = svc.ImportGitHubIssues(ctx, "acme/app", issues, true)
+	if err != nil {
+		t.Fatalf("ImportGitHubIssues update: %v", err)
+	}
+	if results[0].Action != board.IssueUpdated || results[1].Action != board.IssueUnchanged {
+		t.Errorf("update = %+v, want updated then unchanged", results)
+	}
+	if got, _ := svc.GetTask(ctx, task.ID); got.Title != "Login fails on Safari" {
+		t.Errorf("title = %q, want the issue's new title", got.Title)
+	}
+	if tasks, _ := svc.ListTasks(ctx); len(tasks) != 2 {
+		t.Errorf("got %d tasks, want 2", len(tasks))
+	}
+
+	// The same number in another repository is a different issue.
+	other := []board.GitHubIssue{{Number: 12, Title: "Crash on start"}}
+	results, err = svc.ImportGitHubIssues(ctx, "Acme/Other", other, true)
+	if err != nil {
+		t.Fatalf("ImportGitHubIssues other repo: %v", err)
+	}
+	if results[0].Action != board.IssueCreated || results[0].TaskID == task.ID {
+		t.Errorf("other repo = %+v, want a new task", results[0])
+	}
+	if got, _ := svc.GetTask(ctx, task.ID); got.Title != "Login fails on Safari" {
+		t.Errorf("title = %q, the other repo's issue overwrote it", got.Title)
+	}
+	// Repository names are case-insensitive.
+	results, _ = svc.ImportGitHubIssues(ctx, "ACME/APP", issues[:1], true)
+	if results[0].Action != board.IssueUnchanged || results[0].TaskID != task.ID {
+		t.Errorf("re-import with other case = %+v, want unchanged", results[0])
+	}
+	if got, _ := svc.GetTask(ctx, task.ID); got.IssueRepo != "acme/app" {
+		t.Errorf("issue repo = %q, want it left as acme/app", got.IssueRepo)
+	}
+
+	// Without a repository, issues match by number alone, unless that's
+	// ambiguous.
+	results, err = svc.ImportGitHubIssues(ctx, "", issues[1:], false)
+	if err != nil || results[0].Action != board.IssueSkipped {
+		t.Errorf("import without repo = %+v, %v, want #13 skipped", results, err)
+	}
+	if got, _ := svc.GetTask(ctx, results[0].TaskID); got.IssueRepo != "acme/app" {
+		t.Errorf("issue repo = %q, want it kept", got.IssueRepo)
+	}
+	if _, err := svc.ImportGitHubIssues(ctx, "", issues[:1], false); err == nil {
+		t.Error("expected #12, on the board from two repositories, to be ambiguous")
+	}
+}
+
+func TestImportGitHubIssuesClaimsUnownedTasks(t *testing.T) {
+	svc := setupTestService(t).(*board.LocalService)
+	ctx := context.Background()
+
+	// A task imported before the repository was recorded.
+	old, err := svc.CreateTask(ctx, "Login fails", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	old.IssueNumber = 12
+	if err := svc.UpdateTask(ctx, old); err != nil {
+		t.Fatal(err)
+	}
+
+	issues := []board.GitHubIssue{{Number: 12, Title: "Login fails"}}
+	results, err := svc.ImportGitHubIssues(ctx, "acme/app", issues, false)
+	if err != nil {
+		t.Fatalf("ImportGitHubIssues: %v", err)
+	}
+	if results[0].Action != board.IssueSkipped || results[0].TaskID != old.ID {
+		t.Errorf("results = %+v, want the old task skipped", results[0])
+	}
+	if got, _ := svc.GetTask(ctx, old.ID); got.IssueRepo != "acme/app" {
+		t.Errorf("issue repo = %q, want acme/app", got.IssueRepo)
+	}
+	results, _ = svc.ImportGitHubIssues(ctx, "acme/other", issues, false)
+	if results[0].Action != board.IssueCreated {
+		t.Errorf("other repo = %+v, want created once the old task is claimed", results[0])
+	}
+}
+
+func TestImportRecordsHistory(t *testing.T) {
+	svc := setupTestService(t).(*board.LocalService)
+	ctx := context.Background()
+
+	snap := &db.Snapshot{Version: db.SnapshotVersion, Tasks: []db.Task{{ID: "seed", Title: "Seeded"}}}
+	res, err := svc.Import(ctx, snap, db.ImportOptions{})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	events, err := svc.ListTaskEvents(ctx, res.IDs["seed"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Field != "created" || events[0].NewValue != "Seeded" {
+		t.Errorf("events = %+v, want one created event", events)
+	}
+}
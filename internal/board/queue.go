@@ -0,0 +1,27 @@
+package board
+
+import (
+	"context"
+
+	"github.com/markx3/agentboard/internal/db"
+)
+
+// Agent queue. Not part of Service: agents run on the machine that holds
+// the board, so only a local board has a queue.
+
+// EnqueueAgent queues an agent for the task; runner "" means the preferred
+// runner when it starts.
+func (s *LocalService) EnqueueAgent(ctx context.Context, taskID, runner string) error {
+	return s.db.EnqueueAgent(ctx, taskID, runner)
+}
+
+// DequeueAgent removes the task's queued agent and reports whether there
+// was one.
+func (s *LocalService) DequeueAgent(ctx context.Context, taskID string) (bool, error) {
+	return s.db.DequeueAgent(ctx, taskID)
+}
+
+// ListAgentQueue returns the queued agents, oldest first.
+func (s *LocalService) ListAgentQueue(ctx context.Context) ([]db.QueuedAgent, error) {
+	return s.db.ListAgentQueue(ctx)
+}
@@ -15,6 +15,7 @@ type Service interface {
 	UpdateTask(ctx context.Context, task *db.Task) error
 	UpdateTaskFields(ctx context.Context, id string, fields db.TaskFieldUpdate) error
 	MoveTask(ctx context.Context, id string, newStatus db.TaskStatus) error
+	ReorderTask(ctx context.Context, id, beforeID, afterID string) error
 	DeleteTask(ctx context.Context, id string) error
 	ClaimTask(ctx context.Context, id, assignee string) error
 	UnclaimTask(ctx context.Context, id string) error
@@ -24,6 +25,22 @@ type Service interface {
 	AddComment(ctx context.Context, taskID, author, body string) (*db.Comment, error)
 	ListComments(ctx context.Context, taskID string) ([]db.Comment, error)
 
+	// History
+	ListTaskEvents(ctx context.Context, taskID string) ([]db.TaskEvent, error)
+
+	// Search
+	Search(ctx context.Context, query string, limit int) ([]db.SearchHit, error)
+
+	// Labels
+	AddLabel(ctx context.Context, taskID, name, color string) error
+	RemoveLabel(ctx context.Context, taskID, name string) error
+	ListLabels(ctx context.Context) ([]db.Label, error)
+
+	// Checklists
+	AddChecklistItem(ctx context.Context, taskID, text string) (*db.ChecklistItem, error)
+	SetChecklistItemDone(ctx context.Context, taskID string, itemID int64, done bool) error
+	RemoveChecklistItem(ctx context.Context, taskID string, itemID int64) error
+
 	// Dependencies
 	AddDependency(ctx context.Context, taskID, dependsOn string) error
 	RemoveDependency(ctx context.Context, taskID, dependsOn string) error
@@ -0,0 +1,176 @@
+package board
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/markx3/agentboard/internal/db"
+)
+
+// Transition rejection codes, in the order the rules are checked.
+const (
+	TransitionNotAllowed    = "not_allowed"
+	TransitionMissingFields = "missing_fields"
+	TransitionBlocked       = "blocked"
+	TransitionChecklist     = "checklist"
+)
+
+// TransitionError explains why a task can't move to a column. It travels
+// to remote clients in sync.reject, so the TUI and CLI see the same reason
+// whether the board is local or not.
+type TransitionError struct {
+	TaskID string        `json:"task_id"`
+	From   db.TaskStatus `json:"from"`
+	To     db.TaskStatus `json:"to"`
+	Code   string        `json:"code"`
+	// Missing lists required fields that are empty.
+	Missing []string `json:"missing,omitempty"`
+	// Blockers lists the IDs of unfinished tasks this one depends on.
+	Blockers []string `json:"blockers,omitempty"`
+	// Unchecked lists the text of checklist items that aren't done.
+	Unchecked []string `json:"unchecked,omitempty"`
+}
+
+func (e *TransitionError) Error() string {
+	switch e.Code {
+	case TransitionMissingFields:
+		return fmt.Sprintf("can't move to %s: set %s first", e.To, strings.Join(e.Missing, ", "))
+	case TransitionBlocked:
+		short := make([]string, len(e.Blockers))
+		for i, id := range e.Blockers {
+			short[i] = shortID(id)
+		}
+		return fmt.Sprintf("can't move to %s: blocked by unfinished %s", e.To, strings.Join(short, ", "))
+	case TransitionChecklist:
+		return fmt.Sprintf("can't move to %s: %d checklist item(s) unchecked: %s",
+			e.To, len(e.Unchecked), strings.Join(e.Unchecked, "; "))
+	default:
+		return fmt.Sprintf("can't move from %s to %s", e.From, e.To)
+	}
+}
+
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// checkTransition applies the rules of the target column to task, which
+// holds the values the task will have after the move. Moves within a
+// column are always allowed.
+func (s *LocalService) checkTransition(ctx context.Context, from db.TaskStatus, task *db.Task) error {
+	to := task.Status
+	if from == to {
+		// Tasks left in a column removed from the config stay editable.
+		return nil
+	}
+	if err := s.checkStatus(to); err != nil {
+		return err
+	}
+	col, _ := s.workflow.Column(to)
+	terr := &TransitionError{TaskID: task.ID, From: from, To: to}
+
+	if len(col.From) > 0 && !slices.Contains(col.From, from) {
+		terr.Code = TransitionNotAllowed
+		return terr
+	}
+
+	for _, f := range col.Requires {
+		if taskField(task, f) == "" {
+			terr.Missing = append(terr.Missing, f)
+		}
+	}
+	if len(terr.Missing) > 0 {
+		terr.Code = TransitionMissingFields
+		return terr
+	}
+
+	if col.BlockersDone {
+		deps, err := s.db.ListDependencies(ctx, task.ID)
+		if err != nil {
+			return fmt.Errorf("checking blockers: %w", err)
+		}
+		for _, id := range deps {
+			blocker, err := s.db.GetTask(ctx, id)
+			if err != nil {
+				return fmt.Errorf("checking blocker %s: %w", shortID(id), err)
+			}
+			if blocker.Status != s.workflow.Last() {
+				terr.Blockers = append(terr.Blockers, id)
+			}
+		}
+		if len(terr.Blockers) > 0 {
+			terr.Code = TransitionBlocked
+			return terr
+		}
+	}
+
+	if col.ChecklistDone {
+		// Read the checklist from the database: callers of UpdateTask may
+		// pass a task without it.
+		stored, err := s.db.GetTask(ctx, task.ID)
+		if err != nil {
+			return fmt.Errorf("checking checklist: %w", err)
+		}
+		for _, item := range stored.Checklist {
+			if !item.Done {
+				terr.Unchecked = append(terr.Unchecked, item.Text)
+			}
+		}
+		if len(terr.Unchecked) > 0 {
+			terr.Code = TransitionChecklist
+			return terr
+		}
+	}
+	return nil
+}
+
+// taskField returns the value of one of workflow.Fields.
+func taskField(task *db.Task, field string) string {
+	switch field {
+	case "assignee":
+		return task.Assignee
+	case "branch":
+		return task.BranchName
+	case "description":
+		return strings.TrimSpace(task.Description)
+	case "pr_url":
+		return task.PRUrl
+	}
+	return ""
+}
+
+// applyFields returns a copy of task with the set fields of update applied.
+func applyFields(task db.Task, update db.TaskFieldUpdate) db.Task {
+	if update.Title != nil {
+		task.Title = *update.Title
+	}
+	if update.Description != nil {
+		task.Description = *update.Description
+	}
+	if update.Status != nil {
+		task.Status = *update.Status
+	}
+	if update.Assignee != nil {
+		task.Assignee = *update.Assignee
+	}
+	if update.BranchName != nil {
+		task.BranchName = *update.BranchName
+	}
+	if update.PRUrl != nil {
+		task.PRUrl = *update.PRUrl
+	}
+	if update.PRNumber != nil {
+		task.PRNumber = *update.PRNumber
+	}
+	if update.Priority != nil {
+		task.Priority = *update.Priority
+	}
+	if update.DueDate != nil {
+		task.DueDate = *update.DueDate
+	}
+	return task
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/markx3/agentboard/internal/agent"
+	boardpkg "github.com/markx3/agentboard/internal/board"
 	"github.com/markx3/agentboard/internal/db"
 )
 
@@ -29,6 +30,7 @@ var requestResetCmd = &cobra.Command{
 var agentStartCmd = &cobra.Command{
 	Use:   "start <task-id>",
 	Short: "Spawn an agent for a task",
+	Long:  "Spawns an agent for a task. At most agent.max_concurrent_agents agents run at once; with --queue the agent waits for a free slot instead of failing.",
 	Args:  cobra.ExactArgs(1),
 	RunE:  runAgentStart,
 }
@@ -51,12 +53,14 @@ var agentStatusCmd = &cobra.Command{
 var (
 	agentStartRunner     string
 	agentSkipPermissions bool
+	agentStartQueue      bool
 	agentOutputJSON      bool
 )
 
 func init() {
-	agentStartCmd.Flags().StringVar(&agentStartRunner, "runner", "", "agent runner (claude, cursor)")
+	agentStartCmd.Flags().StringVar(&agentStartRunner, "runner", "", "agent runner ID (claude, cursor, codex, gemini, aider, antigravity or one declared in agent.runners)")
 	agentStartCmd.Flags().BoolVar(&agentSkipPermissions, "skip-permissions", false, "skip permission prompts")
+	agentStartCmd.Flags().BoolVar(&agentStartQueue, "queue", false, "queue the agent if all agent slots are busy; queued agents start while the TUI runs, on agent kill or on agent queue run")
 	agentStartCmd.Flags().BoolVar(&agentOutputJSON, "json", false, "output as JSON")
 	agentKillCmd.Flags().BoolVar(&agentOutputJSON, "json", false, "output as JSON")
 	agentStatusCmd.Flags().BoolVar(&agentOutputJSON, "json", false, "output as JSON")
@@ -101,7 +105,7 @@ func runAgentStatus(cmd *cobra.Command, args []string) error {
 }
 
 func runRequestReset(cmd *cobra.Command, args []string) error {
-	svc, cleanup, err := openService()
+	svc, cleanup, err := openLocalService()
 	if err != nil {
 		return err
 	}
@@ -137,7 +141,7 @@ func runRequestReset(cmd *cobra.Command, args []string) error {
 }
 
 func runAgentStart(cmd *cobra.Command, args []string) error {
-	svc, cleanup, err := openService()
+	svc, cleanup, err := openLocalService()
 	if err != nil {
 		return err
 	}
@@ -162,21 +166,26 @@ func runAgentStart(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("agent already running on task %s", task.ID[:8])
 	}
 
-	var runner agent.AgentRunner
-	if agentStartRunner != "" {
-		runner = agent.GetRunner(agentStartRunner)
-		if runner == nil {
-			return fmt.Errorf("unknown runner: %s", agentStartRunner)
-		}
-		if !runner.Available() {
-			return fmt.Errorf("runner %s not available", agentStartRunner)
-		}
-	} else {
-		available := agent.AvailableRunners()
-		if len(available) == 0 {
-			return fmt.Errorf("no agent runners available")
-		}
-		runner = available[0]
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if task.AgentQueued {
+		return fmt.Errorf("agent already queued for task %s", task.ID[:8])
+	}
+
+	runner, err := agent.PickRunner(agentStartRunner, cfg.Agent.Preferred)
+	if err != nil {
+		return err
+	}
+	if !runner.Available() {
+		return fmt.Errorf("runner %s not available", runner.ID())
+	}
+
+	busy := agent.ActiveAgents(tasks) >= cfg.Agent.MaxConcurrentAgents
+	if busy && !agentStartQueue {
+		return fmt.Errorf("all %d agent slots are busy (agent.max_concurrent_agents); use --queue to wait for one", cfg.Agent.MaxConcurrentAgents)
 	}
 
 	if agentSkipPermissions {
@@ -186,7 +195,21 @@ func runAgentStart(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if err := agent.Spawn(ctx, svc, *task, runner); err != nil {
+	if busy {
+		if err := svc.EnqueueAgent(ctx, task.ID, agentStartRunner); err != nil {
+			return err
+		}
+		if agentOutputJSON {
+			task, _ = svc.GetTask(ctx, fullID)
+			if task != nil {
+				return json.NewEncoder(os.Stdout).Encode(task)
+			}
+		}
+		fmt.Printf("All %d agent slots are busy; agent queued for task %s (%s)\n", cfg.Agent.MaxConcurrentAgents, task.ID[:8], task.Title)
+		return nil
+	}
+
+	if err := agent.Spawn(ctx, svc, *task, runner, cfg); err != nil {
 		return fmt.Errorf("spawning agent: %w", err)
 	}
 
@@ -202,7 +225,7 @@ func runAgentStart(cmd *cobra.Command, args []string) error {
 }
 
 func runAgentKill(cmd *cobra.Command, args []string) error {
-	svc, cleanup, err := openService()
+	svc, cleanup, err := openLocalService()
 	if err != nil {
 		return err
 	}
@@ -230,6 +253,8 @@ func runAgentKill(cmd *cobra.Command, args []string) error {
 	if err := agent.Kill(ctx, svc, *task); err != nil {
 		return fmt.Errorf("killing agent: %w", err)
 	}
+	// Hand the freed slot to the queue once the kill is reported.
+	defer startQueuedAgents(ctx, svc)
 
 	if agentOutputJSON {
 		task, _ = svc.GetTask(ctx, fullID)
@@ -241,3 +266,19 @@ func runAgentKill(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Agent killed for task %s (%s)\n", task.ID[:8], task.Title)
 	return nil
 }
+
+// startQueuedAgents starts queued agents in the slots that are free,
+// reporting them on stderr so --json output stays clean.
+func startQueuedAgents(ctx context.Context, svc *boardpkg.LocalService) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return
+	}
+	started, err := agent.StartQueued(ctx, svc, svc, cfg)
+	for _, t := range started {
+		fmt.Fprintf(os.Stderr, "Started queued agent for task %s (%s)\n", t.ID[:8], t.Title)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: starting queued agents: %v\n", err)
+	}
+}
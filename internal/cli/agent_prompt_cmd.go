@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/markx3/agentboard/internal/agent"
+)
+
+var (
+	agentPromptRunner string
+	agentPromptJSON   bool
+)
+
+var agentPromptCmd = &cobra.Command{
+	Use:   "prompt <task-id>",
+	Short: "Print the command an agent would be spawned with",
+	Long: `Render the task's prompt and print the exact shell command 'agent start'
+would run, without creating a worktree or spawning anything. Use it to
+check templates in .agentboard/prompts/. --json also prints the prompt and
+the working directory on their own.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentPrompt,
+}
+
+func init() {
+	agentPromptCmd.Flags().StringVar(&agentPromptRunner, "runner", "", "agent runner ID (default: the one 'agent start' would pick)")
+	agentPromptCmd.Flags().BoolVar(&agentPromptJSON, "json", false, "output as JSON")
+	agentCmd.AddCommand(agentPromptCmd)
+}
+
+// agentPromptOutput is the --json output of `agent prompt`.
+type agentPromptOutput struct {
+	Runner  string `json:"runner"`
+	WorkDir string `json:"work_dir"`
+	Prompt  string `json:"prompt"`
+	Command string `json:"command"`
+}
+
+func runAgentPrompt(cmd *cobra.Command, args []string) error {
+	svc, cleanup, err := openLocalService()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	tasks, err := svc.ListTasks(ctx)
+	if err != nil {
+		return err
+	}
+	fullID := findByPrefix(tasks, args[0])
+	if fullID == "" {
+		return fmt.Errorf("task not found: %s", args[0])
+	}
+	task, err := svc.GetTask(ctx, fullID)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	runner, err := agent.PickRunner(agentPromptRunner, cfg.Agent.Preferred)
+	if err != nil {
+		return err
+	}
+	if !runner.Available() {
+		fmt.Fprintf(os.Stderr, "warning: runner %s is not available on this machine\n", runner.ID())
+	}
+
+	workDir, err := agent.WorktreeDir(*task)
+	if err != nil {
+		return err
+	}
+	opts, err := agent.PrepareSpawn(ctx, svc, *task, workDir, cfg)
+	if err != nil {
+		return err
+	}
+	command := agent.SpawnCommand(runner, opts)
+
+	if agentPromptJSON {
+		return json.NewEncoder(os.Stdout).Encode(agentPromptOutput{
+			Runner:  runner.ID(),
+			WorkDir: workDir,
+			Prompt:  agent.SpawnPrompt(runner, opts),
+			Command: command,
+		})
+	}
+
+	fmt.Printf("# runner: %s (%s)\n", runner.ID(), runner.Name())
+	fmt.Printf("# workdir: %s\n", workDir)
+	fmt.Println(command)
+	return nil
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/markx3/agentboard/internal/agent"
+	"github.com/markx3/agentboard/internal/db"
+)
+
+var agentQueueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Manage agents waiting for a free slot",
+	Long:  "Agents started with `agent start --queue` while all agent.max_concurrent_agents slots are busy wait here, oldest first. The TUI starts them as slots free up; without it, `agent kill` and `agent queue run` do.",
+}
+
+var agentQueueListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List queued agents",
+	Args:  cobra.NoArgs,
+	RunE:  runAgentQueueList,
+}
+
+var agentQueueRemoveCmd = &cobra.Command{
+	Use:   "remove <task-id>",
+	Short: "Remove a task's agent from the queue",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAgentQueueRemove,
+}
+
+var agentQueueRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Start queued agents in the free agent slots",
+	Long:  "Starts queued agents, oldest first, while fewer than agent.max_concurrent_agents are running. Only the TUI notices agents that exit on their own; without it an agent holds its slot until `agent kill`.",
+	Args:  cobra.NoArgs,
+	RunE:  runAgentQueueRun,
+}
+
+func init() {
+	agentQueueListCmd.Flags().BoolVar(&agentOutputJSON, "json", false, "output as JSON")
+	agentQueueCmd.AddCommand(agentQueueListCmd, agentQueueRemoveCmd, agentQueueRunCmd)
+	agentCmd.AddCommand(agentQueueCmd)
+}
+
+func runAgentQueueList(cmd *cobra.Command, args []string) error {
+	svc, cleanup, err := openLocalService()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	queued, err := svc.ListAgentQueue(ctx)
+	if err != nil {
+		return err
+	}
+	if agentOutputJSON {
+		if queued == nil {
+			queued = []db.QueuedAgent{}
+		}
+		return json.NewEncoder(os.Stdout).Encode(queued)
+	}
+	if len(queued) == 0 {
+		fmt.Println("No queued agents")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tID\tTITLE\tRUNNER\tQUEUED")
+	for i, q := range queued {
+		title := ""
+		if task, err := svc.GetTask(ctx, q.TaskID); err == nil {
+			title = task.Title
+		}
+		runner := q.Runner
+		if runner == "" {
+			runner = "(preferred)"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n",
+			i+1, q.TaskID[:8], title, runner, q.QueuedAt.Local().Format("2006-01-02 15:04"))
+	}
+	return w.Flush()
+}
+
+func runAgentQueueRemove(cmd *cobra.Command, args []string) error {
+	svc, cleanup, err := openLocalService()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	tasks, err := svc.ListTasks(ctx)
+	if err != nil {
+		return err
+	}
+	fullID := findByPrefix(tasks, args[0])
+	if fullID == "" {
+		return fmt.Errorf("task not found: %s", args[0])
+	}
+
+	removed, err := svc.DequeueAgent(ctx, fullID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("no queued agent for task %s", fullID[:8])
+	}
+	fmt.Printf("Removed task %s from the agent queue\n", fullID[:8])
+	return nil
+}
+
+func runAgentQueueRun(cmd *cobra.Command, args []string) error {
+	svc, cleanup, err := openLocalService()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	started, err := agent.StartQueued(context.Background(), svc, svc, cfg)
+	for _, t := range started {
+		fmt.Printf("Started queued agent for task %s (%s)\n", t.ID[:8], t.Title)
+	}
+	if err != nil {
+		return fmt.Errorf("starting queued agents: %w", err)
+	}
+	if len(started) == 0 {
+		fmt.Println("No queued agent could start")
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	boardpkg "github.com/markx3/agentboard/internal/board"
+	"github.com/markx3/agentboard/internal/db"
+)
+
+var taskCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Manage a task's checklist of acceptance criteria",
+}
+
+var taskCheckAddCmd = &cobra.Command{
+	Use:   "add <task-id> <text>...",
+	Short: "Add an item to a task's checklist",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runTaskCheckAdd,
+}
+
+var taskCheckTickCmd = &cobra.Command{
+	Use:   "tick <task-id> <item>...",
+	Short: "Mark checklist items done (items are numbered from 1)",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runTaskCheckTick,
+}
+
+var taskCheckUntickCmd = &cobra.Command{
+	Use:   "untick <task-id> <item>...",
+	Short: "Mark checklist items not done",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runTaskCheckUntick,
+}
+
+var taskCheckRemoveCmd = &cobra.Command{
+	Use:   "remove <task-id> <item>...",
+	Short: "Remove items from a task's checklist",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runTaskCheckRemove,
+}
+
+var taskCheckListCmd = &cobra.Command{
+	Use:   "list <task-id>",
+	Short: "Show a task's checklist",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTaskCheckList,
+}
+
+func init() {
+	taskCheckCmd.AddCommand(taskCheckAddCmd, taskCheckTickCmd, taskCheckUntickCmd, taskCheckRemoveCmd, taskCheckListCmd)
+	taskCmd.AddCommand(taskCheckCmd)
+}
+
+func runTaskCheckAdd(cmd *cobra.Command, args []string) error {
+	return changeChecklist(args[0], func(ctx context.Context, svc boardpkg.Service, task *db.Task) error {
+		_, err := svc.AddChecklistItem(ctx, task.ID, strings.Join(args[1:], " "))
+		return err
+	})
+}
+
+func runTaskCheckTick(cmd *cobra.Command, args []string) error {
+	return setChecklistItems(args, true)
+}
+
+func runTaskCheckUntick(cmd *cobra.Command, args []string) error {
+	return setChecklistItems(args, false)
+}
+
+func setChecklistItems(args []string, done bool) error {
+	return changeChecklist(args[0], func(ctx context.Context, svc boardpkg.Service, task *db.Task) error {
+		ids, err := checklistItemIDs(task.Checklist, args[1:])
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err := svc.SetChecklistItemDone(ctx, task.ID, id, done); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func runTaskCheckRemove(cmd *cobra.Command, args []string) error {
+	return changeChecklist(args[0], func(ctx context.Context, svc boardpkg.Service, task *db.Task) error {
+		// Resolve every number first: removing an item renumbers the rest.
+		ids, err := checklistItemIDs(task.Checklist, args[1:])
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err := svc.RemoveChecklistItem(ctx, task.ID, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func runTaskCheckList(cmd *cobra.Command, args []string) error {
+	return changeChecklist(args[0], nil)
+}
+
+// changeChecklist finds the task by ID prefix, applies change (if any) and
+// prints the resulting checklist.
+func changeChecklist(prefix string, change func(context.Context, boardpkg.Service, *db.Task) error) error {
+	svc, cleanup, err := openService()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	tasks, err := svc.ListTasks(ctx)
+	if err != nil {
+		return err
+	}
+	fullID := findByPrefix(tasks, prefix)
+	if fullID == "" {
+		return fmt.Errorf("task not found: %s", prefix)
+	}
+	task, err := svc.GetTask(ctx, fullID)
+	if err != nil {
+		return err
+	}
+
+	if change != nil {
+		if err := change(ctx, svc, task); err != nil {
+			return err
+		}
+		if task, err = svc.GetTask(ctx, fullID); err != nil {
+			return err
+		}
+	}
+
+	if taskOutputJSON {
+		items := task.Checklist
+		if items == nil {
+			items = []db.ChecklistItem{}
+		}
+		return json.NewEncoder(os.Stdout).Encode(items)
+	}
+	if len(task.Checklist) == 0 {
+		fmt.Printf("No checklist on %s\n", task.ID[:8])
+		return nil
+	}
+	done, total := task.ChecklistProgress()
+	fmt.Printf("Checklist on %s (%d/%d):\n", task.ID[:8], done, total)
+	printChecklist(task.Checklist)
+	return nil
+}
+
+// checklistItemIDs maps 1-based item numbers, as printed by printChecklist,
+// to item IDs.
+func checklistItemIDs(items []db.ChecklistItem, args []string) ([]int64, error) {
+	ids := make([]int64, len(args))
+	for i, arg := range args {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 || n > len(items) {
+			return nil, fmt.Errorf("invalid checklist item %q: the task has %d item(s)", arg, len(items))
+		}
+		ids[i] = items[n-1].ID
+	}
+	return ids, nil
+}
+
+func printChecklist(items []db.ChecklistItem) {
+	for i, item := range items {
+		mark := " "
+		if item.Done {
+			mark = "x"
+		}
+		fmt.Printf("  %d. [%s] %s\n", i+1, mark, item.Text)
+	}
+}
@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	boardpkg "github.com/markx3/agentboard/internal/board"
+	"github.com/markx3/agentboard/internal/db"
+	"github.com/markx3/agentboard/internal/workflow"
+)
+
+var (
+	exportFormat string
+	exportOutput string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the board as JSON or Markdown",
+	Long: `Export every task with its labels, checklist and dependencies, along
+with comments and suggestions. JSON exports can be loaded back with
+'agentboard import'; Markdown is for reading. Task history is not exported.`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "json", "output format: json or markdown")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "write to a file instead of stdout")
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if exportFormat != "json" && exportFormat != "markdown" && exportFormat != "md" {
+		return fmt.Errorf("invalid format %q (use: json, markdown)", exportFormat)
+	}
+
+	svc, cleanup, err := openService()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	snap, err := boardpkg.Export(context.Background(), svc)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if exportOutput != "" {
+		f, err := os.Create(exportOutput)
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", exportOutput, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if exportFormat == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(snap)
+	} else {
+		cfg, cfgErr := loadConfig()
+		if cfgErr != nil {
+			return cfgErr
+		}
+		err = writeMarkdown(out, snap, cfg.BoardWorkflow())
+	}
+	if err != nil {
+		return fmt.Errorf("writing export: %w", err)
+	}
+	if exportOutput != "" {
+		fmt.Fprintf(os.Stderr, "Exported %d task(s) to %s\n", len(snap.Tasks), exportOutput)
+	}
+	return nil
+}
+
+// writeMarkdown renders a snapshot as a readable document: one section per
+// column in workflow order, then suggestions. Columns that are no longer
+// configured come after the configured ones.
+func writeMarkdown(w io.Writer, snap *db.Snapshot, wf *workflow.Workflow) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Agentboard export\n\n")
+	fmt.Fprintf(&b, "Exported %s, %d task(s).\n", snap.ExportedAt.Format("2006-01-02 15:04 MST"), len(snap.Tasks))
+
+	titles := make(map[string]string, len(snap.Tasks))
+	byStatus := make(map[db.TaskStatus][]db.Task)
+	var statuses []db.TaskStatus
+	for _, s := range wf.Statuses() {
+		statuses = append(statuses, s)
+		byStatus[s] = nil
+	}
+	for _, t := range snap.Tasks {
+		titles[t.ID] = t.Title
+		if _, ok := byStatus[t.Status]; !ok {
+			statuses = append(statuses, t.Status)
+		}
+		byStatus[t.Status] = append(byStatus[t.Status], t)
+	}
+	comments := make(map[string][]db.Comment)
+	for _, c := range snap.Comments {
+		comments[c.TaskID] = append(comments[c.TaskID], c)
+	}
+
+	for _, status := range statuses {
+		tasks := byStatus[status]
+		fmt.Fprintf(&b, "\n## %s (%d)\n", wf.Name(status), len(tasks))
+		for _, t := range tasks {
+			writeMarkdownTask(&b, t, titles, comments[t.ID])
+		}
+	}
+
+	if len(snap.Suggestions) > 0 {
+		fmt.Fprintf(&b, "\n## Suggestions (%d)\n\n", len(snap.Suggestions))
+		for _, s := range snap.Suggestions {
+			line := fmt.Sprintf("- [%s] %s", s.Status, s.Type)
+			if s.Title != "" {
+				line += " **" + s.Title + "**"
+			}
+			if s.TaskID != "" {
+				line += fmt.Sprintf(" on %q", titles[s.TaskID])
+			}
+			if s.Author != "" {
+				line += " by " + s.Author
+			}
+			fmt.Fprintf(&b, "%s: %s\n", line, oneLine(s.Message))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownTask(b *strings.Builder, t db.Task, titles map[string]string, comments []db.Comment) {
+	fmt.Fprintf(b, "\n### %s\n\n", t.Title)
+	fmt.Fprintf(b, "- ID: `%s`\n", t.ID)
+	if t.Priority != db.PriorityNone {
+		fmt.Fprintf(b, "- Priority: %s\n", t.Priority)
+	}
+	if t.DueDate != "" {
+		fmt.Fprintf(b, "- Due: %s\n", t.DueDate)
+	}
+	if t.Assignee != "" {
+		fmt.Fprintf(b, "- Assignee: %s\n", t.Assignee)
+	}
+	if len(t.Labels) > 0 {
+		fmt.Fprintf(b, "- Labels: %s\n", labelList(t.Labels))
+	}
+	for _, id := range t.BlockedBy {
+		title, ok := titles[id]
+		if !ok {
+			title = "(not exported)"
+		}
+		fmt.Fprintf(b, "- Blocked by: `%s` %s\n", shortID(id), title)
+	}
+	if t.BranchName != "" {
+		fmt.Fprintf(b, "- Branch: `%s`\n", t.BranchName)
+	}
+	if t.PRUrl != "" {
+		fmt.Fprintf(b, "- PR: %s\n", t.PRUrl)
+	}
+	fmt.Fprintf(b, "- Created: %s\n", t.CreatedAt.Format("2006-01-02 15:04"))
+
+	if desc := strings.TrimSpace(t.Description); desc != "" {
+		fmt.Fprintf(b, "\n%s\n", desc)
+	}
+
+	if len(t.Checklist) > 0 {
+		done, total := t.ChecklistProgress()
+		fmt.Fprintf(b, "\nChecklist (%d/%d):\n\n", done, total)
+		for _, item := range t.Checklist {
+			mark := " "
+			if item.Done {
+				mark = "x"
+			}
+			fmt.Fprintf(b, "- [%s] %s\n", mark, item.Text)
+		}
+	}
+
+	if len(comments) > 0 {
+		fmt.Fprintf(b, "\nComments:\n\n")
+		for _, c := range comments {
+			fmt.Fprintf(b, "- %s, %s: %s\n", c.Author, c.CreatedAt.Format("2006-01-02 15:04"), oneLine(c.Body))
+		}
+	}
+}
+
+// oneLine collapses whitespace so multi-line text fits in a list item.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
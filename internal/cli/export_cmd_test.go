@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/markx3/agentboard/internal/db"
+	"github.com/markx3/agentboard/internal/workflow"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	snap := &db.Snapshot{
+		ExportedAt: time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC),
+		Tasks: []db.Task{
+			{ID: "aaaaaaaa-1", Title: "Ship it", Status: db.StatusInProgress, Priority: db.PriorityHigh,
+				BlockedBy: []string{"bbbbbbbb-2"},
+				Checklist: []db.ChecklistItem{{Text: "Tests pass", Done: true}, {Text: "Docs"}}},
+			{ID: "bbbbbbbb-2", Title: "Old column", Status: "archived"},
+		},
+		Comments: []db.Comment{{TaskID: "aaaaaaaa-1", Author: "bob", Body: "on\nit"}},
+		Suggestions: []db.Suggestion{
+			{TaskID: "aaaaaaaa-1", Type: db.SuggestionHint, Status: db.SuggestionPending, Message: "check logs"},
+		},
+	}
+
+	var b strings.Builder
+	if err := writeMarkdown(&b, snap, workflow.Default()); err != nil {
+		t.Fatalf("writeMarkdown: %v", err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"## Backlog (0)",
+		"## In Progress (1)\n\n### Ship it",
+		"- Priority: high",
+		"- Blocked by: `bbbbbbbb` Old column",
+		"Checklist (1/2):\n\n- [x] Tests pass\n- [ ] Docs",
+		"- bob, 0001-01-01 00:00: on it",
+		"- [pending] hint on \"Ship it\": check logs",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	// Columns outside the workflow come last, under their status.
+	if strings.Index(out, "## archived (1)") < strings.Index(out, "## Done") {
+		t.Errorf("unknown column should follow the workflow's:\n%s", out)
+	}
+}
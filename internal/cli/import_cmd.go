@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/markx3/agentboard/internal/db"
+)
+
+var (
+	importOnConflict string
+	importNewIDs     bool
+	importJSON       bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import tasks from a JSON export",
+	Long: `Recreate the tasks, comments, suggestions and labels of a file written by
+'agentboard export --format json' ("-" reads stdin). Imported tasks keep
+their IDs unless they clash with a task on the board:
+
+  skip     keep the board's task and ignore the imported one (default)
+  replace  overwrite the board's task and everything attached to it
+  copy     import the task under a new ID
+
+With --new-ids every task gets a new ID. Dependencies and comments follow
+their tasks either way. Works on the local board only.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importOnConflict, "on-conflict", string(db.ImportSkip), "what to do with tasks that already exist: skip, replace or copy")
+	importCmd.Flags().BoolVar(&importNewIDs, "new-ids", false, "give every imported task a new ID")
+	importCmd.Flags().BoolVar(&importJSON, "json", false, "output the result as JSON")
+	rootCmd.AddCommand(importCmd)
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	opts := db.ImportOptions{
+		OnConflict: db.ImportConflict(importOnConflict),
+		NewIDs:     importNewIDs,
+	}
+	if !opts.OnConflict.Valid() {
+		return fmt.Errorf("invalid --on-conflict %q (use: skip, replace, copy)", importOnConflict)
+	}
+
+	snap, err := readSnapshot(args[0])
+	if err != nil {
+		return err
+	}
+
+	svc, cleanup, err := openLocalService()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	res, err := svc.Import(context.Background(), snap, opts)
+	if err != nil {
+		return err
+	}
+
+	if importJSON {
+		return json.NewEncoder(os.Stdout).Encode(res)
+	}
+
+	fmt.Printf("Imported %d task(s), replaced %d, skipped %d; %d comment(s), %d suggestion(s)\n",
+		res.Created, res.Replaced, res.Skipped, res.Comments, res.Suggestions)
+	var unknown []string
+	for _, t := range snap.Tasks {
+		if !svc.Workflow().Valid(t.Status) && !slices.Contains(unknown, string(t.Status)) {
+			unknown = append(unknown, string(t.Status))
+		}
+	}
+	for _, status := range unknown {
+		fmt.Fprintf(os.Stderr, "warning: column %q is not in this board's workflow; move its tasks with 'agentboard task move'\n", status)
+	}
+	return nil
+}
+
+// readSnapshot decodes a JSON export from path, or from stdin for "-".
+func readSnapshot(path string) (*db.Snapshot, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("opening %s: %w", path, err)
+		}
+		defer f.Close()
+		r = f
+	}
+	var snap db.Snapshot
+	if err := json.NewDecoder(r).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("reading %s: not a JSON export: %w", path, err)
+	}
+	if snap.Version == 0 {
+		return nil, fmt.Errorf("reading %s: not an agentboard export (missing version)", path)
+	}
+	return &snap, nil
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	boardpkg "github.com/markx3/agentboard/internal/board"
+)
+
+// ghIssueFields are the fields ImportGitHubIssues reads.
+const ghIssueFields = "number,title,body,labels,assignees"
+
+var (
+	importGitHubRepo   string
+	importGitHubState  string
+	importGitHubLimit  int
+	importGitHubUpdate bool
+)
+
+var importGitHubCmd = &cobra.Command{
+	Use:   "github [file]",
+	Short: "Import GitHub issues as tasks",
+	Long: `Create a task for every GitHub issue not on the board yet. Issues are read
+from the output of
+
+  gh issue list --json ` + ghIssueFields + `
+
+given as a file ("-" reads stdin), or fetched by running gh when no file is
+given. Tasks remember the repository and number of their issue, so issues
+imported before are skipped; with --update their title, description,
+assignee and labels are refreshed from the issue instead. For a file,
+--repo names the repository it was listed from; without it the issues are
+linked by number only. Works on the local board only.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runImportGitHub,
+}
+
+func init() {
+	importGitHubCmd.Flags().StringVarP(&importGitHubRepo, "repo", "R", "", "repository the issues come from, as OWNER/REPO (default: the current one)")
+	importGitHubCmd.Flags().StringVar(&importGitHubState, "state", "open", "issues to import: open, closed or all")
+	importGitHubCmd.Flags().IntVarP(&importGitHubLimit, "limit", "L", 100, "maximum number of issues to fetch")
+	importGitHubCmd.Flags().BoolVar(&importGitHubUpdate, "update", false, "update tasks of issues imported before instead of skipping them")
+	importGitHubCmd.Flags().BoolVar(&importJSON, "json", false, "output the result as JSON")
+	importCmd.AddCommand(importGitHubCmd)
+}
+
+func runImportGitHub(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	repo := importGitHubRepo
+	var issues []boardpkg.GitHubIssue
+	var err error
+	if len(args) == 1 {
+		// Saved output doesn't say where it came from: without --repo the
+		// issues are linked by number only.
+		issues, err = readGitHubIssues(args[0])
+	} else {
+		if repo == "" {
+			if repo, err = currentGitHubRepo(ctx); err != nil {
+				return err
+			}
+		}
+		issues, err = fetchGitHubIssues(ctx, repo)
+	}
+	if err != nil {
+		return err
+	}
+
+	svc, cleanup, err := openLocalService()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	results, err := svc.ImportGitHubIssues(ctx, repo, issues, importGitHubUpdate)
+	if err != nil {
+		return err
+	}
+
+	if importJSON {
+		return json.NewEncoder(os.Stdout).Encode(results)
+	}
+
+	counts := make(map[string]int)
+	for _, r := range results {
+		counts[r.Action]++
+		if r.Action == boardpkg.IssueCreated || r.Action == boardpkg.IssueUpdated {
+			fmt.Printf("%-8s %s#%-5d %s  %s\n", r.Action, r.Repo, r.Number, r.TaskID[:8], r.Title)
+		}
+	}
+	fmt.Printf("%d issue(s): %d created, %d updated, %d unchanged, %d skipped\n", len(results),
+		counts[boardpkg.IssueCreated], counts[boardpkg.IssueUpdated],
+		counts[boardpkg.IssueUnchanged], counts[boardpkg.IssueSkipped])
+	return nil
+}
+
+// currentGitHubRepo asks gh for the OWNER/REPO of the current directory.
+func currentGitHubRepo(ctx context.Context) (string, error) {
+	out, err := runGH(ctx, "finding the repository", "repo", "view", "--json", "nameWithOwner", "--jq", ".nameWithOwner")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// fetchGitHubIssues lists the issues of repo with the gh CLI.
+func fetchGitHubIssues(ctx context.Context, repo string) ([]boardpkg.GitHubIssue, error) {
+	out, err := runGH(ctx, "listing issues", "issue", "list", "--json", ghIssueFields, "--repo", repo,
+		"--state", importGitHubState, "--limit", strconv.Itoa(importGitHubLimit))
+	if err != nil {
+		return nil, err
+	}
+	var issues []boardpkg.GitHubIssue
+	if err := json.Unmarshal(out, &issues); err != nil {
+		return nil, fmt.Errorf("decoding gh output: %w", err)
+	}
+	return issues, nil
+}
+
+// runGH runs gh and returns its output, or an error starting with what.
+func runGH(ctx context.Context, what string, args ...string) ([]byte, error) {
+	var stderr strings.Builder
+	ghCmd := exec.CommandContext(ctx, "gh", args...)
+	ghCmd.Stderr = &stderr
+	out, err := ghCmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s with gh: %s", what, msg)
+		}
+		return nil, fmt.Errorf("%s with gh (is it installed and logged in?): %w", what, err)
+	}
+	return out, nil
+}
+
+// readGitHubIssues decodes saved `gh issue list --json` output from path,
+// or from stdin for "-".
+func readGitHubIssues(path string) ([]boardpkg.GitHubIssue, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("opening %s: %w", path, err)
+		}
+		defer f.Close()
+		r = f
+	}
+	var issues []boardpkg.GitHubIssue
+	if err := json.NewDecoder(r).Decode(&issues); err != nil {
+		return nil, fmt.Errorf("reading %s: expected the JSON array printed by gh issue list --json %s: %w", path, ghIssueFields, err)
+	}
+	return issues, nil
+}
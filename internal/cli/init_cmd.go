@@ -33,12 +33,22 @@ func runInit(cmd *cobra.Command, args []string) error {
 	defaultConfig := `[project]
 name = ""
 
-# [agent]
-# preferred = "claude"  # Reserved for future use
+[agent]
+preferred = "claude"
+max_concurrent_enrichments = 3
+max_concurrent_agents = 4
 
 [worktree]
 copy_files = [".env", ".env.local"]
 init_script = ""
+
+[tui]
+poll_interval = "2.5s"
+grace_period = "5s"
+
+[server]
+auth = "github"
+token_file = ""
 `
 	if err := os.WriteFile(configPath, []byte(defaultConfig), 0o644); err != nil {
 		return fmt.Errorf("writing config: %w", err)
@@ -46,7 +56,7 @@ init_script = ""
 
 	// Add server.json to gitignore
 	gitignorePath := filepath.Join(dir, ".gitignore")
-	if err := os.WriteFile(gitignorePath, []byte("server.json\nworktrees/\n"), 0o644); err != nil {
+	if err := os.WriteFile(gitignorePath, []byte("server.json\nleader.lock\nworktrees/\n"), 0o644); err != nil {
 		return fmt.Errorf("writing gitignore: %w", err)
 	}
 
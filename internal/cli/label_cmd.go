@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/markx3/agentboard/internal/db"
+)
+
+var labelColor string
+
+var taskLabelCmd = &cobra.Command{
+	Use:   "label",
+	Short: "Manage task labels",
+}
+
+var taskLabelAddCmd = &cobra.Command{
+	Use:   "add <task-id> <label>...",
+	Short: "Add labels to a task",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runTaskLabelAdd,
+}
+
+var taskLabelRemoveCmd = &cobra.Command{
+	Use:   "remove <task-id> <label>...",
+	Short: "Remove labels from a task",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runTaskLabelRemove,
+}
+
+var taskLabelListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List labels and how many tasks carry each",
+	Args:  cobra.NoArgs,
+	RunE:  runTaskLabelList,
+}
+
+func init() {
+	taskLabelAddCmd.Flags().StringVar(&labelColor, "color", "", "label color as #rrggbb (default: keep, or pick one for a new label)")
+
+	taskLabelCmd.AddCommand(taskLabelAddCmd, taskLabelRemoveCmd, taskLabelListCmd)
+	taskCmd.AddCommand(taskLabelCmd)
+}
+
+func runTaskLabelAdd(cmd *cobra.Command, args []string) error {
+	return changeLabels(args, true)
+}
+
+func runTaskLabelRemove(cmd *cobra.Command, args []string) error {
+	return changeLabels(args, false)
+}
+
+// changeLabels adds or removes the labels named in args[1:] on the task
+// args[0] and prints the updated task.
+func changeLabels(args []string, add bool) error {
+	svc, cleanup, err := openService()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	tasks, err := svc.ListTasks(ctx)
+	if err != nil {
+		return err
+	}
+	fullID := findByPrefix(tasks, args[0])
+	if fullID == "" {
+		return fmt.Errorf("task not found: %s", args[0])
+	}
+
+	for _, name := range args[1:] {
+		if add {
+			err = svc.AddLabel(ctx, fullID, name, labelColor)
+		} else {
+			err = svc.RemoveLabel(ctx, fullID, name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	task, err := svc.GetTask(ctx, fullID)
+	if err != nil {
+		return err
+	}
+	if taskOutputJSON {
+		return json.NewEncoder(os.Stdout).Encode(task)
+	}
+	fmt.Printf("Labels on %s: %s\n", task.ID[:8], labelList(task.Labels))
+	return nil
+}
+
+type labelInfo struct {
+	db.Label
+	Tasks int `json:"tasks"`
+}
+
+func runTaskLabelList(cmd *cobra.Command, args []string) error {
+	svc, cleanup, err := openService()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	labels, err := svc.ListLabels(ctx)
+	if err != nil {
+		return err
+	}
+	tasks, err := svc.ListTasks(ctx)
+	if err != nil {
+		return err
+	}
+	counts := labelCounts(tasks)
+
+	infos := make([]labelInfo, len(labels))
+	for i, l := range labels {
+		infos[i] = labelInfo{Label: l, Tasks: counts[l.Name]}
+	}
+
+	if taskOutputJSON {
+		return json.NewEncoder(os.Stdout).Encode(infos)
+	}
+
+	if len(infos) == 0 {
+		fmt.Println("No labels")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LABEL\tCOLOR\tTASKS")
+	for _, l := range infos {
+		fmt.Fprintf(w, "%s\t%s\t%d\n", l.Name, l.Color, l.Tasks)
+	}
+	return w.Flush()
+}
+
+// labelCounts returns how many of tasks carry each label.
+func labelCounts(tasks []db.Task) map[string]int {
+	counts := make(map[string]int)
+	for _, t := range tasks {
+		for _, l := range t.Labels {
+			counts[l.Name]++
+		}
+	}
+	return counts
+}
+
+// filterTasksByLabels keeps the tasks that carry every one of labels.
+func filterTasksByLabels(tasks []db.Task, labels []string) []db.Task {
+	var out []db.Task
+	for _, t := range tasks {
+		match := true
+		for _, l := range labels {
+			if !t.HasLabel(strings.ToLower(strings.TrimSpace(l))) {
+				match = false
+				break
+			}
+		}
+		if match {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func labelList(labels []db.Label) string {
+	names := make([]string, len(labels))
+	for i, l := range labels {
+		names[i] = l.Name
+	}
+	return strings.Join(names, ", ")
+}
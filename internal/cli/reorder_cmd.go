@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	boardpkg "github.com/markx3/agentboard/internal/board"
+	"github.com/markx3/agentboard/internal/db"
+)
+
+var (
+	reorderBefore string
+	reorderAfter  string
+	reorderTop    bool
+	reorderBottom bool
+)
+
+var taskReorderCmd = &cobra.Command{
+	Use:   "reorder <task-id>",
+	Short: "Move a task up or down within its column",
+	Long: `Place a task just before or after another task in the same column, or at
+the top or bottom of its column. Exactly one of --before, --after, --top
+and --bottom is required.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTaskReorder,
+}
+
+func init() {
+	taskReorderCmd.Flags().StringVar(&reorderBefore, "before", "", "place the task just before this task")
+	taskReorderCmd.Flags().StringVar(&reorderAfter, "after", "", "place the task just after this task")
+	taskReorderCmd.Flags().BoolVar(&reorderTop, "top", false, "move the task to the top of its column")
+	taskReorderCmd.Flags().BoolVar(&reorderBottom, "bottom", false, "move the task to the bottom of its column")
+	taskReorderCmd.MarkFlagsMutuallyExclusive("before", "after", "top", "bottom")
+	taskReorderCmd.MarkFlagsOneRequired("before", "after", "top", "bottom")
+	taskCmd.AddCommand(taskReorderCmd)
+}
+
+func runTaskReorder(cmd *cobra.Command, args []string) error {
+	svc, cleanup, err := openService()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	tasks, err := svc.ListTasks(ctx)
+	if err != nil {
+		return err
+	}
+	fullID := findByPrefix(tasks, args[0])
+	if fullID == "" {
+		return fmt.Errorf("task not found: %s", args[0])
+	}
+
+	var beforeID, afterID string
+	switch {
+	case reorderBefore != "":
+		if beforeID = findByPrefix(tasks, reorderBefore); beforeID == "" {
+			return fmt.Errorf("task not found: %s", reorderBefore)
+		}
+	case reorderAfter != "":
+		if afterID = findByPrefix(tasks, reorderAfter); afterID == "" {
+			return fmt.Errorf("task not found: %s", reorderAfter)
+		}
+	default:
+		beforeID, afterID = columnEnd(tasks, fullID, reorderTop)
+		if beforeID == "" && afterID == "" {
+			return printColumn(ctx, svc, fullID)
+		}
+	}
+
+	if err := svc.ReorderTask(ctx, fullID, beforeID, afterID); err != nil {
+		return err
+	}
+	return printColumn(ctx, svc, fullID)
+}
+
+// columnEnd returns the reorder target that puts the task at the top
+// (before the first other task) or bottom (after the last one) of its
+// column. Both are empty when the task is alone in its column.
+func columnEnd(tasks []db.Task, id string, top bool) (beforeID, afterID string) {
+	var status db.TaskStatus
+	for _, t := range tasks {
+		if t.ID == id {
+			status = t.Status
+		}
+	}
+	// ListTasks orders tasks by status, then position.
+	var column []string
+	for _, t := range tasks {
+		if t.Status == status && t.ID != id {
+			column = append(column, t.ID)
+		}
+	}
+	if len(column) == 0 {
+		return "", ""
+	}
+	if top {
+		return column[0], ""
+	}
+	return "", column[len(column)-1]
+}
+
+// printColumn shows the task's column in its current order, marking the
+// task.
+func printColumn(ctx context.Context, svc boardpkg.Service, id string) error {
+	task, err := svc.GetTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	column, err := svc.ListTasksByStatus(ctx, task.Status)
+	if err != nil {
+		return err
+	}
+	if taskOutputJSON {
+		if column == nil {
+			column = []db.Task{}
+		}
+		return json.NewEncoder(os.Stdout).Encode(column)
+	}
+	fmt.Printf("%s:\n", task.Status)
+	for i, t := range column {
+		mark := " "
+		if t.ID == id {
+			mark = ">"
+		}
+		fmt.Printf("%s %2d. %s  %s\n", mark, i+1, t.ID[:8], t.Title)
+	}
+	return nil
+}
@@ -2,19 +2,24 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"os"
 	"os/signal"
-	"path/filepath"
 	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 
 	"github.com/markx3/agentboard/internal/auth"
 	boardpkg "github.com/markx3/agentboard/internal/board"
+	"github.com/markx3/agentboard/internal/config"
 	"github.com/markx3/agentboard/internal/db"
 	"github.com/markx3/agentboard/internal/peersync"
+	"github.com/markx3/agentboard/internal/server"
 	"github.com/markx3/agentboard/internal/tui"
 )
 
@@ -40,38 +45,80 @@ func Execute() error {
 }
 
 func runBoard(cmd *cobra.Command, args []string) error {
-	dbPath := filepath.Join(".agentboard", "board.db")
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	dbPath := config.DBPath()
 	database, err := db.Open(dbPath)
 	if err != nil {
 		return fmt.Errorf("opening database: %w", err)
 	}
 	defer database.Close()
 
-	svc := boardpkg.NewLocalService(database)
-
-	var opts []tui.AppOption
-	var connector *peersync.Connector
-
-	if connectAddr != "" {
-		ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-		defer cancel()
+	svc := boardpkg.NewLocalService(database, boardpkg.WithWorkflow(cfg.BoardWorkflow()))
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	opts := []tui.AppOption{tui.WithConfig(cfg)}
+
+	// An explicit --connect wins. Otherwise follow the leader from
+	// server.json if it is alive, or become the leader ourselves. The leader
+	// lock makes sure only one of several TUIs starting at once does.
+	addr := connectAddr
+	var release func()
+	if addr == "" {
+		if info, err := peersync.ReadServerInfo(); err == nil && peersync.IsAlive(info.Addr) {
+			addr = info.Addr
+		} else if release, err = peersync.ClaimLeader(); errors.Is(err, peersync.ErrLeaderTaken) {
+			if info, err := peersync.WaitForLeader(5 * time.Second); err == nil {
+				addr = info.Addr
+			} else {
+				fmt.Fprintf(os.Stderr, "Warning: not joining leader: %v\n", err)
+			}
+		} else if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not start sync server: %v\n", err)
+		} else {
+			defer release()
+		}
+	}
 
-		token, err := auth.GetToken(ctx)
+	if addr != "" {
+		connector, err := connectPeer(ctx, addr)
 		if err != nil {
-			return fmt.Errorf("getting auth token: %w", err)
+			if connectAddr != "" {
+				return err
+			}
+			// The leader shares our database file, so running unsynced is safe.
+			fmt.Fprintf(os.Stderr, "Warning: not joining leader at %s: %v\n", addr, err)
+		} else {
+			defer connector.Close()
+			if connectAddr != "" {
+				opts = append(opts, tui.WithConnectAddr(addr), tui.WithConnector(connector))
+			} else {
+				// A leader found through server.json shares our database and
+				// tmux server, so agents stay local; the hub only tells us
+				// when the board changed.
+				opts = append(opts, tui.WithFollower(addr, connector))
+			}
 		}
-
-		connector = peersync.NewConnector(connectAddr, token)
-		if err := connector.Connect(ctx); err != nil {
-			return fmt.Errorf("connecting to server: %w", err)
+	} else if release != nil {
+		leaderAddr, hub, stop, err := startLeader(ctx, svc, cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not start sync server: %v\n", err)
+		} else {
+			defer stop()
+			opts = append(opts, tui.WithLeader(leaderAddr, hub.ClientCount))
 		}
-		defer connector.Close()
-
-		opts = append(opts, tui.WithConnectAddr(connectAddr))
 	}
 
+	// The TUI owns the terminal; log output would corrupt the screen.
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
 	app := tui.NewApp(svc, opts...)
-	_ = connector // connector messages will be handled in a future iteration
 
 	p := tea.NewProgram(app, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
@@ -80,3 +127,53 @@ func runBoard(cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+// connectPeer authenticates and connects to the server at addr.
+func connectPeer(ctx context.Context, addr string) (*peersync.Connector, error) {
+	token, err := auth.GetToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting auth token: %w", err)
+	}
+
+	connector := peersync.NewConnector(addr, token)
+	if err := connector.Connect(ctx); err != nil {
+		return nil, fmt.Errorf("connecting to server: %w", err)
+	}
+	return connector, nil
+}
+
+// startLeader runs an embedded sync server on a random local port and
+// advertises it in server.json. stop shuts the server down and removes
+// server.json if it still points at this leader.
+func startLeader(ctx context.Context, svc boardpkg.Service, cfg *config.Config) (string, *server.Hub, func(), error) {
+	authenticator, err := newAuthenticator(cfg)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	srvCtx, cancel := context.WithCancel(ctx)
+	srv := server.New(svc, "127.0.0.1", 0)
+	srv.SetAuthenticator(authenticator)
+	go func() {
+		if err := srv.Start(srvCtx); err != nil {
+			log.Printf("sync server: %v", err)
+		}
+	}()
+
+	addr, err := waitForAddr(srv)
+	if err != nil {
+		cancel()
+		return "", nil, nil, err
+	}
+	if err := peersync.WriteServerInfo(addr); err != nil {
+		cancel()
+		return "", nil, nil, fmt.Errorf("writing server info: %w", err)
+	}
+
+	stop := func() {
+		cancel()
+		if info, err := peersync.ReadServerInfo(); err == nil && info.Addr == addr {
+			peersync.RemoveServerInfo()
+		}
+	}
+	return addr, srv.Hub(), stop, nil
+}
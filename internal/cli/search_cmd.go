@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/markx3/agentboard/internal/db"
+)
+
+var (
+	searchJSON  bool
+	searchLimit int
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>...",
+	Short: "Full-text search across task titles, descriptions and comments",
+	Long: `Search task titles, descriptions and comments. Every word must match,
+as a word prefix and ignoring word endings ("fail" finds "failing").
+Tasks are listed best match first, with a snippet of the matching text.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	searchCmd.Flags().BoolVar(&searchJSON, "json", false, "output as JSON")
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 20, "maximum number of tasks to show (0 for all)")
+	rootCmd.AddCommand(searchCmd)
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	svc, cleanup, err := openService()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	hits, err := svc.Search(context.Background(), strings.Join(args, " "), searchLimit)
+	if err != nil {
+		return err
+	}
+
+	if searchJSON {
+		if hits == nil {
+			hits = []db.SearchHit{}
+		}
+		return json.NewEncoder(os.Stdout).Encode(hits)
+	}
+
+	if len(hits) == 0 {
+		fmt.Println("No matches")
+		return nil
+	}
+	for _, h := range hits {
+		fmt.Printf("%s  %-12s %s\n", h.TaskID[:8], h.Status, h.TaskTitle)
+		fmt.Printf("    %s: %s\n", h.Kind, strings.Join(strings.Fields(h.Snippet), " "))
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -12,7 +13,9 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/markx3/agentboard/internal/auth"
 	boardpkg "github.com/markx3/agentboard/internal/board"
+	"github.com/markx3/agentboard/internal/config"
 	"github.com/markx3/agentboard/internal/db"
 	"github.com/markx3/agentboard/internal/peersync"
 	"github.com/markx3/agentboard/internal/server"
@@ -22,6 +25,8 @@ import (
 var servePort int
 var serveHost string
 var serveTunnel bool
+var serveAuth string
+var serveTokenFile string
 
 var serveCmd = &cobra.Command{
 	Use:   "serve",
@@ -33,6 +38,8 @@ func init() {
 	serveCmd.Flags().IntVarP(&servePort, "port", "p", 0, "port to listen on (0 for random)")
 	serveCmd.Flags().StringVar(&serveHost, "bind", "127.0.0.1", "address to bind to")
 	serveCmd.Flags().BoolVar(&serveTunnel, "tunnel", false, "expose server via ngrok tunnel (requires NGROK_AUTHTOKEN)")
+	serveCmd.Flags().StringVar(&serveAuth, "auth", "", "peer authentication: github, secret (AGENTBOARD_SECRET) or tokens (default from config)")
+	serveCmd.Flags().StringVar(&serveTokenFile, "token-file", "", "file of \"username token\" lines for --auth tokens")
 	rootCmd.AddCommand(serveCmd)
 }
 
@@ -47,15 +54,31 @@ func runServe(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	dbPath := filepath.Join(".agentboard", "board.db")
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if serveAuth != "" {
+		cfg.Server.Auth = serveAuth
+	}
+	if serveTokenFile != "" {
+		cfg.Server.TokenFile = serveTokenFile
+	}
+	authenticator, err := newAuthenticator(cfg)
+	if err != nil {
+		return err
+	}
+
+	dbPath := config.DBPath()
 	database, err := db.Open(dbPath)
 	if err != nil {
 		return fmt.Errorf("opening database: %w", err)
 	}
 	defer database.Close()
 
-	svc := boardpkg.NewLocalService(database)
+	svc := boardpkg.NewLocalService(database, boardpkg.WithWorkflow(cfg.BoardWorkflow()))
 	srv := server.New(svc, serveHost, servePort)
+	srv.SetAuthenticator(authenticator)
 
 	if serveTunnel {
 		return runServeTunnel(ctx, srv)
@@ -89,6 +112,15 @@ func runServeTunnel(ctx context.Context, srv *server.Server) error {
 }
 
 func runServeLocal(ctx context.Context, srv *server.Server) error {
+	release, err := peersync.ClaimLeader()
+	if errors.Is(err, peersync.ErrLeaderTaken) {
+		return fmt.Errorf("a server or TUI is already the leader for this board")
+	}
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	go func() {
 		<-ctx.Done()
 		peersync.RemoveServerInfo()
@@ -101,17 +133,9 @@ func runServeLocal(ctx context.Context, srv *server.Server) error {
 		errCh <- srv.Start(ctx)
 	}()
 
-	// Wait for the server to be ready (address assigned)
-	var addr string
-	for i := 0; i < 50; i++ {
-		addr = srv.Addr()
-		if addr != "" {
-			break
-		}
-		time.Sleep(10 * time.Millisecond)
-	}
-	if addr == "" {
-		return fmt.Errorf("server did not become ready in time")
+	addr, err := waitForAddr(srv)
+	if err != nil {
+		return err
 	}
 
 	// Write server info for peer discovery
@@ -124,3 +148,28 @@ func runServeLocal(ctx context.Context, srv *server.Server) error {
 
 	return <-errCh
 }
+
+// newAuthenticator builds the sync server's authenticator from the [server]
+// config. A relative token file is resolved against the project root.
+func newAuthenticator(cfg *config.Config) (auth.Authenticator, error) {
+	tokenFile := cfg.Server.TokenFile
+	if tokenFile != "" && !filepath.IsAbs(tokenFile) {
+		tokenFile = filepath.Join(config.ProjectRoot(), tokenFile)
+	}
+	a, err := auth.New(cfg.Server.Auth, os.Getenv(auth.SecretEnv), tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("configuring server auth: %w", err)
+	}
+	return a, nil
+}
+
+// waitForAddr waits until srv is listening and returns its address.
+func waitForAddr(srv *server.Server) (string, error) {
+	for i := 0; i < 50; i++ {
+		if addr := srv.ListenAddr(); addr != "" {
+			return addr, nil
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return "", fmt.Errorf("server did not become ready in time")
+}
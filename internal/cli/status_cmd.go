@@ -5,13 +5,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/markx3/agentboard/internal/db"
 )
 
-var statusJSON bool
+var (
+	statusJSON   bool
+	statusLabels []string
+)
 
 var statusCmd = &cobra.Command{
 	Use:   "status",
@@ -21,6 +28,7 @@ var statusCmd = &cobra.Command{
 
 func init() {
 	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "output as JSON")
+	statusCmd.Flags().StringSliceVar(&statusLabels, "label", nil, "only count tasks with this label (repeatable; all must match)")
 	rootCmd.AddCommand(statusCmd)
 }
 
@@ -39,15 +47,31 @@ type enrichmentInfo struct {
 	Agent     string `json:"enrichment_agent,omitempty"`
 }
 
+// urgentInfo is an unfinished task with a priority or due date.
+type urgentInfo struct {
+	TaskID    string `json:"task_id"`
+	TaskTitle string `json:"task_title"`
+	Column    string `json:"column"`
+	Priority  string `json:"priority,omitempty"`
+	DueDate   string `json:"due_date,omitempty"`
+	Overdue   bool   `json:"overdue,omitempty"`
+}
+
 type boardSummary struct {
 	Columns            map[string]int   `json:"columns"`
 	Total              int              `json:"total"`
 	Agents             []agentInfo      `json:"agents,omitempty"`
 	Enrichments        []enrichmentInfo `json:"enrichments,omitempty"`
+	Labels             map[string]int   `json:"labels,omitempty"`
+	Urgent             []urgentInfo     `json:"urgent,omitempty"`
 	PendingSuggestions int              `json:"pending_suggestions"`
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
 	svc, cleanup, err := openService()
 	if err != nil {
 		return err
@@ -59,6 +83,9 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if len(statusLabels) > 0 {
+		tasks = filterTasksByLabels(tasks, statusLabels)
+	}
 
 	counts := make(map[string]int)
 	var agents []agentInfo
@@ -93,18 +120,42 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		pendingSuggestions = len(suggestions)
 	}
 
+	// Every column is listed, empty or not. Tasks left in columns that are
+	// no longer configured still show up under their own status.
+	wf := cfg.BoardWorkflow()
+
+	var open []db.Task
+	for _, t := range tasks {
+		if t.Status != wf.Last() && (t.Priority != db.PriorityNone || t.DueDate != "") {
+			open = append(open, t)
+		}
+	}
+	slices.SortStableFunc(open, db.CompareUrgency)
+	now := time.Now()
+	urgent := make([]urgentInfo, len(open))
+	for i, t := range open {
+		urgent[i] = urgentInfo{
+			TaskID:    t.ID[:8],
+			TaskTitle: t.Title,
+			Column:    string(t.Status),
+			Priority:  string(t.Priority),
+			DueDate:   t.DueDate,
+			Overdue:   t.Overdue(now, wf.Last()),
+		}
+	}
+	for _, status := range wf.Statuses() {
+		if _, ok := counts[string(status)]; !ok {
+			counts[string(status)] = 0
+		}
+	}
+
 	summary := boardSummary{
-		Columns: map[string]int{
-			string(db.StatusBacklog):    counts[string(db.StatusBacklog)],
-			string(db.StatusBrainstorm): counts[string(db.StatusBrainstorm)],
-			string(db.StatusPlanning):   counts[string(db.StatusPlanning)],
-			string(db.StatusInProgress): counts[string(db.StatusInProgress)],
-			string(db.StatusReview):     counts[string(db.StatusReview)],
-			string(db.StatusDone):       counts[string(db.StatusDone)],
-		},
+		Columns:            counts,
 		Total:              len(tasks),
 		Agents:             agents,
 		Enrichments:        enrichments,
+		Labels:             labelCounts(tasks),
+		Urgent:             urgent,
 		PendingSuggestions: pendingSuggestions,
 	}
 
@@ -114,15 +165,46 @@ func runStatus(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("Agentboard Status\n")
 	fmt.Printf("─────────────────\n")
-	fmt.Printf("Backlog:     %d\n", summary.Columns[string(db.StatusBacklog)])
-	fmt.Printf("Brainstorm:  %d\n", summary.Columns[string(db.StatusBrainstorm)])
-	fmt.Printf("Planning:    %d\n", summary.Columns[string(db.StatusPlanning)])
-	fmt.Printf("In Progress: %d\n", summary.Columns[string(db.StatusInProgress)])
-	fmt.Printf("Review:      %d\n", summary.Columns[string(db.StatusReview)])
-	fmt.Printf("Done:        %d\n", summary.Columns[string(db.StatusDone)])
+	for _, c := range wf.Columns() {
+		fmt.Printf("%-13s%d\n", c.Name+":", summary.Columns[string(c.Status)])
+	}
 	fmt.Printf("─────────────────\n")
 	fmt.Printf("Total:       %d\n", summary.Total)
 
+	if len(summary.Labels) > 0 {
+		names := make([]string, 0, len(summary.Labels))
+		for name := range summary.Labels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Printf("\nLabels:\n")
+		for _, name := range names {
+			fmt.Printf("  %-11s%d\n", name+":", summary.Labels[name])
+		}
+	}
+
+	if len(urgent) > 0 {
+		fmt.Printf("\nMost Urgent:\n")
+		for i, u := range urgent {
+			if i == 5 {
+				fmt.Printf("  ... and %d more\n", len(urgent)-i)
+				break
+			}
+			var tags []string
+			if u.Priority != "" {
+				tags = append(tags, u.Priority)
+			}
+			if u.DueDate != "" {
+				due := "due " + u.DueDate
+				if u.Overdue {
+					due += " (overdue)"
+				}
+				tags = append(tags, due)
+			}
+			fmt.Printf("  %s: %s [%s] in %s\n", u.TaskID, u.TaskTitle, strings.Join(tags, ", "), u.Column)
+		}
+	}
+
 	if len(agents) > 0 {
 		fmt.Printf("\nActive Agents:\n")
 		for _, a := range agents {
@@ -3,22 +3,30 @@ package cli
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
+	"slices"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/markx3/agentboard/internal/agent"
+	"github.com/markx3/agentboard/internal/auth"
 	boardpkg "github.com/markx3/agentboard/internal/board"
+	"github.com/markx3/agentboard/internal/config"
 	"github.com/markx3/agentboard/internal/db"
+	"github.com/markx3/agentboard/internal/peersync"
 )
 
 var (
 	taskFilterStatus   string
 	taskFilterAssignee string
 	taskFilterSearch   string
+	taskFilterLabels   []string
+	taskSortBy         string
 	taskOutputJSON     bool
 )
 
@@ -88,6 +96,13 @@ var taskCommentCmd = &cobra.Command{
 	RunE:  runTaskComment,
 }
 
+var taskHistoryCmd = &cobra.Command{
+	Use:   "history <task-id>",
+	Short: "Show who changed a task and when",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTaskHistory,
+}
+
 var taskBlockCmd = &cobra.Command{
 	Use:   "block <task-id> <blocker-id>",
 	Short: "Mark a task as blocked by another",
@@ -141,10 +156,12 @@ var taskSuggestionDismissCmd = &cobra.Command{
 }
 
 var (
-	createTitle string
-	createDesc  string
-	claimUser   string
-	doEnrich    bool
+	createTitle    string
+	createDesc     string
+	createPriority string
+	createDue      string
+	claimUser      string
+	doEnrich       bool
 
 	// task update flags
 	updateTitle            string
@@ -155,6 +172,8 @@ var (
 	updateAddDep           string
 	updateRemoveDep        string
 	updateEnrichmentStatus string
+	updatePriority         string
+	updateDue              string
 
 	// task comment flags
 	commentAuthor string
@@ -180,11 +199,15 @@ func init() {
 
 	taskListCmd.Flags().StringVar(&taskFilterStatus, "status", "", "filter by status")
 	taskListCmd.Flags().StringVar(&taskFilterAssignee, "assignee", "", "filter by assignee")
-	taskListCmd.Flags().StringVar(&taskFilterSearch, "search", "", "filter by title/description substring (case-insensitive)")
+	taskListCmd.Flags().StringVar(&taskFilterSearch, "search", "", "filter by title/description substring, or a full-text match that includes comments")
+	taskListCmd.Flags().StringSliceVar(&taskFilterLabels, "label", nil, "only tasks with this label (repeatable; all must match)")
+	taskListCmd.Flags().StringVar(&taskSortBy, "sort", "position", "sort by position, priority, due, created or updated")
 
 	taskCreateCmd.Flags().StringVar(&createTitle, "title", "", "task title (required)")
 	taskCreateCmd.Flags().StringVar(&createDesc, "description", "", "task description")
 	taskCreateCmd.Flags().BoolVar(&doEnrich, "enrich", false, "trigger automatic enrichment on creation")
+	taskCreateCmd.Flags().StringVar(&createPriority, "priority", "", "priority: low, medium, high or urgent")
+	taskCreateCmd.Flags().StringVar(&createDue, "due", "", "due date (YYYY-MM-DD, today or tomorrow)")
 	taskCreateCmd.MarkFlagRequired("title")
 
 	taskClaimCmd.Flags().StringVar(&claimUser, "user", "", "username to claim as")
@@ -198,6 +221,8 @@ func init() {
 	taskUpdateCmd.Flags().StringVar(&updateAddDep, "add-dep", "", "add dependency (task ID prefix)")
 	taskUpdateCmd.Flags().StringVar(&updateRemoveDep, "remove-dep", "", "remove dependency (task ID prefix)")
 	taskUpdateCmd.Flags().StringVar(&updateEnrichmentStatus, "enrichment-status", "", "set enrichment status")
+	taskUpdateCmd.Flags().StringVar(&updatePriority, "priority", "", "set priority: low, medium, high, urgent (empty clears)")
+	taskUpdateCmd.Flags().StringVar(&updateDue, "due", "", "set due date: YYYY-MM-DD, today, tomorrow (empty clears)")
 
 	// task comment flags
 	taskCommentCmd.Flags().StringVar(&commentAuthor, "author", "", "comment author (required)")
@@ -224,23 +249,95 @@ func init() {
 	taskSuggestionCmd.AddCommand(taskSuggestionAcceptCmd, taskSuggestionDismissCmd)
 	taskCmd.AddCommand(
 		taskListCmd, taskCreateCmd, taskMoveCmd, taskGetCmd, taskDeleteCmd,
-		taskClaimCmd, taskUnclaimCmd, taskUpdateCmd, taskCommentCmd,
+		taskClaimCmd, taskUnclaimCmd, taskUpdateCmd, taskCommentCmd, taskHistoryCmd,
 		taskBlockCmd, taskUnblockCmd,
 		taskSuggestCmd, taskProposeCmd, taskSuggestionsCmd, taskSuggestionCmd,
 	)
 	rootCmd.AddCommand(taskCmd)
 }
 
+// openService opens the board the command acts on: the sync server named by
+// --connect or AGENTBOARD_CONNECT if set, otherwise the local database.
 func openService() (boardpkg.Service, func(), error) {
-	dbPath := filepath.Join(".agentboard", "board.db")
+	addr := remoteAddr()
+	if addr == "" {
+		svc, cleanup, err := openLocalService()
+		if err != nil {
+			return nil, nil, err
+		}
+		return svc, cleanup, nil
+	}
+	ctx := context.Background()
+	token, err := auth.GetToken(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting auth token: %w", err)
+	}
+	svc, err := peersync.DialService(ctx, addr, token)
+	if err != nil {
+		return nil, nil, err
+	}
+	return svc, func() { svc.Close() }, nil
+}
+
+// remoteAddr returns the sync server to use, or "" for the local board.
+// Without a local board, a live server advertised in server.json is used.
+func remoteAddr() string {
+	if connectAddr != "" {
+		return connectAddr
+	}
+	if addr := os.Getenv("AGENTBOARD_CONNECT"); addr != "" {
+		return addr
+	}
+	if _, err := os.Stat(config.DBPath()); err == nil {
+		return ""
+	}
+	if info, err := peersync.ReadServerInfo(); err == nil && peersync.IsAlive(info.Addr) {
+		return info.Addr
+	}
+	return ""
+}
+
+// openLocalService opens the local database. Commands that manage agents or
+// worktrees on this machine use it instead of openService.
+func openLocalService() (*boardpkg.LocalService, func(), error) {
+	if connectAddr != "" {
+		return nil, nil, fmt.Errorf("this command works on the local board only and can't be used with --connect")
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	dbPath := config.DBPath()
 	database, err := db.Open(dbPath)
 	if err != nil {
 		return nil, nil, fmt.Errorf("opening database: %w", err)
 	}
-	svc := boardpkg.NewLocalService(database)
+	svc := boardpkg.NewLocalService(database, boardpkg.WithWorkflow(cfg.BoardWorkflow()))
 	return svc, func() { database.Close() }, nil
 }
 
+// loadConfig reads .agentboard/config.toml, falling back to defaults when
+// the file does not exist.
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	// Every command that picks or names a runner loads the config first.
+	agent.SetCustomRunners(cfg.Agent.Runners)
+	return cfg, nil
+}
+
+// lastColumn returns the board's last workflow column, where tasks are
+// finished.
+func lastColumn() (db.TaskStatus, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return "", err
+	}
+	return cfg.BoardWorkflow().Last(), nil
+}
+
 func runTaskList(cmd *cobra.Command, args []string) error {
 	svc, cleanup, err := openService()
 	if err != nil {
@@ -271,7 +368,23 @@ func runTaskList(cmd *cobra.Command, args []string) error {
 	}
 
 	if taskFilterSearch != "" {
-		tasks = filterTasksBySearch(tasks, taskFilterSearch)
+		hits, err := svc.Search(ctx, taskFilterSearch, 0)
+		if err != nil {
+			return err
+		}
+		matched := make(map[string]bool, len(hits))
+		for _, h := range hits {
+			matched[h.TaskID] = true
+		}
+		tasks = filterTasksBySearch(tasks, taskFilterSearch, matched)
+	}
+
+	if len(taskFilterLabels) > 0 {
+		tasks = filterTasksByLabels(tasks, taskFilterLabels)
+	}
+
+	if err := sortTasks(tasks, taskSortBy); err != nil {
+		return err
 	}
 
 	// Populate dependency data
@@ -288,15 +401,27 @@ func runTaskList(cmd *cobra.Command, args []string) error {
 		return json.NewEncoder(os.Stdout).Encode(tasks)
 	}
 
+	last, err := lastColumn()
+	if err != nil {
+		return err
+	}
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "ID\tTITLE\tSTATUS\tASSIGNEE\tAGENT")
+	fmt.Fprintln(w, "ID\tTITLE\tSTATUS\tPRIORITY\tDUE\tASSIGNEE\tAGENT\tLABELS")
+	now := time.Now()
 	for _, t := range tasks {
 		agentCol := string(t.AgentStatus)
 		if t.AgentName != "" {
 			agentCol = fmt.Sprintf("%s (%s)", t.AgentName, t.AgentStatus)
 		}
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
-			t.ID[:8], t.Title, t.Status, t.Assignee, agentCol)
+		if t.AgentQueued {
+			agentCol += " [queued]"
+		}
+		due := t.DueDate
+		if t.Overdue(now, last) {
+			due += " (overdue)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			t.ID[:8], t.Title, t.Status, t.Priority, due, t.Assignee, agentCol, labelList(t.Labels))
 	}
 	return w.Flush()
 }
@@ -308,6 +433,15 @@ func runTaskCreate(cmd *cobra.Command, args []string) error {
 	}
 	defer cleanup()
 
+	priority, err := parsePriority(createPriority)
+	if err != nil {
+		return err
+	}
+	due, err := parseDueDate(createDue, time.Now())
+	if err != nil {
+		return err
+	}
+
 	task, err := svc.CreateTask(context.Background(), createTitle, createDesc)
 	if err != nil {
 		return err
@@ -322,6 +456,17 @@ func runTaskCreate(cmd *cobra.Command, args []string) error {
 		task.EnrichmentStatus = pending
 	}
 
+	if priority != db.PriorityNone || due != "" {
+		if err := svc.UpdateTaskFields(context.Background(), task.ID, db.TaskFieldUpdate{
+			Priority: &priority,
+			DueDate:  &due,
+		}); err != nil {
+			return fmt.Errorf("setting priority and due date: %w", err)
+		}
+		task.Priority = priority
+		task.DueDate = due
+	}
+
 	if taskOutputJSON {
 		return json.NewEncoder(os.Stdout).Encode(task)
 	}
@@ -339,9 +484,6 @@ func runTaskMove(cmd *cobra.Command, args []string) error {
 
 	taskID := args[0]
 	newStatus := db.TaskStatus(args[1])
-	if !newStatus.Valid() {
-		return fmt.Errorf("invalid status: %s (use: backlog, brainstorm, planning, in_progress, review, done)", args[1])
-	}
 
 	// Find task by prefix
 	tasks, err := svc.ListTasks(context.Background())
@@ -354,6 +496,14 @@ func runTaskMove(cmd *cobra.Command, args []string) error {
 	}
 
 	if err := svc.MoveTask(context.Background(), fullID, newStatus); err != nil {
+		// Agents read --json output, so refused moves say why in JSON too.
+		var terr *boardpkg.TransitionError
+		if taskOutputJSON && errors.As(err, &terr) {
+			json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				"error":      terr.Error(),
+				"transition": terr,
+			})
+		}
 		return err
 	}
 
@@ -436,9 +586,29 @@ func runTaskGet(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Agent:       %s (%s)\n", task.AgentName, task.AgentStatus)
 	fmt.Printf("Branch:      %s\n", task.BranchName)
 	fmt.Printf("PR:          %s\n", task.PRUrl)
+	if task.IssueNumber > 0 {
+		fmt.Printf("Issue:       %s#%d\n", task.IssueRepo, task.IssueNumber)
+	}
 	if task.EnrichmentStatus != "" {
 		fmt.Printf("Enrichment:  %s\n", task.EnrichmentStatus)
 	}
+	if len(task.Labels) > 0 {
+		fmt.Printf("Labels:      %s\n", labelList(task.Labels))
+	}
+	if task.Priority != db.PriorityNone {
+		fmt.Printf("Priority:    %s\n", task.Priority)
+	}
+	if task.DueDate != "" {
+		last, err := lastColumn()
+		if err != nil {
+			return err
+		}
+		overdue := ""
+		if task.Overdue(time.Now(), last) {
+			overdue = " (overdue)"
+		}
+		fmt.Printf("Due:         %s%s\n", task.DueDate, overdue)
+	}
 	if len(task.BlockedBy) > 0 {
 		var shortIDs []string
 		for _, id := range task.BlockedBy {
@@ -466,6 +636,12 @@ func runTaskGet(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
+	if len(task.Checklist) > 0 {
+		done, total := task.ChecklistProgress()
+		fmt.Printf("\nChecklist (%d/%d):\n", done, total)
+		printChecklist(task.Checklist)
+	}
+
 	// Show comments
 	comments, _ := svc.ListComments(ctx, task.ID)
 	if len(comments) > 0 {
@@ -501,6 +677,12 @@ func runTaskDelete(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if task != nil {
+		if dir, err := agent.FindWorktree(*task); err == nil && dir != "" {
+			fmt.Fprintf(os.Stderr, "warning: worktree for this task remains at %s (remove it with `agentboard worktree prune`)\n", dir)
+		}
+	}
+
 	if taskOutputJSON && task != nil {
 		return json.NewEncoder(os.Stdout).Encode(map[string]string{"deleted": fullID})
 	}
@@ -622,6 +804,20 @@ func runTaskUpdate(cmd *cobra.Command, args []string) error {
 		}
 		update.EnrichmentStatus = &es
 	}
+	if cmd.Flags().Changed("priority") {
+		priority, err := parsePriority(updatePriority)
+		if err != nil {
+			return err
+		}
+		update.Priority = &priority
+	}
+	if cmd.Flags().Changed("due") {
+		due, err := parseDueDate(updateDue, time.Now())
+		if err != nil {
+			return err
+		}
+		update.DueDate = &due
+	}
 
 	if err := svc.UpdateTaskFields(ctx, fullID, update); err != nil {
 		return err
@@ -686,6 +882,68 @@ func runTaskComment(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runTaskHistory(cmd *cobra.Command, args []string) error {
+	svc, cleanup, err := openService()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	ctx := context.Background()
+	tasks, err := svc.ListTasks(ctx)
+	if err != nil {
+		return err
+	}
+	fullID := findByPrefix(tasks, args[0])
+	if fullID == "" {
+		return fmt.Errorf("task not found: %s", args[0])
+	}
+
+	events, err := svc.ListTaskEvents(ctx, fullID)
+	if err != nil {
+		return err
+	}
+
+	if taskOutputJSON {
+		if events == nil {
+			events = []db.TaskEvent{}
+		}
+		return json.NewEncoder(os.Stdout).Encode(events)
+	}
+
+	if len(events) == 0 {
+		fmt.Println("No history")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tACTOR\tFIELD\tCHANGE")
+	for _, ev := range events {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			ev.CreatedAt.Local().Format("2006-01-02 15:04"), ev.Actor, ev.Field, eventChange(ev))
+	}
+	return w.Flush()
+}
+
+// eventChange renders an event's values on one line.
+func eventChange(ev db.TaskEvent) string {
+	switch {
+	case ev.OldValue == "":
+		return historyValue(ev.NewValue)
+	case ev.NewValue == "":
+		return "removed " + historyValue(ev.OldValue)
+	}
+	return historyValue(ev.OldValue) + " -> " + historyValue(ev.NewValue)
+}
+
+func historyValue(v string) string {
+	r := []rune(strings.Join(strings.Fields(v), " "))
+	if len(r) > 40 {
+		return string(r[:37]) + "..."
+	}
+	return string(r)
+}
+
 func runTaskBlock(cmd *cobra.Command, args []string) error {
 	svc, cleanup, err := openService()
 	if err != nil {
@@ -899,11 +1157,13 @@ func runTaskSuggestionDismiss(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func filterTasksBySearch(tasks []db.Task, q string) []db.Task {
+// filterTasksBySearch keeps tasks whose title or description contains q,
+// ignoring case, or whose ID is in matched (full-text search hits).
+func filterTasksBySearch(tasks []db.Task, q string, matched map[string]bool) []db.Task {
 	q = strings.ToLower(q)
 	var out []db.Task
 	for _, t := range tasks {
-		if strings.Contains(strings.ToLower(t.Title), q) ||
+		if matched[t.ID] || strings.Contains(strings.ToLower(t.Title), q) ||
 			strings.Contains(strings.ToLower(t.Description), q) {
 			out = append(out, t)
 		}
@@ -911,6 +1171,64 @@ func filterTasksBySearch(tasks []db.Task, q string) []db.Task {
 	return out
 }
 
+// parsePriority checks a --priority value. The empty string means none.
+func parsePriority(s string) (db.TaskPriority, error) {
+	p := db.TaskPriority(strings.ToLower(strings.TrimSpace(s)))
+	if !p.Valid() {
+		return "", fmt.Errorf("invalid priority %q (use: low, medium, high, urgent)", s)
+	}
+	return p, nil
+}
+
+// parseDueDate turns a --due value into a date in db.DueDateLayout. It
+// accepts "today", "tomorrow" and YYYY-MM-DD; the empty string means none.
+func parseDueDate(s string, now time.Time) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return "", nil
+	case "today":
+		return now.Format(db.DueDateLayout), nil
+	case "tomorrow":
+		return now.AddDate(0, 0, 1).Format(db.DueDateLayout), nil
+	}
+	if !db.ValidDueDate(s) {
+		return "", fmt.Errorf("invalid due date %q (use YYYY-MM-DD, today or tomorrow)", s)
+	}
+	return s, nil
+}
+
+// sortTasks orders tasks for `task list --sort`. Position keeps the
+// board's order.
+func sortTasks(tasks []db.Task, by string) error {
+	var cmp func(a, b db.Task) int
+	switch by {
+	case "", "position":
+		return nil
+	case "priority":
+		cmp = db.CompareUrgency
+	case "due":
+		cmp = func(a, b db.Task) int {
+			switch {
+			case a.DueDate == b.DueDate:
+				return db.CompareUrgency(a, b)
+			case a.DueDate == "":
+				return 1
+			case b.DueDate == "":
+				return -1
+			}
+			return strings.Compare(a.DueDate, b.DueDate)
+		}
+	case "created":
+		cmp = func(a, b db.Task) int { return a.CreatedAt.Compare(b.CreatedAt) }
+	case "updated":
+		cmp = func(a, b db.Task) int { return b.UpdatedAt.Compare(a.UpdatedAt) }
+	default:
+		return fmt.Errorf("invalid sort %q (use: position, priority, due, created, updated)", by)
+	}
+	slices.SortStableFunc(tasks, cmp)
+	return nil
+}
+
 func findByPrefix(tasks []db.Task, prefix string) string {
 	for _, t := range tasks {
 		if len(t.ID) >= len(prefix) && t.ID[:len(prefix)] == prefix {
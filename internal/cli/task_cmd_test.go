@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/markx3/agentboard/internal/db"
 )
@@ -31,7 +33,7 @@ func TestFilterTasksBySearch(t *testing.T) {
 				// empty query not handled by filterTasksBySearch, skip
 				return
 			}
-			got := filterTasksBySearch(tasks, tt.query)
+			got := filterTasksBySearch(tasks, tt.query, nil)
 			if len(got) != len(tt.wantIDs) {
 				t.Errorf("filterTasksBySearch(%q) got %d results, want %d", tt.query, len(got), len(tt.wantIDs))
 				return
@@ -44,3 +46,118 @@ func TestFilterTasksBySearch(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterTasksBySearchKeepsMatched(t *testing.T) {
+	tasks := []db.Task{
+		{ID: "1", Title: "Fix login bug"},
+		{ID: "2", Title: "Add dark mode"},
+	}
+	// Task 2 only matched in a comment, which the substring check can't see.
+	got := filterTasksBySearch(tasks, "login", map[string]bool{"2": true})
+	if len(got) != 2 {
+		t.Errorf("got %d tasks, want both the substring and the full-text match", len(got))
+	}
+}
+
+func TestFilterTasksByLabels(t *testing.T) {
+	bug := db.Label{Name: "bug"}
+	ui := db.Label{Name: "ui"}
+	tasks := []db.Task{
+		{ID: "1", Labels: []db.Label{bug}},
+		{ID: "2", Labels: []db.Label{bug, ui}},
+		{ID: "3"},
+	}
+
+	got := filterTasksByLabels(tasks, []string{"bug"})
+	if len(got) != 2 {
+		t.Errorf("--label bug: got %d tasks, want 2", len(got))
+	}
+	got = filterTasksByLabels(tasks, []string{"Bug", "ui"})
+	if len(got) != 1 || got[0].ID != "2" {
+		t.Errorf("--label Bug --label ui: got %+v, want task 2", got)
+	}
+}
+
+func TestParseDueDate(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{"", "", false},
+		{"today", "2026-10-17", false},
+		{"Tomorrow", "2026-10-18", false},
+		{"2026-12-24", "2026-12-24", false},
+		{"24/12/2026", "", true},
+		{"friday", "", true},
+	}
+	for _, tt := range tests {
+		got, err := parseDueDate(tt.in, now)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseDueDate(%q) = %q, %v; want %q (error: %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSortTasks(t *testing.T) {
+	tasks := []db.Task{
+		{ID: "a", Priority: db.PriorityLow, DueDate: "2026-01-10"},
+		{ID: "b", Priority: db.PriorityUrgent},
+		{ID: "c", DueDate: "2026-01-05"},
+	}
+
+	ids := func() string {
+		var s []string
+		for _, task := range tasks {
+			s = append(s, task.ID)
+		}
+		return strings.Join(s, ",")
+	}
+
+	if err := sortTasks(tasks, "priority"); err != nil || ids() != "b,a,c" {
+		t.Errorf("sort by priority = %s (%v), want b,a,c", ids(), err)
+	}
+	if err := sortTasks(tasks, "due"); err != nil || ids() != "c,a,b" {
+		t.Errorf("sort by due = %s (%v), want c,a,b", ids(), err)
+	}
+	if err := sortTasks(tasks, "size"); err == nil {
+		t.Error("unknown sort key accepted")
+	}
+}
+
+func TestChecklistItemIDs(t *testing.T) {
+	items := []db.ChecklistItem{{ID: 7, Text: "a"}, {ID: 9, Text: "b"}}
+	ids, err := checklistItemIDs(items, []string{"2", "1"})
+	if err != nil {
+		t.Fatalf("checklistItemIDs: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != 9 || ids[1] != 7 {
+		t.Errorf("got %v, want [9 7]", ids)
+	}
+	for _, bad := range []string{"0", "3", "x"} {
+		if _, err := checklistItemIDs(items, []string{bad}); err == nil {
+			t.Errorf("checklistItemIDs(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestColumnEnd(t *testing.T) {
+	tasks := []db.Task{
+		{ID: "a", Status: db.StatusBacklog},
+		{ID: "b", Status: db.StatusBacklog},
+		{ID: "c", Status: db.StatusBacklog},
+		{ID: "d", Status: db.StatusPlanning},
+	}
+	if before, after := columnEnd(tasks, "b", true); before != "a" || after != "" {
+		t.Errorf("top = (%q, %q), want before a", before, after)
+	}
+	if before, after := columnEnd(tasks, "b", false); before != "" || after != "c" {
+		t.Errorf("bottom = (%q, %q), want after c", before, after)
+	}
+	if before, after := columnEnd(tasks, "c", false); after != "b" {
+		t.Errorf("bottom of last task = (%q, %q), want after b", before, after)
+	}
+	if before, after := columnEnd(tasks, "d", true); before != "" || after != "" {
+		t.Errorf("alone in column = (%q, %q), want no target", before, after)
+	}
+}
@@ -0,0 +1,339 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/markx3/agentboard/internal/agent"
+	"github.com/markx3/agentboard/internal/config"
+	"github.com/markx3/agentboard/internal/db"
+	"github.com/markx3/agentboard/internal/worktree"
+)
+
+var worktreeCmd = &cobra.Command{
+	Use:   "worktree",
+	Short: "Manage task worktrees",
+}
+
+var worktreeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List task worktrees with their task, dirty and unpushed state",
+	RunE:  runWorktreeList,
+}
+
+var worktreePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove worktrees of done or deleted tasks",
+	Long:  "Removes worktrees whose task is done or no longer exists. Worktrees with uncommitted changes, a running agent or a state git can't read are skipped unless --force is given.",
+	RunE:  runWorktreePrune,
+}
+
+var worktreeRemoveCmd = &cobra.Command{
+	Use:   "remove <task-id>",
+	Short: "Remove a task's worktree",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWorktreeRemove,
+}
+
+var (
+	worktreeForce        bool
+	worktreeDeleteBranch bool
+	worktreeOutputJSON   bool
+)
+
+func init() {
+	worktreeListCmd.Flags().BoolVar(&worktreeOutputJSON, "json", false, "output as JSON")
+	for _, c := range []*cobra.Command{worktreePruneCmd, worktreeRemoveCmd} {
+		c.Flags().BoolVar(&worktreeForce, "force", false, "remove even with uncommitted changes or a running agent")
+		c.Flags().BoolVar(&worktreeDeleteBranch, "delete-branch", false, "also delete the task branch (unmerged branches need --force unless gh shows their pull request merged)")
+		c.Flags().BoolVar(&worktreeOutputJSON, "json", false, "output as JSON")
+	}
+
+	worktreeCmd.AddCommand(worktreeListCmd, worktreePruneCmd, worktreeRemoveCmd)
+	rootCmd.AddCommand(worktreeCmd)
+}
+
+// worktreeEntry is a task worktree cross-referenced with the tasks table.
+type worktreeEntry struct {
+	Path        string        `json:"path"`
+	Branch      string        `json:"branch"`
+	TaskID      string        `json:"task_id,omitempty"`
+	TaskTitle   string        `json:"task_title,omitempty"`
+	TaskStatus  db.TaskStatus `json:"task_status,omitempty"`
+	AgentActive bool          `json:"agent_active"`
+	Orphan      bool          `json:"orphan"`
+	Dirty       bool          `json:"dirty"`
+	Unpushed    int           `json:"unpushed"`
+	// Error says why the dirty and unpushed state could not be read.
+	Error string `json:"error,omitempty"`
+}
+
+// matchWorktrees pairs linked worktrees with the tasks that own them by
+// branch. Worktrees outside managedDir are only kept when a task owns them.
+func matchWorktrees(wts []worktree.Worktree, tasks []db.Task, managedDir string) []worktreeEntry {
+	byBranch := make(map[string]db.Task, len(tasks))
+	for _, t := range tasks {
+		// A recorded branch wins over a slug-derived one.
+		b := agent.TaskBranch(t)
+		if _, ok := byBranch[b]; !ok || t.BranchName != "" {
+			byBranch[b] = t
+		}
+	}
+
+	var entries []worktreeEntry
+	for i, wt := range wts {
+		if i == 0 || wt.Bare {
+			continue // main worktree
+		}
+		e := worktreeEntry{Path: wt.Path, Branch: wt.Branch}
+		task, owned := byBranch[wt.Branch]
+		if wt.Branch == "" {
+			owned = false
+		}
+		if owned {
+			e.TaskID = task.ID
+			e.TaskTitle = task.Title
+			e.TaskStatus = task.Status
+			e.AgentActive = task.AgentStatus == db.AgentActive
+		} else {
+			if !isWithin(wt.Path, managedDir) {
+				continue
+			}
+			e.Orphan = true
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != "." && !strings.HasPrefix(rel, "..")
+}
+
+// runningInside reports whether the working directory is dir or below it.
+func runningInside(dir string) bool {
+	wd, err := os.Getwd()
+	if err != nil {
+		return false
+	}
+	return wd == dir || isWithin(wd, dir)
+}
+
+// loadWorktreeEntries lists task worktrees with their dirty/unpushed state
+// and returns the repository root alongside them.
+func loadWorktreeEntries(tasks []db.Task) (string, []worktreeEntry, error) {
+	root, err := worktree.RepoRoot(config.ProjectRoot())
+	if err != nil {
+		return "", nil, err
+	}
+	if err := worktree.Prune(root); err != nil {
+		return "", nil, err
+	}
+	wts, err := worktree.List(root)
+	if err != nil {
+		return "", nil, err
+	}
+
+	project, err := filepath.Abs(config.ProjectRoot())
+	if err != nil {
+		return "", nil, err
+	}
+	entries := matchWorktrees(wts, tasks, filepath.Join(project, agent.WorktreesDir))
+	for i := range entries {
+		e := &entries[i]
+		var err error
+		if e.Dirty, err = worktree.IsDirty(e.Path); err == nil {
+			e.Unpushed, err = worktree.Unpushed(e.Path)
+		}
+		if err != nil {
+			e.Error = err.Error()
+		}
+	}
+	return root, entries, nil
+}
+
+// listTasks opens the board just long enough to read all tasks.
+func listTasks() ([]db.Task, error) {
+	svc, cleanup, err := openLocalService()
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	return svc.ListTasks(context.Background())
+}
+
+func runWorktreeList(cmd *cobra.Command, args []string) error {
+	tasks, err := listTasks()
+	if err != nil {
+		return err
+	}
+	_, entries, err := loadWorktreeEntries(tasks)
+	if err != nil {
+		return err
+	}
+
+	if worktreeOutputJSON {
+		if entries == nil {
+			entries = []worktreeEntry{}
+		}
+		return json.NewEncoder(os.Stdout).Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No task worktrees")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TASK\tSTATUS\tBRANCH\tDIRTY\tUNPUSHED\tPATH")
+	for _, e := range entries {
+		taskCol, statusCol := "-", "orphaned"
+		if !e.Orphan {
+			taskCol = e.TaskID[:8]
+			statusCol = string(e.TaskStatus)
+		}
+		dirty := ""
+		switch {
+		case e.Error != "":
+			dirty = "unknown"
+		case e.Dirty:
+			dirty = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n",
+			taskCol, statusCol, e.Branch, dirty, e.Unpushed, e.Path)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.Error != "" {
+			fmt.Fprintf(os.Stderr, "warning: %s: %s\n", e.Path, e.Error)
+		}
+	}
+	return nil
+}
+
+// removeEntry removes one worktree, honoring --force and --delete-branch.
+func removeEntry(root string, e worktreeEntry) error {
+	if !worktreeForce {
+		// The task's own agent may remove its worktree as its last step.
+		if e.AgentActive && !runningInside(e.Path) {
+			return fmt.Errorf("%s: agent is still running (use --force to remove anyway)", e.Path)
+		}
+		if e.Error != "" {
+			return fmt.Errorf("%s: can't tell whether it has uncommitted changes: %s (use --force to remove anyway)", e.Path, e.Error)
+		}
+		if e.Dirty {
+			return fmt.Errorf("%s: has uncommitted changes (use --force to discard them)", e.Path)
+		}
+	}
+	if err := worktree.Remove(root, e.Path, worktreeForce); err != nil {
+		return err
+	}
+	if worktreeDeleteBranch && e.Branch != "" {
+		err := worktree.DeleteBranch(root, e.Branch, worktreeForce)
+		// Squash and rebase merges leave the branch unmerged as far as git
+		// can tell; a merged pull request is proof enough.
+		if err != nil && !worktreeForce && prMerged(root, e.Branch) {
+			err = worktree.DeleteBranch(root, e.Branch, true)
+		}
+		if err != nil {
+			return fmt.Errorf("worktree removed, but deleting branch %s failed: %w", e.Branch, err)
+		}
+	}
+	return nil
+}
+
+// prMerged reports whether gh knows of a merged pull request for branch.
+// Without gh, or without a pull request, it is false.
+func prMerged(root, branch string) bool {
+	cmd := exec.Command("gh", "pr", "view", branch, "--json", "state", "--jq", ".state")
+	cmd.Dir = root
+	out, err := cmd.Output()
+	return err == nil && strings.TrimSpace(string(out)) == "MERGED"
+}
+
+func runWorktreeRemove(cmd *cobra.Command, args []string) error {
+	tasks, err := listTasks()
+	if err != nil {
+		return err
+	}
+	fullID := findByPrefix(tasks, args[0])
+	if fullID == "" {
+		return fmt.Errorf("task not found: %s", args[0])
+	}
+	root, entries, err := loadWorktreeEntries(tasks)
+	if err != nil {
+		return err
+	}
+
+	var entry *worktreeEntry
+	for i := range entries {
+		if entries[i].TaskID == fullID {
+			entry = &entries[i]
+			break
+		}
+	}
+	if entry == nil {
+		return fmt.Errorf("task %s has no worktree", fullID[:8])
+	}
+
+	if err := removeEntry(root, *entry); err != nil {
+		return err
+	}
+
+	if worktreeOutputJSON {
+		return json.NewEncoder(os.Stdout).Encode(entry)
+	}
+	fmt.Printf("Removed worktree %s\n", entry.Path)
+	return nil
+}
+
+func runWorktreePrune(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	done := cfg.BoardWorkflow().Last()
+	tasks, err := listTasks()
+	if err != nil {
+		return err
+	}
+	root, entries, err := loadWorktreeEntries(tasks)
+	if err != nil {
+		return err
+	}
+
+	removed := []worktreeEntry{}
+	for _, e := range entries {
+		if !e.Orphan && e.TaskStatus != done {
+			continue
+		}
+		if err := removeEntry(root, e); err != nil {
+			fmt.Fprintf(os.Stderr, "skipped: %v\n", err)
+			continue
+		}
+		removed = append(removed, e)
+	}
+
+	if worktreeOutputJSON {
+		return json.NewEncoder(os.Stdout).Encode(removed)
+	}
+	if len(removed) == 0 {
+		fmt.Println("Nothing to prune")
+		return nil
+	}
+	for _, e := range removed {
+		fmt.Printf("Removed %s\n", e.Path)
+	}
+	return nil
+}
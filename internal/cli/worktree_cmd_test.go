@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/markx3/agentboard/internal/db"
+	"github.com/markx3/agentboard/internal/worktree"
+)
+
+func TestMatchWorktrees(t *testing.T) {
+	managed := "/repo/.agentboard/worktrees"
+	wts := []worktree.Worktree{
+		{Path: "/repo", Branch: "main"},
+		{Path: managed + "/fix-login", Branch: "agentboard/fix-login"},
+		{Path: managed + "/renamed", Branch: "feature/custom"},
+		{Path: managed + "/gone", Branch: "agentboard/gone"},
+		{Path: "/elsewhere/mine", Branch: "personal"},
+	}
+	tasks := []db.Task{
+		{ID: "aaaaaaaa1111", Title: "Fix login", Status: db.StatusDone},
+		{ID: "bbbbbbbb2222", Title: "New title", BranchName: "feature/custom", Status: db.StatusInProgress, AgentStatus: db.AgentActive},
+	}
+
+	entries := matchWorktrees(wts, tasks, managed)
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3: %+v", len(entries), entries)
+	}
+
+	if entries[0].TaskID != "aaaaaaaa1111" || entries[0].TaskStatus != db.StatusDone {
+		t.Errorf("slug branch not matched: %+v", entries[0])
+	}
+	if entries[1].TaskID != "bbbbbbbb2222" || !entries[1].AgentActive {
+		t.Errorf("recorded branch not matched: %+v", entries[1])
+	}
+	if !entries[2].Orphan || entries[2].TaskID != "" {
+		t.Errorf("worktree of deleted task should be orphaned: %+v", entries[2])
+	}
+}
+
+func TestRemoveEntrySkipsUnreadableWorktree(t *testing.T) {
+	worktreeForce = false
+	e := worktreeEntry{Path: "/repo/.agentboard/worktrees/broken", Error: "git status: not a git repository"}
+	err := removeEntry("/repo", e)
+	if err == nil || !strings.Contains(err.Error(), "can't tell") {
+		t.Errorf("err = %v, want the worktree skipped", err)
+	}
+}
@@ -0,0 +1,287 @@
+// Package config loads and validates the project configuration stored in
+// .agentboard/config.toml.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/markx3/agentboard/internal/auth"
+	"github.com/markx3/agentboard/internal/workflow"
+)
+
+// Dir is the per-project directory holding the board database and config.
+const Dir = ".agentboard"
+
+// FileName is the name of the config file inside Dir.
+const FileName = "config.toml"
+
+// DBFileName is the name of the board database inside Dir.
+const DBFileName = "board.db"
+
+// PromptsDirName is the directory inside Dir holding agent prompt
+// templates that override the built-in ones.
+const PromptsDirName = "prompts"
+
+// Config mirrors the structure of config.toml.
+type Config struct {
+	Project  ProjectConfig  `toml:"project"`
+	Agent    AgentConfig    `toml:"agent"`
+	Worktree WorktreeConfig `toml:"worktree"`
+	TUI      TUIConfig      `toml:"tui"`
+	Server   ServerConfig   `toml:"server"`
+	Workflow WorkflowConfig `toml:"workflow"`
+}
+
+type ProjectConfig struct {
+	Name string `toml:"name"`
+}
+
+type AgentConfig struct {
+	// Preferred is the runner ID pre-selected in the agent picker and used
+	// by `agent start` when --runner is not given.
+	Preferred string `toml:"preferred"`
+	// MaxConcurrentEnrichments caps how many enrichment agents run at once.
+	MaxConcurrentEnrichments int `toml:"max_concurrent_enrichments"`
+	// MaxConcurrentAgents caps how many task agents run at once. Agents
+	// started beyond it wait in the queue.
+	MaxConcurrentAgents int `toml:"max_concurrent_agents"`
+	// Runners declares agent CLIs beyond the built-in ones. A runner whose
+	// ID matches a built-in replaces it.
+	Runners []RunnerConfig `toml:"runners"`
+}
+
+// RunnerConfig declares an agent CLI. Command and EnrichmentCommand are
+// shell commands in which {workdir}, {prompt}, {task_id}, {short_id} and
+// {title} are replaced with shell-quoted values.
+type RunnerConfig struct {
+	// ID is stored on tasks and passed to `agent start --runner`.
+	ID string `toml:"id"`
+	// Name is shown in the agent picker (default: the ID).
+	Name string `toml:"name"`
+	// Binary is looked up on PATH to detect the CLI (default: the first
+	// word of Command).
+	Binary string `toml:"binary"`
+	// Command starts the agent on a task.
+	Command string `toml:"command"`
+	// EnrichmentCommand runs a one-shot enrichment. Empty means the runner
+	// can't enrich tasks.
+	EnrichmentCommand string `toml:"enrichment_command"`
+	// VersionArgs, when set, are run against Binary to verify the CLI; the
+	// runner is available only if they succeed and the output contains
+	// VersionMatch (case-insensitive).
+	VersionArgs  []string `toml:"version_args"`
+	VersionMatch string   `toml:"version_match"`
+}
+
+// RunnerPlaceholders are the names that may appear in braces in runner
+// command templates.
+var RunnerPlaceholders = []string{"workdir", "prompt", "task_id", "short_id", "title"}
+
+var (
+	runnerIDPattern    = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	placeholderPattern = regexp.MustCompile(`\{([a-z_]+)\}`)
+)
+
+type WorktreeConfig struct {
+	// CopyFiles are copied from the project root into each new worktree.
+	CopyFiles []string `toml:"copy_files"`
+	// InitScript is run with sh inside each new worktree after it is created.
+	InitScript string `toml:"init_script"`
+}
+
+type TUIConfig struct {
+	// PollInterval is how often the TUI reloads tasks and checks agent windows.
+	PollInterval time.Duration `toml:"poll_interval"`
+	// GracePeriod is how long a dead agent window is tolerated before the
+	// task is reconciled to completed or error.
+	GracePeriod time.Duration `toml:"grace_period"`
+}
+
+type ServerConfig struct {
+	// Auth selects how the sync server authenticates peers: "github",
+	// "secret" (AGENTBOARD_SECRET) or "tokens" (TokenFile).
+	Auth string `toml:"auth"`
+	// TokenFile lists "username token" pairs for the "tokens" mode. Relative
+	// paths are resolved against the project root.
+	TokenFile string `toml:"token_file"`
+}
+
+type WorkflowConfig struct {
+	// Columns replaces the built-in columns when set, in board order.
+	Columns []workflow.Column `toml:"columns"`
+}
+
+// Default returns the configuration used when no config file exists.
+func Default() *Config {
+	return &Config{
+		Agent: AgentConfig{
+			Preferred:                "claude",
+			MaxConcurrentEnrichments: 3,
+			MaxConcurrentAgents:      4,
+		},
+		Worktree: WorktreeConfig{
+			CopyFiles: []string{".env", ".env.local"},
+		},
+		TUI: TUIConfig{
+			PollInterval: 2500 * time.Millisecond,
+			GracePeriod:  5 * time.Second,
+		},
+		Server: ServerConfig{
+			Auth: auth.ModeGitHub,
+		},
+	}
+}
+
+// BoardWorkflow returns the configured workflow, or the built-in one when
+// workflow.columns is unset or invalid.
+func (c *Config) BoardWorkflow() *workflow.Workflow {
+	wf, err := workflow.New(c.Workflow.Columns)
+	if err != nil {
+		return workflow.Default()
+	}
+	return wf
+}
+
+// ProjectRoot returns the nearest directory at or above the working
+// directory whose Dir contains the board database. Agents run inside task
+// worktrees nested below the project, and their CLI calls must reach the
+// same board. Falls back to "." when no database is found.
+func ProjectRoot() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+	for dir := wd; ; {
+		if _, err := os.Stat(filepath.Join(dir, Dir, DBFileName)); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "."
+		}
+		dir = parent
+	}
+}
+
+// Path returns the config path for the current project.
+func Path() string {
+	return filepath.Join(ProjectRoot(), Dir, FileName)
+}
+
+// DBPath returns the board database path for the current project.
+func DBPath() string {
+	return filepath.Join(ProjectRoot(), Dir, DBFileName)
+}
+
+// PromptsDir returns the prompt template directory for the current project.
+func PromptsDir() string {
+	return filepath.Join(ProjectRoot(), Dir, PromptsDirName)
+}
+
+// Load reads the config at path on top of Default(). A missing file is not
+// an error. Unknown keys and invalid values are.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+
+	md, err := toml.Decode(string(data), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, k := range undecoded {
+			keys[i] = k.String()
+		}
+		sort.Strings(keys)
+		return nil, fmt.Errorf("%s: unknown config key(s): %s", path, strings.Join(keys, ", "))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Validate checks value ranges that the TOML decoder cannot enforce.
+func (c *Config) Validate() error {
+	if c.Agent.MaxConcurrentEnrichments < 1 {
+		return fmt.Errorf("agent.max_concurrent_enrichments must be at least 1 (got %d)", c.Agent.MaxConcurrentEnrichments)
+	}
+	if c.Agent.MaxConcurrentAgents < 1 {
+		return fmt.Errorf("agent.max_concurrent_agents must be at least 1 (got %d)", c.Agent.MaxConcurrentAgents)
+	}
+	if c.TUI.PollInterval < 100*time.Millisecond {
+		return fmt.Errorf("tui.poll_interval must be at least 100ms (got %s)", c.TUI.PollInterval)
+	}
+	if c.TUI.GracePeriod < 0 {
+		return fmt.Errorf("tui.grace_period must not be negative (got %s)", c.TUI.GracePeriod)
+	}
+	switch c.Server.Auth {
+	case auth.ModeGitHub, auth.ModeSecret:
+	case auth.ModeTokens:
+		if c.Server.TokenFile == "" {
+			return fmt.Errorf("server.token_file is required when server.auth is %q", auth.ModeTokens)
+		}
+	default:
+		return fmt.Errorf("server.auth must be %s, %s or %s (got %q)", auth.ModeGitHub, auth.ModeSecret, auth.ModeTokens, c.Server.Auth)
+	}
+	if _, err := workflow.New(c.Workflow.Columns); err != nil {
+		return fmt.Errorf("workflow.columns: %w", err)
+	}
+	seen := make(map[string]bool)
+	for _, r := range c.Agent.Runners {
+		if err := r.validate(); err != nil {
+			return err
+		}
+		if seen[r.ID] {
+			return fmt.Errorf("agent.runners: duplicate id %q", r.ID)
+		}
+		seen[r.ID] = true
+	}
+	for _, f := range c.Worktree.CopyFiles {
+		if f == "" || filepath.IsAbs(f) || strings.HasPrefix(filepath.Clean(f), "..") {
+			return fmt.Errorf("worktree.copy_files entry %q must be a relative path inside the project", f)
+		}
+	}
+	return nil
+}
+
+func (r RunnerConfig) validate() error {
+	if !runnerIDPattern.MatchString(r.ID) {
+		return fmt.Errorf("agent.runners: id %q must be lowercase letters, digits, '-' and '_'", r.ID)
+	}
+	if strings.TrimSpace(r.Command) == "" {
+		return fmt.Errorf("agent.runners %q: command is required", r.ID)
+	}
+	for _, tmpl := range []string{r.Command, r.EnrichmentCommand} {
+		for _, m := range placeholderPattern.FindAllStringSubmatch(tmpl, -1) {
+			if !slices.Contains(RunnerPlaceholders, m[1]) {
+				return fmt.Errorf("agent.runners %q: unknown placeholder %s (use: {%s})",
+					r.ID, m[0], strings.Join(RunnerPlaceholders, "}, {"))
+			}
+		}
+	}
+	if r.VersionMatch != "" && len(r.VersionArgs) == 0 {
+		return fmt.Errorf("agent.runners %q: version_match needs version_args", r.ID)
+	}
+	return nil
+}
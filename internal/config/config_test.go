@@ -0,0 +1,211 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/markx3/agentboard/internal/config"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+	return path
+}
+
+func TestLoadMissingFileReturnsDefaults(t *testing.T) {
+	cfg, err := config.Load(filepath.Join(t.TempDir(), "missing.toml"))
+	if err != nil {
+		t.Fatalf("loading missing config: %v", err)
+	}
+	if cfg.Agent.Preferred != "claude" {
+		t.Errorf("got preferred %q, want %q", cfg.Agent.Preferred, "claude")
+	}
+	if cfg.Agent.MaxConcurrentEnrichments != 3 {
+		t.Errorf("got max enrichments %d, want 3", cfg.Agent.MaxConcurrentEnrichments)
+	}
+	if cfg.Agent.MaxConcurrentAgents != 4 {
+		t.Errorf("got max agents %d, want 4", cfg.Agent.MaxConcurrentAgents)
+	}
+	if cfg.TUI.PollInterval != 2500*time.Millisecond {
+		t.Errorf("got poll interval %s, want 2.5s", cfg.TUI.PollInterval)
+	}
+}
+
+func TestLoadOverridesDefaults(t *testing.T) {
+	path := writeConfig(t, `
+[project]
+name = "demo"
+
+[agent]
+preferred = "cursor"
+max_concurrent_enrichments = 1
+
+[worktree]
+copy_files = [".env"]
+init_script = "make deps"
+
+[tui]
+poll_interval = "1s"
+grace_period = "10s"
+`)
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("loading config: %v", err)
+	}
+	if cfg.Project.Name != "demo" {
+		t.Errorf("got name %q, want %q", cfg.Project.Name, "demo")
+	}
+	if cfg.Agent.Preferred != "cursor" {
+		t.Errorf("got preferred %q, want %q", cfg.Agent.Preferred, "cursor")
+	}
+	if cfg.Agent.MaxConcurrentEnrichments != 1 {
+		t.Errorf("got max enrichments %d, want 1", cfg.Agent.MaxConcurrentEnrichments)
+	}
+	if len(cfg.Worktree.CopyFiles) != 1 || cfg.Worktree.CopyFiles[0] != ".env" {
+		t.Errorf("got copy_files %v, want [.env]", cfg.Worktree.CopyFiles)
+	}
+	if cfg.Worktree.InitScript != "make deps" {
+		t.Errorf("got init_script %q, want %q", cfg.Worktree.InitScript, "make deps")
+	}
+	if cfg.TUI.PollInterval != time.Second || cfg.TUI.GracePeriod != 10*time.Second {
+		t.Errorf("got intervals %s/%s, want 1s/10s", cfg.TUI.PollInterval, cfg.TUI.GracePeriod)
+	}
+}
+
+func TestLoadWorkflowColumns(t *testing.T) {
+	path := writeConfig(t, `
+[[workflow.columns]]
+status = "todo"
+
+[[workflow.columns]]
+status = "qa"
+name = "QA"
+prompt = "Run the test plan."
+
+[[workflow.columns]]
+status = "deployed"
+`)
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("loading config: %v", err)
+	}
+	wf := cfg.BoardWorkflow()
+	if got := wf.ValidList(); got != "todo, qa, deployed" {
+		t.Errorf("got columns %q, want todo, qa, deployed", got)
+	}
+	if wf.Name("todo") != "Todo" || wf.Name("qa") != "QA" {
+		t.Errorf("got names %q/%q, want Todo/QA", wf.Name("todo"), wf.Name("qa"))
+	}
+	if c, _ := wf.Column("qa"); c.Prompt != "Run the test plan." {
+		t.Errorf("got prompt %q", c.Prompt)
+	}
+}
+
+func TestLoadRunners(t *testing.T) {
+	path := writeConfig(t, `
+[[agent.runners]]
+id = "aider"
+name = "Aider"
+command = "aider --message {prompt}"
+version_args = ["--version"]
+version_match = "aider"
+`)
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("loading config: %v", err)
+	}
+	if len(cfg.Agent.Runners) != 1 {
+		t.Fatalf("got %d runners, want 1", len(cfg.Agent.Runners))
+	}
+	r := cfg.Agent.Runners[0]
+	if r.ID != "aider" || r.Name != "Aider" || r.Command != "aider --message {prompt}" {
+		t.Errorf("got runner %+v", r)
+	}
+	if len(r.VersionArgs) != 1 || r.VersionMatch != "aider" {
+		t.Errorf("got version probe %v/%q", r.VersionArgs, r.VersionMatch)
+	}
+}
+
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+	path := writeConfig(t, `
+[agent]
+prefered = "claude"
+
+[bogus]
+x = 1
+`)
+	_, err := config.Load(path)
+	if err == nil {
+		t.Fatal("expected error for unknown keys")
+	}
+	for _, key := range []string{"agent.prefered", "bogus"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("error %q should mention %q", err, key)
+		}
+	}
+}
+
+func TestLoadRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"zero enrichments", "[agent]\nmax_concurrent_enrichments = 0\n"},
+		{"zero agents", "[agent]\nmax_concurrent_agents = 0\n"},
+		{"tiny poll interval", "[tui]\npoll_interval = \"1ms\"\n"},
+		{"bad duration", "[tui]\npoll_interval = \"soon\"\n"},
+		{"absolute copy file", "[worktree]\ncopy_files = [\"/etc/passwd\"]\n"},
+		{"escaping copy file", "[worktree]\ncopy_files = [\"../secrets\"]\n"},
+		{"unknown auth mode", "[server]\nauth = \"ldap\"\n"},
+		{"tokens without file", "[server]\nauth = \"tokens\"\n"},
+		{"single column", "[[workflow.columns]]\nstatus = \"todo\"\n"},
+		{"runner without command", "[[agent.runners]]\nid = \"x\"\n"},
+		{"bad runner id", "[[agent.runners]]\nid = \"My Agent\"\ncommand = \"x\"\n"},
+		{"duplicate runner", "[[agent.runners]]\nid = \"x\"\ncommand = \"x\"\n[[agent.runners]]\nid = \"x\"\ncommand = \"y\"\n"},
+		{"unknown placeholder", "[[agent.runners]]\nid = \"x\"\ncommand = \"x {task}\"\n"},
+		{"match without probe", "[[agent.runners]]\nid = \"x\"\ncommand = \"x\"\nversion_match = \"x\"\n"},
+		{"bad column status", "[[workflow.columns]]\nstatus = \"To Do\"\n[[workflow.columns]]\nstatus = \"done\"\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := config.Load(writeConfig(t, tt.content)); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestProjectRootFromNestedDir(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("resolving temp dir: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, config.Dir), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, config.Dir, config.DBFileName), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(root, config.Dir, "worktrees", "some-task")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	os.Chdir(nested)
+	defer os.Chdir(origDir)
+
+	if got := config.ProjectRoot(); got != root {
+		t.Errorf("ProjectRoot() = %q, want %q", got, root)
+	}
+	if got := config.DBPath(); got != filepath.Join(root, config.Dir, config.DBFileName) {
+		t.Errorf("DBPath() = %q", got)
+	}
+}
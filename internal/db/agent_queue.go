@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// EnqueueAgent queues an agent for the task. runner is a runner ID, or ""
+// for the preferred runner at start time.
+func (d *DB) EnqueueAgent(ctx context.Context, taskID, runner string) error {
+	result, err := d.conn.ExecContext(ctx,
+		`INSERT INTO agent_queue (task_id, runner, queued_at) VALUES (?, ?, ?)
+		 ON CONFLICT(task_id) DO NOTHING`,
+		taskID, runner, time.Now().UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("queueing agent: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("an agent is already queued for task %s", taskID[:min(8, len(taskID))])
+	}
+	return nil
+}
+
+// DequeueAgent removes the task's queued agent and reports whether there
+// was one. Schedulers start an agent only if they removed its entry, so two
+// of them never start the same one.
+func (d *DB) DequeueAgent(ctx context.Context, taskID string) (bool, error) {
+	result, err := d.conn.ExecContext(ctx, "DELETE FROM agent_queue WHERE task_id = ?", taskID)
+	if err != nil {
+		return false, fmt.Errorf("dequeueing agent: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// ListAgentQueue returns the queued agents, oldest first.
+func (d *DB) ListAgentQueue(ctx context.Context) ([]QueuedAgent, error) {
+	rows, err := d.conn.QueryContext(ctx,
+		"SELECT task_id, runner, queued_at FROM agent_queue ORDER BY queued_at, rowid")
+	if err != nil {
+		return nil, fmt.Errorf("listing agent queue: %w", err)
+	}
+	defer rows.Close()
+
+	queue := []QueuedAgent{}
+	for rows.Next() {
+		var q QueuedAgent
+		var queuedAt string
+		if err := rows.Scan(&q.TaskID, &q.Runner, &queuedAt); err != nil {
+			return nil, fmt.Errorf("scanning agent queue: %w", err)
+		}
+		q.QueuedAt, _ = time.Parse(time.RFC3339Nano, queuedAt)
+		queue = append(queue, q)
+	}
+	return queue, rows.Err()
+}
+
+// attachAgentQueue fills in AgentQueued on tasks read from the tasks table.
+func (d *DB) attachAgentQueue(ctx context.Context, tasks []Task) error {
+	queue, err := d.ListAgentQueue(ctx)
+	if err != nil {
+		return err
+	}
+	queued := make(map[string]bool, len(queue))
+	for _, q := range queue {
+		queued[q.TaskID] = true
+	}
+	for i := range tasks {
+		tasks[i].AgentQueued = queued[tasks[i].ID]
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAgentQueue(t *testing.T) {
+	database := setupTestDB(t)
+	ctx := context.Background()
+
+	first, _ := database.CreateTask(ctx, "First", "")
+	second, _ := database.CreateTask(ctx, "Second", "")
+	third, _ := database.CreateTask(ctx, "Third", "")
+
+	if err := database.EnqueueAgent(ctx, second.ID, "cursor"); err != nil {
+		t.Fatalf("EnqueueAgent: %v", err)
+	}
+	database.EnqueueAgent(ctx, first.ID, "")
+	if err := database.EnqueueAgent(ctx, second.ID, ""); err == nil {
+		t.Error("queueing a task twice should fail")
+	}
+	if err := database.EnqueueAgent(ctx, "missing", ""); err == nil {
+		t.Error("queueing an unknown task should fail")
+	}
+
+	queue, err := database.ListAgentQueue(ctx)
+	if err != nil {
+		t.Fatalf("ListAgentQueue: %v", err)
+	}
+	if len(queue) != 2 || queue[0].TaskID != second.ID || queue[1].TaskID != first.ID {
+		t.Fatalf("queue = %+v, want second then first", queue)
+	}
+	if queue[0].Runner != "cursor" || queue[1].Runner != "" || queue[0].QueuedAt.IsZero() {
+		t.Errorf("queue = %+v, want runners and times kept", queue)
+	}
+
+	if got, _ := database.GetTask(ctx, second.ID); !got.AgentQueued {
+		t.Error("GetTask should report the task as queued")
+	}
+	tasks, _ := database.ListTasks(ctx)
+	for _, task := range tasks {
+		if want := task.ID != third.ID; task.AgentQueued != want {
+			t.Errorf("ListTasks: %s queued = %v, want %v", task.Title, task.AgentQueued, want)
+		}
+	}
+
+	if removed, err := database.DequeueAgent(ctx, second.ID); err != nil || !removed {
+		t.Fatalf("DequeueAgent = %v, %v, want removed", removed, err)
+	}
+	if removed, _ := database.DequeueAgent(ctx, second.ID); removed {
+		t.Error("dequeueing twice should report nothing removed")
+	}
+
+	// Deleting a task drops its queued agent.
+	database.DeleteTask(ctx, first.ID)
+	if queue, _ := database.ListAgentQueue(ctx); len(queue) != 0 {
+		t.Errorf("queue = %+v, want empty", queue)
+	}
+}
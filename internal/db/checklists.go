@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// AddChecklistItem appends an unchecked item to a task's checklist.
+func (d *DB) AddChecklistItem(ctx context.Context, taskID, text string) (*ChecklistItem, error) {
+	item := &ChecklistItem{TaskID: taskID, Text: text}
+	err := d.conn.QueryRowContext(ctx,
+		"SELECT COALESCE(MAX(position), -1) + 1 FROM checklist_items WHERE task_id = ?",
+		taskID).Scan(&item.Position)
+	if err != nil {
+		return nil, fmt.Errorf("getting checklist position: %w", err)
+	}
+	result, err := d.conn.ExecContext(ctx,
+		`INSERT INTO checklist_items (task_id, text, done, position, created_at)
+		 VALUES (?, ?, 0, ?, ?)`,
+		taskID, text, item.Position, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("adding checklist item: %w", err)
+	}
+	if item.ID, err = result.LastInsertId(); err != nil {
+		return nil, fmt.Errorf("adding checklist item: %w", err)
+	}
+	return item, nil
+}
+
+// SetChecklistItemDone ticks or unticks an item of a task's checklist.
+func (d *DB) SetChecklistItemDone(ctx context.Context, taskID string, itemID int64, done bool) error {
+	result, err := d.conn.ExecContext(ctx,
+		"UPDATE checklist_items SET done = ? WHERE id = ? AND task_id = ?",
+		boolToInt(done), itemID, taskID)
+	if err != nil {
+		return fmt.Errorf("updating checklist item: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("checklist item %d not found", itemID)
+	}
+	return nil
+}
+
+// RemoveChecklistItem deletes an item from a task's checklist.
+func (d *DB) RemoveChecklistItem(ctx context.Context, taskID string, itemID int64) error {
+	result, err := d.conn.ExecContext(ctx,
+		"DELETE FROM checklist_items WHERE id = ? AND task_id = ?", itemID, taskID)
+	if err != nil {
+		return fmt.Errorf("removing checklist item: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("checklist item %d not found", itemID)
+	}
+	return nil
+}
+
+// taskChecklists returns the checklist of the task with the given ID, or of
+// every task when taskID is empty, keyed by task ID.
+func (d *DB) taskChecklists(ctx context.Context, taskID string) (map[string][]ChecklistItem, error) {
+	query := "SELECT id, task_id, text, done, position FROM checklist_items"
+	var args []interface{}
+	if taskID != "" {
+		query += " WHERE task_id = ?"
+		args = append(args, taskID)
+	}
+	rows, err := d.conn.QueryContext(ctx, query+" ORDER BY position, id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing checklist items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make(map[string][]ChecklistItem)
+	for rows.Next() {
+		var item ChecklistItem
+		var done int
+		if err := rows.Scan(&item.ID, &item.TaskID, &item.Text, &done, &item.Position); err != nil {
+			return nil, fmt.Errorf("scanning checklist item: %w", err)
+		}
+		item.Done = done != 0
+		items[item.TaskID] = append(items[item.TaskID], item)
+	}
+	return items, rows.Err()
+}
+
+// attachChecklists fills in Checklist on tasks read from the tasks table.
+func (d *DB) attachChecklists(ctx context.Context, tasks []Task) error {
+	items, err := d.taskChecklists(ctx, "")
+	if err != nil {
+		return err
+	}
+	for i := range tasks {
+		tasks[i].Checklist = items[tasks[i].ID]
+	}
+	return nil
+}
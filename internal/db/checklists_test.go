@@ -0,0 +1,59 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTaskChecklist(t *testing.T) {
+	database := setupTestDB(t)
+	ctx := context.Background()
+
+	task, _ := database.CreateTask(ctx, "Checked", "")
+	other, _ := database.CreateTask(ctx, "Plain", "")
+
+	first, err := database.AddChecklistItem(ctx, task.ID, "Tests pass")
+	if err != nil {
+		t.Fatalf("AddChecklistItem: %v", err)
+	}
+	second, _ := database.AddChecklistItem(ctx, task.ID, "Docs updated")
+	if first.Position != 0 || second.Position != 1 {
+		t.Errorf("positions = %d, %d, want 0, 1", first.Position, second.Position)
+	}
+
+	if err := database.SetChecklistItemDone(ctx, task.ID, second.ID, true); err != nil {
+		t.Fatalf("SetChecklistItemDone: %v", err)
+	}
+	if err := database.SetChecklistItemDone(ctx, other.ID, first.ID, true); err == nil {
+		t.Error("ticking another task's item should fail")
+	}
+
+	got, _ := database.GetTask(ctx, task.ID)
+	if len(got.Checklist) != 2 || got.Checklist[0].Text != "Tests pass" || got.Checklist[0].Done || !got.Checklist[1].Done {
+		t.Errorf("GetTask checklist = %+v, want unchecked then checked", got.Checklist)
+	}
+	if done, total := got.ChecklistProgress(); done != 1 || total != 2 {
+		t.Errorf("progress = %d/%d, want 1/2", done, total)
+	}
+
+	tasks, _ := database.ListTasks(ctx)
+	for _, tk := range tasks {
+		if tk.ID == other.ID && len(tk.Checklist) != 0 {
+			t.Errorf("task without a checklist got %+v", tk.Checklist)
+		}
+		if tk.ID == task.ID && len(tk.Checklist) != 2 {
+			t.Errorf("ListTasks lost the checklist: %+v", tk.Checklist)
+		}
+	}
+
+	if err := database.RemoveChecklistItem(ctx, task.ID, first.ID); err != nil {
+		t.Fatalf("RemoveChecklistItem: %v", err)
+	}
+	if err := database.RemoveChecklistItem(ctx, task.ID, first.ID); err == nil {
+		t.Error("removing a missing item should fail")
+	}
+	third, _ := database.AddChecklistItem(ctx, task.ID, "Reviewed")
+	if third.Position != 2 {
+		t.Errorf("new item position = %d, want 2 (after the last one)", third.Position)
+	}
+}
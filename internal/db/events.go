@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// EventLogLimit is how many of the most recent events the log keeps.
+// Peers that fall further behind get a full snapshot instead of a replay.
+const EventLogLimit = 1000
+
+// AppendEvent stores an event and trims the log to EventLogLimit entries.
+func (d *DB) AppendEvent(ctx context.Context, ev Event) error {
+	if ev.CreatedAt.IsZero() {
+		ev.CreatedAt = time.Now().UTC()
+	}
+	_, err := d.conn.ExecContext(ctx,
+		`INSERT OR REPLACE INTO events (seq, type, sender, payload, created_at)
+		 VALUES (?, ?, ?, ?, ?)`,
+		ev.Seq, ev.Type, ev.Sender, string(ev.Payload), ev.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("appending event: %w", err)
+	}
+	if _, err := d.conn.ExecContext(ctx,
+		"DELETE FROM events WHERE seq <= ?", ev.Seq-EventLogLimit); err != nil {
+		return fmt.Errorf("trimming event log: %w", err)
+	}
+	return nil
+}
+
+// EventsSince returns the logged events with a sequence number above seq,
+// oldest first.
+func (d *DB) EventsSince(ctx context.Context, seq int64) ([]Event, error) {
+	rows, err := d.conn.QueryContext(ctx,
+		`SELECT seq, type, sender, payload, created_at
+		 FROM events WHERE seq > ? ORDER BY seq`, seq)
+	if err != nil {
+		return nil, fmt.Errorf("listing events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var ev Event
+		var payload, createdAt string
+		if err := rows.Scan(&ev.Seq, &ev.Type, &ev.Sender, &payload, &createdAt); err != nil {
+			return nil, fmt.Errorf("scanning event: %w", err)
+		}
+		ev.Payload = []byte(payload)
+		var parseErr error
+		ev.CreatedAt, parseErr = time.Parse(time.RFC3339, createdAt)
+		if parseErr != nil {
+			log.Printf("warning: invalid created_at for event %d: %v", ev.Seq, parseErr)
+		}
+		events = append(events, ev)
+	}
+	return events, rows.Err()
+}
+
+// LatestEventSeq returns the highest logged sequence number, or 0 if the
+// log is empty.
+func (d *DB) LatestEventSeq(ctx context.Context) (int64, error) {
+	var seq int64
+	err := d.conn.QueryRowContext(ctx, "SELECT COALESCE(MAX(seq), 0) FROM events").Scan(&seq)
+	if err != nil {
+		return 0, fmt.Errorf("reading latest event seq: %w", err)
+	}
+	return seq, nil
+}
@@ -0,0 +1,66 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/markx3/agentboard/internal/db"
+)
+
+func TestEventLog(t *testing.T) {
+	database := setupTestDB(t)
+	ctx := context.Background()
+
+	seq, err := database.LatestEventSeq(ctx)
+	if err != nil {
+		t.Fatalf("LatestEventSeq: %v", err)
+	}
+	if seq != 0 {
+		t.Errorf("empty log: got seq %d, want 0", seq)
+	}
+
+	for i := int64(1); i <= 3; i++ {
+		ev := db.Event{Seq: i, Type: "task.create", Sender: "alice", Payload: []byte(`{"title":"x"}`)}
+		if err := database.AppendEvent(ctx, ev); err != nil {
+			t.Fatalf("AppendEvent %d: %v", i, err)
+		}
+	}
+
+	events, err := database.EventsSince(ctx, 1)
+	if err != nil {
+		t.Fatalf("EventsSince: %v", err)
+	}
+	if len(events) != 2 || events[0].Seq != 2 || events[1].Seq != 3 {
+		t.Fatalf("got %+v, want events 2 and 3", events)
+	}
+	if string(events[0].Payload) != `{"title":"x"}` || events[0].Sender != "alice" {
+		t.Errorf("event fields not round-tripped: %+v", events[0])
+	}
+
+	if seq, _ := database.LatestEventSeq(ctx); seq != 3 {
+		t.Errorf("got latest seq %d, want 3", seq)
+	}
+}
+
+func TestEventLogTrimmed(t *testing.T) {
+	database := setupTestDB(t)
+	ctx := context.Background()
+
+	last := int64(db.EventLogLimit + 5)
+	for i := int64(1); i <= last; i++ {
+		if err := database.AppendEvent(ctx, db.Event{Seq: i, Type: "ping"}); err != nil {
+			t.Fatalf("AppendEvent %d: %v", i, err)
+		}
+	}
+
+	events, err := database.EventsSince(ctx, 0)
+	if err != nil {
+		t.Fatalf("EventsSince: %v", err)
+	}
+	if len(events) != db.EventLogLimit {
+		t.Fatalf("got %d events, want %d", len(events), db.EventLogLimit)
+	}
+	if events[0].Seq != last-db.EventLogLimit+1 {
+		t.Errorf("oldest kept seq = %d, want %d", events[0].Seq, last-db.EventLogLimit+1)
+	}
+}
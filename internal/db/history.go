@@ -0,0 +1,62 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// AddTaskEvents records changes to tasks in one transaction.
+func (d *DB) AddTaskEvents(ctx context.Context, events []TaskEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	tx, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	for _, ev := range events {
+		if ev.CreatedAt.IsZero() {
+			ev.CreatedAt = now
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO task_events (task_id, actor, field, old_value, new_value, created_at)
+			 VALUES (?, ?, ?, ?, ?, ?)`,
+			ev.TaskID, ev.Actor, ev.Field, ev.OldValue, ev.NewValue,
+			ev.CreatedAt.Format(time.RFC3339)); err != nil {
+			return fmt.Errorf("adding task event: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ListTaskEvents returns a task's history, oldest first.
+func (d *DB) ListTaskEvents(ctx context.Context, taskID string) ([]TaskEvent, error) {
+	rows, err := d.conn.QueryContext(ctx,
+		`SELECT id, task_id, actor, field, old_value, new_value, created_at
+		 FROM task_events WHERE task_id = ? ORDER BY id`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("listing task events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []TaskEvent
+	for rows.Next() {
+		var ev TaskEvent
+		var createdAt string
+		if err := rows.Scan(&ev.ID, &ev.TaskID, &ev.Actor, &ev.Field, &ev.OldValue, &ev.NewValue, &createdAt); err != nil {
+			return nil, fmt.Errorf("scanning task event: %w", err)
+		}
+		var parseErr error
+		ev.CreatedAt, parseErr = time.Parse(time.RFC3339, createdAt)
+		if parseErr != nil {
+			log.Printf("warning: invalid created_at for task event %d: %v", ev.ID, parseErr)
+		}
+		events = append(events, ev)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,42 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/markx3/agentboard/internal/db"
+)
+
+func TestTaskEvents(t *testing.T) {
+	database := setupTestDB(t)
+	ctx := context.Background()
+
+	task, _ := database.CreateTask(ctx, "Tracked", "")
+	err := database.AddTaskEvents(ctx, []db.TaskEvent{
+		{TaskID: task.ID, Actor: "alice", Field: "status", OldValue: "backlog", NewValue: "planning"},
+		{TaskID: task.ID, Actor: "alice", Field: "assignee", NewValue: "alice"},
+	})
+	if err != nil {
+		t.Fatalf("AddTaskEvents: %v", err)
+	}
+
+	events, err := database.ListTaskEvents(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("ListTaskEvents: %v", err)
+	}
+	if len(events) != 2 || events[0].Field != "status" || events[1].Field != "assignee" {
+		t.Fatalf("got %+v, want status then assignee", events)
+	}
+	ev := events[0]
+	if ev.Actor != "alice" || ev.OldValue != "backlog" || ev.NewValue != "planning" || ev.CreatedAt.IsZero() {
+		t.Errorf("event fields not round-tripped: %+v", ev)
+	}
+
+	if err := database.DeleteTask(ctx, task.ID); err != nil {
+		t.Fatalf("deleting task: %v", err)
+	}
+	events, _ = database.ListTaskEvents(ctx, task.ID)
+	if len(events) != 0 {
+		t.Errorf("after cascade delete: got %d events, want 0", len(events))
+	}
+}
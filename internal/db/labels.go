@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// EnsureLabel creates the label with color unless it already exists.
+func (d *DB) EnsureLabel(ctx context.Context, name, color string) error {
+	_, err := d.conn.ExecContext(ctx,
+		"INSERT OR IGNORE INTO labels (name, color) VALUES (?, ?)", name, color)
+	if err != nil {
+		return fmt.Errorf("creating label: %w", err)
+	}
+	return nil
+}
+
+// SetLabelColor creates the label or changes its color.
+func (d *DB) SetLabelColor(ctx context.Context, name, color string) error {
+	_, err := d.conn.ExecContext(ctx,
+		`INSERT INTO labels (name, color) VALUES (?, ?)
+		 ON CONFLICT(name) DO UPDATE SET color = excluded.color`, name, color)
+	if err != nil {
+		return fmt.Errorf("setting label color: %w", err)
+	}
+	return nil
+}
+
+// ListLabels returns every label, including ones no task carries.
+func (d *DB) ListLabels(ctx context.Context) ([]Label, error) {
+	rows, err := d.conn.QueryContext(ctx, "SELECT name, color FROM labels ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("listing labels: %w", err)
+	}
+	defer rows.Close()
+
+	var labels []Label
+	for rows.Next() {
+		var l Label
+		if err := rows.Scan(&l.Name, &l.Color); err != nil {
+			return nil, fmt.Errorf("scanning label: %w", err)
+		}
+		labels = append(labels, l)
+	}
+	return labels, rows.Err()
+}
+
+// AddTaskLabel tags a task with an existing label. Adding a label the task
+// already has is a no-op.
+func (d *DB) AddTaskLabel(ctx context.Context, taskID, name string) error {
+	_, err := d.conn.ExecContext(ctx,
+		"INSERT OR IGNORE INTO task_labels (task_id, label) VALUES (?, ?)", taskID, name)
+	if err != nil {
+		return fmt.Errorf("adding label: %w", err)
+	}
+	return nil
+}
+
+// RemoveTaskLabel untags a task. The label itself, and its color, are kept.
+func (d *DB) RemoveTaskLabel(ctx context.Context, taskID, name string) error {
+	result, err := d.conn.ExecContext(ctx,
+		"DELETE FROM task_labels WHERE task_id = ? AND label = ?", taskID, name)
+	if err != nil {
+		return fmt.Errorf("removing label: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("task does not have label %q", name)
+	}
+	return nil
+}
+
+// taskLabels returns the labels of the task with the given ID, or of every
+// task when taskID is empty, keyed by task ID.
+func (d *DB) taskLabels(ctx context.Context, taskID string) (map[string][]Label, error) {
+	query := `SELECT tl.task_id, l.name, l.color
+		FROM task_labels tl JOIN labels l ON l.name = tl.label`
+	var args []interface{}
+	if taskID != "" {
+		query += " WHERE tl.task_id = ?"
+		args = append(args, taskID)
+	}
+	rows, err := d.conn.QueryContext(ctx, query+" ORDER BY l.name", args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing task labels: %w", err)
+	}
+	defer rows.Close()
+
+	labels := make(map[string][]Label)
+	for rows.Next() {
+		var id string
+		var l Label
+		if err := rows.Scan(&id, &l.Name, &l.Color); err != nil {
+			return nil, fmt.Errorf("scanning task label: %w", err)
+		}
+		labels[id] = append(labels[id], l)
+	}
+	return labels, rows.Err()
+}
+
+// attachLabels fills in Labels on tasks read from the tasks table.
+func (d *DB) attachLabels(ctx context.Context, tasks []Task) error {
+	labels, err := d.taskLabels(ctx, "")
+	if err != nil {
+		return err
+	}
+	for i := range tasks {
+		tasks[i].Labels = labels[tasks[i].ID]
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTaskLabels(t *testing.T) {
+	database := setupTestDB(t)
+	ctx := context.Background()
+
+	task, _ := database.CreateTask(ctx, "Labelled", "")
+	other, _ := database.CreateTask(ctx, "Plain", "")
+
+	database.EnsureLabel(ctx, "bug", "#ff5555")
+	database.EnsureLabel(ctx, "bug", "#000000") // existing color is kept
+	database.SetLabelColor(ctx, "backend", "#8be9fd")
+	if err := database.AddTaskLabel(ctx, task.ID, "bug"); err != nil {
+		t.Fatalf("AddTaskLabel: %v", err)
+	}
+	database.AddTaskLabel(ctx, task.ID, "backend")
+	if err := database.AddTaskLabel(ctx, task.ID, "bug"); err != nil {
+		t.Errorf("adding a label twice: %v", err)
+	}
+
+	got, _ := database.GetTask(ctx, task.ID)
+	if len(got.Labels) != 2 || got.Labels[0].Name != "backend" || got.Labels[1].Color != "#ff5555" {
+		t.Errorf("GetTask labels = %+v, want backend then bug (#ff5555)", got.Labels)
+	}
+
+	tasks, _ := database.ListTasks(ctx)
+	for _, tk := range tasks {
+		if tk.ID == other.ID && len(tk.Labels) != 0 {
+			t.Errorf("unlabelled task got labels %+v", tk.Labels)
+		}
+		if tk.ID == task.ID && !tk.HasLabel("bug") {
+			t.Errorf("ListTasks lost labels: %+v", tk.Labels)
+		}
+	}
+
+	if err := database.RemoveTaskLabel(ctx, task.ID, "bug"); err != nil {
+		t.Fatalf("RemoveTaskLabel: %v", err)
+	}
+	if err := database.RemoveTaskLabel(ctx, task.ID, "bug"); err == nil {
+		t.Error("removing a label the task doesn't have should fail")
+	}
+	labels, _ := database.ListLabels(ctx)
+	if len(labels) != 2 {
+		t.Errorf("got %d labels, want both kept after removal", len(labels))
+	}
+}
@@ -1,7 +1,12 @@
 package db
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
+// TaskStatus is the column a task is in. The valid statuses come from the
+// board's workflow; these are the built-in columns.
 type TaskStatus string
 
 const (
@@ -13,14 +18,6 @@ const (
 	StatusDone       TaskStatus = "done"
 )
 
-func (s TaskStatus) Valid() bool {
-	switch s {
-	case StatusBacklog, StatusBrainstorm, StatusPlanning, StatusInProgress, StatusReview, StatusDone:
-		return true
-	}
-	return false
-}
-
 type AgentStatus string
 
 const (
@@ -30,6 +27,14 @@ const (
 	AgentError     AgentStatus = "error"
 )
 
+func (s AgentStatus) Valid() bool {
+	switch s {
+	case AgentIdle, AgentActive, AgentCompleted, AgentError:
+		return true
+	}
+	return false
+}
+
 type EnrichmentStatus string
 
 const (
@@ -49,6 +54,54 @@ func (s EnrichmentStatus) Valid() bool {
 	return false
 }
 
+// TaskPriority says how urgent a task is. The empty priority means none
+// was set.
+type TaskPriority string
+
+const (
+	PriorityNone   TaskPriority = ""
+	PriorityLow    TaskPriority = "low"
+	PriorityMedium TaskPriority = "medium"
+	PriorityHigh   TaskPriority = "high"
+	PriorityUrgent TaskPriority = "urgent"
+)
+
+func (p TaskPriority) Valid() bool {
+	switch p {
+	case PriorityNone, PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent:
+		return true
+	}
+	return false
+}
+
+// Rank orders priorities from 0 (none) to 4 (urgent).
+func (p TaskPriority) Rank() int {
+	switch p {
+	case PriorityLow:
+		return 1
+	case PriorityMedium:
+		return 2
+	case PriorityHigh:
+		return 3
+	case PriorityUrgent:
+		return 4
+	}
+	return 0
+}
+
+// DueDateLayout is the format of Task.DueDate.
+const DueDateLayout = "2006-01-02"
+
+// ValidDueDate reports whether s is empty (no due date) or a date in
+// DueDateLayout.
+func ValidDueDate(s string) bool {
+	if s == "" {
+		return true
+	}
+	_, err := time.Parse(DueDateLayout, s)
+	return err == nil
+}
+
 type Task struct {
 	ID                  string           `json:"id"`
 	Title               string           `json:"title"`
@@ -67,11 +120,107 @@ type Task struct {
 	EnrichmentStatus    EnrichmentStatus `json:"enrichment_status"`
 	EnrichmentAgentName string           `json:"enrichment_agent_name"`
 	AgentActivity       string           `json:"agent_activity"`
+	Priority            TaskPriority     `json:"priority"`
+	DueDate             string           `json:"due_date"`
 	Position            int              `json:"position"`
 	CreatedAt           time.Time        `json:"created_at"`
 	UpdatedAt           time.Time        `json:"updated_at"`
+	// IssueNumber and IssueRepo (OWNER/REPO) link the task to the GitHub
+	// issue it was imported from. IssueRepo is empty for tasks imported
+	// before it was recorded.
+	IssueNumber int    `json:"issue_number,omitempty"`
+	IssueRepo   string `json:"issue_repo,omitempty"`
 	// BlockedBy is populated at read time, not stored in the tasks table.
 	BlockedBy []string `json:"blocked_by,omitempty"`
+	// Labels are read from task_labels along with the task, sorted by name.
+	Labels []Label `json:"labels,omitempty"`
+	// Checklist is read from checklist_items along with the task, in order.
+	Checklist []ChecklistItem `json:"checklist,omitempty"`
+	// AgentQueued is read from agent_queue: an agent is waiting for a free
+	// slot to start on the task.
+	AgentQueued bool `json:"agent_queued,omitempty"`
+}
+
+// Overdue reports whether the task's due date is before the day of now.
+// Tasks in last, the workflow's last column, are finished and never
+// overdue.
+func (t Task) Overdue(now time.Time, last TaskStatus) bool {
+	return t.Status != last && t.DueDate != "" && t.DueDate < now.Format(DueDateLayout)
+}
+
+// CompareUrgency orders tasks most urgent first: by priority, then by due
+// date (tasks without one last), then by position.
+func CompareUrgency(a, b Task) int {
+	if ra, rb := a.Priority.Rank(), b.Priority.Rank(); ra != rb {
+		return rb - ra
+	}
+	if a.DueDate != b.DueDate {
+		switch {
+		case a.DueDate == "":
+			return 1
+		case b.DueDate == "":
+			return -1
+		}
+		return strings.Compare(a.DueDate, b.DueDate)
+	}
+	return a.Position - b.Position
+}
+
+// HasLabel reports whether the task carries the named label.
+func (t Task) HasLabel(name string) bool {
+	for _, l := range t.Labels {
+		if l.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ChecklistProgress returns how many checklist items are done, out of how
+// many.
+func (t Task) ChecklistProgress() (done, total int) {
+	for _, item := range t.Checklist {
+		if item.Done {
+			done++
+		}
+	}
+	return done, len(t.Checklist)
+}
+
+// Label tags tasks. Color is a "#rrggbb" hex string.
+type Label struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// ChecklistItem is one acceptance criterion of a task.
+type ChecklistItem struct {
+	ID       int64  `json:"id"`
+	TaskID   string `json:"task_id"`
+	Text     string `json:"text"`
+	Done     bool   `json:"done"`
+	Position int    `json:"position"`
+}
+
+// Search hit kinds: where in a task the query matched.
+const (
+	SearchHitTask    = "task"
+	SearchHitComment = "comment"
+)
+
+// SearchHit is a task matching a full-text search, with a snippet of its
+// best-ranked match: the title and description, or one of its comments.
+type SearchHit struct {
+	TaskID    string     `json:"task_id"`
+	TaskTitle string     `json:"task_title"`
+	Status    TaskStatus `json:"status"`
+	Kind      string     `json:"kind"`
+	CommentID string     `json:"comment_id,omitempty"`
+	// Snippet is an excerpt of the matched text with matching terms
+	// wrapped in '*'.
+	Snippet string `json:"snippet"`
+	// Rank is the match's bm25 score; lower is better.
+	Rank float64 `json:"rank"`
 }
 
 // TaskFieldUpdate holds optional field updates. Nil pointer = don't update.
@@ -85,6 +234,8 @@ type TaskFieldUpdate struct {
 	PRNumber            *int              `json:"pr_number,omitempty"`
 	EnrichmentStatus    *EnrichmentStatus `json:"enrichment_status,omitempty"`
 	EnrichmentAgentName *string           `json:"enrichment_agent_name,omitempty"`
+	Priority            *TaskPriority     `json:"priority,omitempty"`
+	DueDate             *string           `json:"due_date,omitempty"`
 }
 
 type Comment struct {
@@ -95,6 +246,18 @@ type Comment struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// TaskEvent records one change to a task: a field going from OldValue to
+// NewValue, or an action such as "created" or "comment".
+type TaskEvent struct {
+	ID        int64     `json:"id"`
+	TaskID    string    `json:"task_id"`
+	Actor     string    `json:"actor"`
+	Field     string    `json:"field"`
+	OldValue  string    `json:"old_value"`
+	NewValue  string    `json:"new_value"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type SuggestionType string
 
 const (
@@ -137,3 +300,20 @@ type Suggestion struct {
 	Status    SuggestionStatus `json:"status"`
 	CreatedAt time.Time        `json:"created_at"`
 }
+
+// QueuedAgent is an agent waiting in agent_queue for a free slot.
+type QueuedAgent struct {
+	TaskID string `json:"task_id"`
+	// Runner is the runner ID to start, or "" for the preferred one.
+	Runner   string    `json:"runner,omitempty"`
+	QueuedAt time.Time `json:"queued_at"`
+}
+
+// Event is one sequenced sync message kept for replay to reconnecting peers.
+type Event struct {
+	Seq       int64
+	Type      string
+	Sender    string
+	Payload   []byte
+	CreatedAt time.Time
+}
@@ -1,14 +1,13 @@
 package db
 
-const schemaVersion = 7
+const schemaVersion = 18
 
 const schemaSQL = `
 CREATE TABLE IF NOT EXISTS tasks (
     id TEXT PRIMARY KEY,
     title TEXT NOT NULL CHECK(length(title) > 0 AND length(title) <= 500),
     description TEXT DEFAULT '',
-    status TEXT NOT NULL DEFAULT 'backlog'
-        CHECK(status IN ('backlog','brainstorm','planning','in_progress','review','done')),
+    status TEXT NOT NULL DEFAULT 'backlog' CHECK(length(status) > 0),
     assignee TEXT DEFAULT '',
     branch_name TEXT DEFAULT '',
     pr_url TEXT DEFAULT '',
@@ -26,7 +25,12 @@ CREATE TABLE IF NOT EXISTS tasks (
     agent_activity TEXT DEFAULT '',
     position INTEGER NOT NULL DEFAULT 0,
     created_at TEXT NOT NULL,
-    updated_at TEXT NOT NULL
+    updated_at TEXT NOT NULL,
+    priority TEXT NOT NULL DEFAULT ''
+        CHECK(priority IN ('','low','medium','high','urgent')),
+    due_date TEXT NOT NULL DEFAULT '',
+    issue_number INTEGER NOT NULL DEFAULT 0,
+    issue_repo TEXT NOT NULL DEFAULT ''
 );
 
 CREATE TABLE IF NOT EXISTS comments (
@@ -57,6 +61,51 @@ CREATE TABLE IF NOT EXISTS suggestions (
     created_at TEXT NOT NULL
 );
 
+CREATE TABLE IF NOT EXISTS events (
+    seq INTEGER PRIMARY KEY,
+    type TEXT NOT NULL,
+    sender TEXT NOT NULL DEFAULT '',
+    payload TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS task_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    actor TEXT NOT NULL DEFAULT '',
+    field TEXT NOT NULL,
+    old_value TEXT NOT NULL DEFAULT '',
+    new_value TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS labels (
+    name TEXT PRIMARY KEY,
+    color TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS task_labels (
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    label TEXT NOT NULL REFERENCES labels(name) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, label)
+);
+
+CREATE TABLE IF NOT EXISTS checklist_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    text TEXT NOT NULL CHECK(length(text) > 0 AND length(text) <= 500),
+    done INTEGER NOT NULL DEFAULT 0,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS agent_queue (
+    task_id TEXT PRIMARY KEY REFERENCES tasks(id) ON DELETE CASCADE,
+    runner TEXT NOT NULL DEFAULT '',
+    queued_at TEXT NOT NULL
+);
+
+` + searchIndexSQL + searchCommentsSQL + `
 CREATE TABLE IF NOT EXISTS meta (
     key TEXT PRIMARY KEY,
     value TEXT NOT NULL
@@ -74,6 +123,10 @@ CREATE INDEX IF NOT EXISTS idx_comments_task_id ON comments(task_id);
 CREATE INDEX IF NOT EXISTS idx_task_deps_depends_on ON task_dependencies(depends_on);
 CREATE INDEX IF NOT EXISTS idx_suggestions_task_id ON suggestions(task_id);
 CREATE INDEX IF NOT EXISTS idx_suggestions_status ON suggestions(status);
+CREATE INDEX IF NOT EXISTS idx_task_events_task_id ON task_events(task_id);
+CREATE INDEX IF NOT EXISTS idx_task_labels_label ON task_labels(label);
+CREATE INDEX IF NOT EXISTS idx_checklist_items_task_id ON checklist_items(task_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_issue_number ON tasks(issue_number) WHERE issue_number > 0;
 `
 
 const migrateV1toV2 = `
@@ -278,3 +331,286 @@ ALTER TABLE task_dependencies_v7 RENAME TO task_dependencies;
 
 CREATE INDEX idx_task_deps_depends_on ON task_dependencies(depends_on);
 `
+
+// migrateV7toV8SQL adds the sync event log used to replay missed messages.
+const migrateV7toV8SQL = `
+CREATE TABLE IF NOT EXISTS events (
+    seq INTEGER PRIMARY KEY,
+    type TEXT NOT NULL,
+    sender TEXT NOT NULL DEFAULT '',
+    payload TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL
+);
+`
+
+// migrateV8toV9SQL drops the fixed status CHECK so columns can be defined in
+// the config. Runs with foreign keys off, like the v5 migration.
+const migrateV8toV9SQL = `
+CREATE TABLE tasks_v9 (
+    id TEXT PRIMARY KEY,
+    title TEXT NOT NULL CHECK(length(title) > 0 AND length(title) <= 500),
+    description TEXT DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'backlog' CHECK(length(status) > 0),
+    assignee TEXT DEFAULT '',
+    branch_name TEXT DEFAULT '',
+    pr_url TEXT DEFAULT '',
+    pr_number INTEGER DEFAULT 0,
+    agent_name TEXT DEFAULT '',
+    agent_status TEXT DEFAULT 'idle'
+        CHECK(agent_status IN ('idle','active','completed','error')),
+    agent_started_at TEXT DEFAULT '',
+    agent_spawned_status TEXT DEFAULT '',
+    reset_requested INTEGER DEFAULT 0,
+    skip_permissions INTEGER DEFAULT 0,
+    enrichment_status TEXT DEFAULT ''
+        CHECK(enrichment_status IN ('','pending','enriching','done','error','skipped')),
+    enrichment_agent_name TEXT DEFAULT '',
+    agent_activity TEXT DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
+INSERT INTO tasks_v9 (
+    id, title, description, status, assignee, branch_name, pr_url, pr_number,
+    agent_name, agent_status, agent_started_at, agent_spawned_status,
+    reset_requested, skip_permissions,
+    enrichment_status, enrichment_agent_name, agent_activity,
+    position, created_at, updated_at
+) SELECT
+    id, title, description, status, assignee, branch_name, pr_url, pr_number,
+    agent_name, agent_status, agent_started_at, agent_spawned_status,
+    reset_requested, skip_permissions,
+    enrichment_status, enrichment_agent_name, agent_activity,
+    position, created_at, updated_at
+FROM tasks;
+
+DROP TABLE tasks;
+ALTER TABLE tasks_v9 RENAME TO tasks;
+
+CREATE INDEX idx_tasks_status ON tasks(status);
+CREATE INDEX idx_tasks_assignee ON tasks(assignee);
+CREATE UNIQUE INDEX idx_tasks_status_position ON tasks(status, position);
+`
+
+// migrateV9toV10SQL adds the per-task change history.
+const migrateV9toV10SQL = `
+CREATE TABLE IF NOT EXISTS task_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    actor TEXT NOT NULL DEFAULT '',
+    field TEXT NOT NULL,
+    old_value TEXT NOT NULL DEFAULT '',
+    new_value TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_events_task_id ON task_events(task_id);
+`
+
+// migrateV10toV11SQL adds task labels.
+const migrateV10toV11SQL = `
+CREATE TABLE IF NOT EXISTS labels (
+    name TEXT PRIMARY KEY,
+    color TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS task_labels (
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    label TEXT NOT NULL REFERENCES labels(name) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, label)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_labels_label ON task_labels(label);
+`
+
+// migrateV11toV12SQL adds task priority and due date.
+const migrateV11toV12SQL = `
+ALTER TABLE tasks ADD COLUMN priority TEXT NOT NULL DEFAULT ''
+    CHECK(priority IN ('','low','medium','high','urgent'));
+ALTER TABLE tasks ADD COLUMN due_date TEXT NOT NULL DEFAULT '';
+`
+
+// migrateV12toV13SQL adds task checklists.
+const migrateV12toV13SQL = `
+CREATE TABLE IF NOT EXISTS checklist_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    text TEXT NOT NULL CHECK(length(text) > 0 AND length(text) <= 500),
+    done INTEGER NOT NULL DEFAULT 0,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_checklist_items_task_id ON checklist_items(task_id);
+`
+
+// migrateV13toV14SQL adds full-text search over tasks and indexes the
+// existing ones; v18 rebuilds it keyed by rowid. migrateV13toV14SQL_comments does the same for comments; it
+// is applied only when the comments table exists.
+const migrateV13toV14SQL = `
+CREATE VIRTUAL TABLE IF NOT EXISTS search_index USING fts5(
+    task_id UNINDEXED,
+    kind UNINDEXED,
+    ref UNINDEXED,
+    title,
+    body,
+    tokenize = 'porter unicode61'
+);
+
+CREATE TRIGGER IF NOT EXISTS tasks_search_insert AFTER INSERT ON tasks BEGIN
+    INSERT INTO search_index (task_id, kind, ref, title, body)
+    VALUES (new.id, 'task', new.id, new.title, new.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS tasks_search_update AFTER UPDATE OF title, description ON tasks BEGIN
+    DELETE FROM search_index WHERE kind = 'task' AND ref = old.id;
+    INSERT INTO search_index (task_id, kind, ref, title, body)
+    VALUES (new.id, 'task', new.id, new.title, new.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS tasks_search_delete AFTER DELETE ON tasks BEGIN
+    DELETE FROM search_index WHERE task_id = old.id;
+END;
+
+INSERT INTO search_index (task_id, kind, ref, title, body)
+SELECT id, 'task', id, title, description FROM tasks;
+`
+
+const migrateV13toV14SQL_comments = `
+CREATE TRIGGER IF NOT EXISTS comments_search_insert AFTER INSERT ON comments BEGIN
+    INSERT INTO search_index (task_id, kind, ref, title, body)
+    VALUES (new.task_id, 'comment', new.id, '', new.body);
+END;
+
+CREATE TRIGGER IF NOT EXISTS comments_search_update AFTER UPDATE OF body ON comments BEGIN
+    DELETE FROM search_index WHERE kind = 'comment' AND ref = old.id;
+    INSERT INTO search_index (task_id, kind, ref, title, body)
+    VALUES (new.task_id, 'comment', new.id, '', new.body);
+END;
+
+CREATE TRIGGER IF NOT EXISTS comments_search_delete AFTER DELETE ON comments BEGIN
+    DELETE FROM search_index WHERE kind = 'comment' AND ref = old.id;
+END;
+
+INSERT INTO search_index (task_id, kind, ref, title, body)
+SELECT task_id, 'comment', id, '', body FROM comments;
+`
+
+// migrateV14toV15SQL links tasks to GitHub issues.
+const migrateV14toV15SQL = `
+ALTER TABLE tasks ADD COLUMN issue_number INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_issue_number ON tasks(issue_number) WHERE issue_number > 0;
+`
+
+// migrateV15toV16SQL adds the queue of agents waiting for a free slot.
+const migrateV15toV16SQL = `
+CREATE TABLE IF NOT EXISTS agent_queue (
+    task_id TEXT PRIMARY KEY REFERENCES tasks(id) ON DELETE CASCADE,
+    runner TEXT NOT NULL DEFAULT '',
+    queued_at TEXT NOT NULL
+);
+`
+
+// migrateV16toV17SQL records which repository an imported issue came from.
+const migrateV16toV17SQL = `
+ALTER TABLE tasks ADD COLUMN issue_repo TEXT NOT NULL DEFAULT '';
+`
+
+// searchIndexSQL creates the full-text index and indexes tasks in it;
+// searchCommentsSQL indexes comments too. search_refs gives every indexed
+// task and comment a stable rowid in search_index, so the triggers update
+// and delete by rowid instead of scanning the index's UNINDEXED columns.
+const searchIndexSQL = `
+CREATE TABLE IF NOT EXISTS search_refs (
+    id INTEGER PRIMARY KEY,
+    kind TEXT NOT NULL,
+    ref TEXT NOT NULL,
+    task_id TEXT NOT NULL,
+    UNIQUE(kind, ref)
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_refs_task_id ON search_refs(task_id);
+
+CREATE VIRTUAL TABLE IF NOT EXISTS search_index USING fts5(
+    task_id UNINDEXED,
+    kind UNINDEXED,
+    ref UNINDEXED,
+    title,
+    body,
+    tokenize = 'porter unicode61'
+);
+
+CREATE TRIGGER IF NOT EXISTS tasks_search_insert AFTER INSERT ON tasks BEGIN
+    INSERT INTO search_refs (kind, ref, task_id) VALUES ('task', new.id, new.id);
+    INSERT INTO search_index (rowid, task_id, kind, ref, title, body)
+    VALUES ((SELECT id FROM search_refs WHERE kind = 'task' AND ref = new.id),
+            new.id, 'task', new.id, new.title, new.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS tasks_search_update AFTER UPDATE OF title, description ON tasks BEGIN
+    DELETE FROM search_index
+    WHERE rowid = (SELECT id FROM search_refs WHERE kind = 'task' AND ref = old.id);
+    INSERT INTO search_index (rowid, task_id, kind, ref, title, body)
+    VALUES ((SELECT id FROM search_refs WHERE kind = 'task' AND ref = new.id),
+            new.id, 'task', new.id, new.title, new.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS tasks_search_delete AFTER DELETE ON tasks BEGIN
+    DELETE FROM search_index
+    WHERE rowid IN (SELECT id FROM search_refs WHERE task_id = old.id);
+    DELETE FROM search_refs WHERE task_id = old.id;
+END;
+`
+
+const searchCommentsSQL = `
+CREATE TRIGGER IF NOT EXISTS comments_search_insert AFTER INSERT ON comments BEGIN
+    INSERT INTO search_refs (kind, ref, task_id) VALUES ('comment', new.id, new.task_id);
+    INSERT INTO search_index (rowid, task_id, kind, ref, title, body)
+    VALUES ((SELECT id FROM search_refs WHERE kind = 'comment' AND ref = new.id),
+            new.task_id, 'comment', new.id, '', new.body);
+END;
+
+CREATE TRIGGER IF NOT EXISTS comments_search_update AFTER UPDATE OF body ON comments BEGIN
+    DELETE FROM search_index
+    WHERE rowid = (SELECT id FROM search_refs WHERE kind = 'comment' AND ref = old.id);
+    INSERT INTO search_index (rowid, task_id, kind, ref, title, body)
+    VALUES ((SELECT id FROM search_refs WHERE kind = 'comment' AND ref = new.id),
+            new.task_id, 'comment', new.id, '', new.body);
+END;
+
+CREATE TRIGGER IF NOT EXISTS comments_search_delete AFTER DELETE ON comments BEGIN
+    DELETE FROM search_index
+    WHERE rowid = (SELECT id FROM search_refs WHERE kind = 'comment' AND ref = old.id);
+    DELETE FROM search_refs WHERE kind = 'comment' AND ref = old.id;
+END;
+`
+
+// migrateV17toV18SQL rebuilds the v14 search index keyed by rowid, whose
+// triggers deleted rows by scanning for their task_id, kind and ref.
+// migrateV17toV18SQL_comments indexes comments again; it is applied only
+// when the comments table exists.
+const migrateV17toV18SQL = `
+DROP TRIGGER IF EXISTS tasks_search_insert;
+DROP TRIGGER IF EXISTS tasks_search_update;
+DROP TRIGGER IF EXISTS tasks_search_delete;
+DROP TRIGGER IF EXISTS comments_search_insert;
+DROP TRIGGER IF EXISTS comments_search_update;
+DROP TRIGGER IF EXISTS comments_search_delete;
+DROP TABLE IF EXISTS search_index;
+` + searchIndexSQL + `
+INSERT INTO search_refs (kind, ref, task_id) SELECT 'task', id, id FROM tasks;
+
+INSERT INTO search_index (rowid, task_id, kind, ref, title, body)
+SELECT r.id, t.id, 'task', t.id, t.title, t.description
+FROM search_refs r JOIN tasks t ON r.kind = 'task' AND r.ref = t.id;
+`
+
+const migrateV17toV18SQL_comments = searchCommentsSQL + `
+INSERT INTO search_refs (kind, ref, task_id) SELECT 'comment', id, task_id FROM comments;
+
+INSERT INTO search_index (rowid, task_id, kind, ref, title, body)
+SELECT r.id, c.task_id, 'comment', c.id, '', c.body
+FROM search_refs r JOIN comments c ON r.kind = 'comment' AND r.ref = c.id;
+`
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Search runs a full-text query over task titles, descriptions and
+// comments. Each word of query must match, as a prefix and after
+// stemming, so "login fail" finds "Failing logins". Tasks are returned
+// best match first, at most limit of them (all when limit <= 0).
+func (d *DB) Search(ctx context.Context, query string, limit int) ([]SearchHit, error) {
+	match := ftsQuery(query)
+	if match == "" {
+		return nil, nil
+	}
+	// Title matches weigh more than body text; the UNINDEXED columns
+	// don't count.
+	rows, err := d.conn.QueryContext(ctx,
+		`SELECT s.task_id, t.title, t.status, s.kind, s.ref,
+		        snippet(search_index, -1, '*', '*', '…', 12),
+		        bm25(search_index, 0, 0, 0, 5.0, 1.0) AS rank
+		 FROM search_index s JOIN tasks t ON t.id = s.task_id
+		 WHERE search_index MATCH ?
+		 ORDER BY rank`, match)
+	if err != nil {
+		return nil, fmt.Errorf("searching: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []SearchHit
+	seen := make(map[string]bool)
+	for rows.Next() {
+		var h SearchHit
+		var ref string
+		if err := rows.Scan(&h.TaskID, &h.TaskTitle, &h.Status, &h.Kind, &ref, &h.Snippet, &h.Rank); err != nil {
+			return nil, fmt.Errorf("scanning search hit: %w", err)
+		}
+		// Keep only the best match of each task.
+		if seen[h.TaskID] {
+			continue
+		}
+		seen[h.TaskID] = true
+		if h.Kind == SearchHitComment {
+			h.CommentID = ref
+		}
+		hits = append(hits, h)
+		if limit > 0 && len(hits) == limit {
+			break
+		}
+	}
+	return hits, rows.Err()
+}
+
+// ftsQuery turns free text into an FTS5 query that ANDs every word as a
+// quoted prefix term, so punctuation in the input can't be read as query
+// syntax.
+func ftsQuery(text string) string {
+	var terms []string
+	for _, w := range strings.Fields(text) {
+		if !strings.ContainsFunc(w, isTokenRune) {
+			continue
+		}
+		terms = append(terms, `"`+strings.ReplaceAll(w, `"`, `""`)+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
+
+// isTokenRune reports whether r is part of a word for the unicode61
+// tokenizer.
+func isTokenRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
@@ -0,0 +1,68 @@
+package db_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/markx3/agentboard/internal/db"
+)
+
+func TestSearch(t *testing.T) {
+	database := setupTestDB(t)
+	ctx := context.Background()
+
+	login, _ := database.CreateTask(ctx, "Fix failing logins", "Users are logged out after a deploy")
+	cache, _ := database.CreateTask(ctx, "Add a cache", "Speed up the board")
+	database.AddComment(ctx, cache.ID, "enrichment", "The session store also handles login tokens")
+	database.CreateTask(ctx, "Unrelated", "")
+
+	hits, err := database.Search(ctx, "login fail", 0)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(hits) != 1 || hits[0].TaskID != login.ID || hits[0].Kind != db.SearchHitTask {
+		t.Fatalf("hits = %+v, want the login task (prefix and stemmed match)", hits)
+	}
+	if !strings.Contains(hits[0].Snippet, "*") {
+		t.Errorf("snippet %q should mark the matched terms", hits[0].Snippet)
+	}
+
+	hits, _ = database.Search(ctx, "login", 0)
+	if len(hits) != 2 || hits[0].TaskID != login.ID {
+		t.Fatalf("hits = %+v, want the title match first, then the comment", hits)
+	}
+	if hits[1].Kind != db.SearchHitComment || hits[1].CommentID == "" || hits[1].TaskTitle != "Add a cache" {
+		t.Errorf("comment hit = %+v, want the cache task's comment", hits[1])
+	}
+	if hits, _ := database.Search(ctx, "login", 1); len(hits) != 1 {
+		t.Errorf("got %d hits with limit 1", len(hits))
+	}
+
+	// The index follows edits and deletes.
+	login.Title = "Fix session expiry"
+	login.Description = ""
+	if err := database.UpdateTask(ctx, login); err != nil {
+		t.Fatal(err)
+	}
+	if hits, _ := database.Search(ctx, "expiry", 0); len(hits) != 1 {
+		t.Errorf("got %d hits for the new title, want 1", len(hits))
+	}
+	login.Description = "Sessions expire early"
+	if err := database.UpdateTask(ctx, login); err != nil {
+		t.Fatal(err)
+	}
+	if hits, _ := database.Search(ctx, "expiry", 0); len(hits) != 1 {
+		t.Errorf("got %d hits after a second edit, want 1", len(hits))
+	}
+	database.DeleteTask(ctx, cache.ID)
+	if hits, _ := database.Search(ctx, "login", 0); len(hits) != 0 {
+		t.Errorf("hits after edit and delete = %+v, want none", hits)
+	}
+
+	for _, q := range []string{"", "  ", `"`, "-", "AND", "foo(bar"} {
+		if _, err := database.Search(ctx, q, 0); err != nil {
+			t.Errorf("Search(%q): %v", q, err)
+		}
+	}
+}
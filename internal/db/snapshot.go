@@ -0,0 +1,365 @@
+package db
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SnapshotVersion is the version of the snapshot format written by
+// `agentboard export`. Import refuses snapshots from newer versions.
+const SnapshotVersion = 1
+
+// Snapshot is a whole board: tasks with their labels, checklists and
+// dependencies (in BlockedBy), comments, suggestions and every label.
+// Task history is not included.
+type Snapshot struct {
+	Version     int          `json:"version"`
+	ExportedAt  time.Time    `json:"exported_at"`
+	Labels      []Label      `json:"labels"`
+	Tasks       []Task       `json:"tasks"`
+	Comments    []Comment    `json:"comments"`
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+// ImportConflict says what Import does with a task or suggestion whose ID
+// already exists on the board.
+type ImportConflict string
+
+const (
+	// ImportSkip keeps the existing task and ignores the imported one,
+	// along with its comments and suggestions.
+	ImportSkip ImportConflict = "skip"
+	// ImportReplace deletes the existing task, with everything attached
+	// to it, and imports the snapshot's version.
+	ImportReplace ImportConflict = "replace"
+	// ImportCopy imports the task as a new one with a fresh ID.
+	ImportCopy ImportConflict = "copy"
+)
+
+// Valid reports whether c is a known conflict policy.
+func (c ImportConflict) Valid() bool {
+	switch c {
+	case ImportSkip, ImportReplace, ImportCopy:
+		return true
+	}
+	return false
+}
+
+type ImportOptions struct {
+	OnConflict ImportConflict
+	// NewIDs gives every imported task, comment and suggestion a fresh ID,
+	// as if each one conflicted under ImportCopy.
+	NewIDs bool
+	// DefaultStatus is the column of tasks without a status; backlog if
+	// empty.
+	DefaultStatus TaskStatus
+	// ValidStatus, when set, reports whether a status is a column of the
+	// board. Tasks in other columns go to DefaultStatus.
+	ValidStatus func(TaskStatus) bool
+}
+
+// ImportResult counts what Import did.
+type ImportResult struct {
+	Created     int `json:"created"`
+	Replaced    int `json:"replaced"`
+	Skipped     int `json:"skipped"`
+	Comments    int `json:"comments"`
+	Suggestions int `json:"suggestions"`
+	// IDs maps task IDs in the snapshot to their IDs on the board, for
+	// every task that was created, replaced or skipped.
+	IDs map[string]string `json:"ids"`
+	// Imported lists the board IDs of the tasks created or replaced.
+	Imported []string `json:"-"`
+}
+
+// Import recreates the tasks, comments, suggestions and labels of snap in
+// one transaction. References between them follow tasks that get a new ID.
+// Tasks keep their column and relative order, after the tasks already in
+// it; dependencies on tasks that are neither imported nor on the board
+// are dropped. Tasks whose ID is not a UUID get a fresh one, and no task
+// comes in with a running agent or a branch from the board it left.
+func (d *DB) Import(ctx context.Context, snap *Snapshot, opts ImportOptions) (*ImportResult, error) {
+	if snap.Version > SnapshotVersion {
+		return nil, fmt.Errorf("snapshot version %d is newer than supported (%d)", snap.Version, SnapshotVersion)
+	}
+	if opts.OnConflict == "" {
+		opts.OnConflict = ImportSkip
+	}
+	if !opts.OnConflict.Valid() {
+		return nil, fmt.Errorf("invalid conflict policy %q (use: skip, replace, copy)", opts.OnConflict)
+	}
+	if opts.DefaultStatus == "" {
+		opts.DefaultStatus = StatusBacklog
+	}
+
+	tx, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, l := range snap.Labels {
+		if err := importLabel(ctx, tx, l, opts.OnConflict == ImportReplace); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &ImportResult{IDs: make(map[string]string, len(snap.Tasks))}
+	imported := make(map[string]bool, len(snap.Tasks))
+
+	// Insert column by column, in order, so positions stay relative.
+	tasks := slices.Clone(snap.Tasks)
+	for i := range tasks {
+		status := tasks[i].Status
+		if status == "" || (opts.ValidStatus != nil && !opts.ValidStatus(status)) {
+			tasks[i].Status = opts.DefaultStatus
+		}
+	}
+	slices.SortStableFunc(tasks, func(a, b Task) int {
+		if a.Status != b.Status {
+			return cmp.Compare(a.Status, b.Status)
+		}
+		return a.Position - b.Position
+	})
+	for _, t := range tasks {
+		if t.Title == "" {
+			return nil, fmt.Errorf("snapshot has a task without a title")
+		}
+		if !t.Priority.Valid() {
+			return nil, fmt.Errorf("task %q has an invalid priority %q", t.Title, t.Priority)
+		}
+		if !ValidDueDate(t.DueDate) {
+			return nil, fmt.Errorf("task %q has an invalid due date %q (use YYYY-MM-DD)", t.Title, t.DueDate)
+		}
+		_, parseErr := uuid.Parse(t.ID)
+		validID := parseErr == nil
+		exists := false
+		if validID {
+			var err error
+			if exists, err = rowExists(ctx, tx, "SELECT 1 FROM tasks WHERE id = ?", t.ID); err != nil {
+				return nil, err
+			}
+		}
+		id := t.ID
+		var dependents []string
+		switch {
+		case opts.NewIDs || !validID || (exists && opts.OnConflict == ImportCopy):
+			id = uuid.New().String()
+		case exists && opts.OnConflict == ImportSkip:
+			res.IDs[t.ID] = id
+			res.Skipped++
+			continue
+		case exists:
+			// Tasks left on the board keep depending on the replaced one.
+			if dependents, err = taskDependents(ctx, tx, id); err != nil {
+				return nil, err
+			}
+			if _, err := tx.ExecContext(ctx, "DELETE FROM tasks WHERE id = ?", id); err != nil {
+				return nil, fmt.Errorf("replacing task %s: %w", id, err)
+			}
+			res.Replaced++
+		}
+		if !exists || id != t.ID {
+			res.Created++
+		}
+
+		if t.ID != "" {
+			res.IDs[t.ID] = id
+		}
+		t.ID = id
+		clearAgentState(&t)
+		if err := tx.QueryRowContext(ctx,
+			"SELECT COALESCE(MAX(position), -1) + 1 FROM tasks WHERE status = ?", t.Status).Scan(&t.Position); err != nil {
+			return nil, fmt.Errorf("getting max position: %w", err)
+		}
+		if t.CreatedAt.IsZero() {
+			t.CreatedAt = time.Now().UTC()
+		}
+		if t.UpdatedAt.IsZero() {
+			t.UpdatedAt = t.CreatedAt
+		}
+		if err := insertTask(ctx, tx, &t); err != nil {
+			return nil, fmt.Errorf("importing task %q: %w", t.Title, err)
+		}
+		for _, l := range t.Labels {
+			if err := importLabel(ctx, tx, l, false); err != nil {
+				return nil, err
+			}
+			if _, err := tx.ExecContext(ctx,
+				"INSERT OR IGNORE INTO task_labels (task_id, label) VALUES (?, ?)", id, l.Name); err != nil {
+				return nil, fmt.Errorf("adding label: %w", err)
+			}
+		}
+		for _, item := range t.Checklist {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO checklist_items (task_id, text, done, position, created_at)
+				 VALUES (?, ?, ?, ?, ?)`,
+				id, item.Text, boolToInt(item.Done), item.Position, t.CreatedAt.Format(time.RFC3339)); err != nil {
+				return nil, fmt.Errorf("adding checklist item: %w", err)
+			}
+		}
+		for _, dep := range dependents {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT OR IGNORE INTO task_dependencies (task_id, depends_on, created_at) VALUES (?, ?, ?)`,
+				dep, id, time.Now().UTC().Format(time.RFC3339)); err != nil {
+				return nil, fmt.Errorf("restoring dependency: %w", err)
+			}
+		}
+		imported[id] = true
+		res.Imported = append(res.Imported, id)
+	}
+
+	for _, t := range snap.Tasks {
+		from, ok := res.IDs[t.ID]
+		if !ok || !imported[from] {
+			continue
+		}
+		for _, blocker := range t.BlockedBy {
+			to, ok := res.IDs[blocker]
+			if !ok {
+				to = blocker
+			}
+			found, err := rowExists(ctx, tx, "SELECT 1 FROM tasks WHERE id = ?", to)
+			if err != nil {
+				return nil, err
+			}
+			if !found || to == from {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx,
+				`INSERT OR IGNORE INTO task_dependencies (task_id, depends_on, created_at) VALUES (?, ?, ?)`,
+				from, to, time.Now().UTC().Format(time.RFC3339)); err != nil {
+				return nil, fmt.Errorf("adding dependency: %w", err)
+			}
+		}
+	}
+
+	for _, c := range snap.Comments {
+		taskID, ok := res.IDs[c.TaskID]
+		if !ok || !imported[taskID] {
+			continue
+		}
+		if opts.NewIDs || taskID != c.TaskID || c.ID == "" {
+			c.ID = uuid.New().String()
+		}
+		result, err := tx.ExecContext(ctx,
+			`INSERT OR IGNORE INTO comments (id, task_id, author, body, created_at)
+			 VALUES (?, ?, ?, ?, ?)`,
+			c.ID, taskID, c.Author, c.Body, c.CreatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("importing comment: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			res.Comments++
+		}
+	}
+
+	for _, s := range snap.Suggestions {
+		var taskID interface{}
+		verb := "INSERT OR IGNORE"
+		if s.TaskID != "" {
+			id, ok := res.IDs[s.TaskID]
+			if !ok || !imported[id] {
+				continue
+			}
+			taskID = id
+			if opts.NewIDs || id != s.TaskID || s.ID == "" {
+				s.ID = uuid.New().String()
+			}
+		} else {
+			exists, err := rowExists(ctx, tx, "SELECT 1 FROM suggestions WHERE id = ?", s.ID)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case opts.NewIDs || s.ID == "" || (exists && opts.OnConflict == ImportCopy):
+				s.ID = uuid.New().String()
+			case exists && opts.OnConflict == ImportReplace:
+				verb = "INSERT OR REPLACE"
+			}
+		}
+		result, err := tx.ExecContext(ctx,
+			verb+` INTO suggestions (id, task_id, type, author, title, message, status, created_at)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			s.ID, taskID, s.Type, s.Author, s.Title, s.Message, s.Status,
+			s.CreatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("importing suggestion: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			res.Suggestions++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing import: %w", err)
+	}
+	return res, nil
+}
+
+// clearAgentState drops what only made sense on the board a task was
+// exported from: its agent run and worktree branch.
+func clearAgentState(t *Task) {
+	if t.AgentStatus == "" || t.AgentStatus == AgentActive {
+		t.AgentStatus = AgentIdle
+	}
+	t.AgentName = ""
+	t.AgentStartedAt = ""
+	t.AgentSpawnedStatus = ""
+	t.AgentActivity = ""
+	t.ResetRequested = false
+	t.BranchName = ""
+	if t.EnrichmentStatus == EnrichmentEnriching {
+		t.EnrichmentStatus = EnrichmentPending
+	}
+	t.EnrichmentAgentName = ""
+}
+
+// importLabel creates a label, or with overwrite also sets the color of
+// an existing one.
+func importLabel(ctx context.Context, tx *sql.Tx, l Label, overwrite bool) error {
+	query := "INSERT OR IGNORE INTO labels (name, color) VALUES (?, ?)"
+	if overwrite {
+		query = `INSERT INTO labels (name, color) VALUES (?, ?)
+			 ON CONFLICT(name) DO UPDATE SET color = excluded.color`
+	}
+	if _, err := tx.ExecContext(ctx, query, l.Name, l.Color); err != nil {
+		return fmt.Errorf("importing label %q: %w", l.Name, err)
+	}
+	return nil
+}
+
+func taskDependents(ctx context.Context, tx *sql.Tx, id string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT task_id FROM task_dependencies WHERE depends_on = ?", id)
+	if err != nil {
+		return nil, fmt.Errorf("listing dependents: %w", err)
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var dep string
+		if err := rows.Scan(&dep); err != nil {
+			return nil, fmt.Errorf("scanning dependent: %w", err)
+		}
+		ids = append(ids, dep)
+	}
+	return ids, rows.Err()
+}
+
+func rowExists(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (bool, error) {
+	var one int
+	err := tx.QueryRowContext(ctx, query, args...).Scan(&one)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("checking existing rows: %w", err)
+	}
+	return true, nil
+}
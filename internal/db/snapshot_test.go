@@ -0,0 +1,226 @@
+package db_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/markx3/agentboard/internal/db"
+)
+
+// exportDB builds a snapshot straight from the db, the way board.Export
+// does through the service.
+func exportDB(t *testing.T, database *db.DB) *db.Snapshot {
+	t.Helper()
+	ctx := context.Background()
+	snap := &db.Snapshot{Version: db.SnapshotVersion}
+	snap.Labels, _ = database.ListLabels(ctx)
+	tasks, err := database.ListTasks(ctx)
+	if err != nil {
+		t.Fatalf("ListTasks: %v", err)
+	}
+	deps, _ := database.ListAllDependencies(ctx)
+	for _, task := range tasks {
+		task.BlockedBy = deps[task.ID]
+		snap.Tasks = append(snap.Tasks, task)
+		comments, _ := database.ListComments(ctx, task.ID)
+		snap.Comments = append(snap.Comments, comments...)
+	}
+	snap.Suggestions, _ = database.ListSuggestions(ctx, db.SuggestionPending)
+	return snap
+}
+
+func TestImportRoundTrip(t *testing.T) {
+	src := setupTestDB(t)
+	ctx := context.Background()
+
+	first, _ := src.CreateTask(ctx, "First", "the blocker")
+	second, _ := src.CreateTaskIn(ctx, db.StatusInProgress, "Second", "blocked")
+	second.Priority = db.PriorityHigh
+	second.Assignee = "alice"
+	src.UpdateTask(ctx, second)
+	src.AddDependency(ctx, second.ID, first.ID)
+	src.EnsureLabel(ctx, "bug", "#ff0000")
+	src.AddTaskLabel(ctx, second.ID, "bug")
+	item, _ := src.AddChecklistItem(ctx, second.ID, "Tests pass")
+	src.SetChecklistItemDone(ctx, second.ID, item.ID, true)
+	src.AddComment(ctx, second.ID, "bob", "looking into it")
+	src.CreateSuggestion(ctx, second.ID, db.SuggestionHint, "agent", "Hint", "check the logs")
+	src.CreateSuggestion(ctx, "", db.SuggestionProposal, "agent", "New task", "split it up")
+
+	snap := exportDB(t, src)
+	dst := setupTestDB(t)
+	res, err := dst.Import(ctx, snap, db.ImportOptions{})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if res.Created != 2 || res.Comments != 1 || res.Suggestions != 2 {
+		t.Errorf("result = %+v, want 2 tasks, 1 comment, 2 suggestions", res)
+	}
+
+	got, err := dst.GetTask(ctx, second.ID)
+	if err != nil {
+		t.Fatalf("imported task missing: %v", err)
+	}
+	if got.Status != db.StatusInProgress || got.Priority != db.PriorityHigh || got.Assignee != "alice" {
+		t.Errorf("imported task = %+v, want status, priority and assignee kept", got)
+	}
+	if len(got.Labels) != 1 || got.Labels[0].Color != "#ff0000" {
+		t.Errorf("labels = %+v, want bug in red", got.Labels)
+	}
+	if len(got.Checklist) != 1 || !got.Checklist[0].Done {
+		t.Errorf("checklist = %+v, want one ticked item", got.Checklist)
+	}
+	if deps, _ := dst.ListDependencies(ctx, second.ID); len(deps) != 1 || deps[0] != first.ID {
+		t.Errorf("dependencies = %v, want [%s]", deps, first.ID)
+	}
+	if comments, _ := dst.ListComments(ctx, second.ID); len(comments) != 1 || comments[0].Author != "bob" {
+		t.Errorf("comments = %+v", comments)
+	}
+
+	// Importing again skips every task and everything attached to it.
+	res, err = dst.Import(ctx, snap, db.ImportOptions{})
+	if err != nil {
+		t.Fatalf("second Import: %v", err)
+	}
+	if res.Created != 0 || res.Skipped != 2 || res.Comments != 0 {
+		t.Errorf("re-import result = %+v, want everything skipped", res)
+	}
+	if tasks, _ := dst.ListTasks(ctx); len(tasks) != 2 {
+		t.Errorf("got %d tasks after skip, want 2", len(tasks))
+	}
+}
+
+func TestImportConflicts(t *testing.T) {
+	database := setupTestDB(t)
+	ctx := context.Background()
+
+	blocker, _ := database.CreateTask(ctx, "Blocker", "")
+	task, _ := database.CreateTask(ctx, "Original", "")
+	database.AddDependency(ctx, task.ID, blocker.ID)
+	database.AddComment(ctx, task.ID, "bob", "first")
+	snap := exportDB(t, database)
+	// A task created after the export depends on one that gets replaced.
+	later, _ := database.CreateTask(ctx, "Later", "")
+	database.AddDependency(ctx, later.ID, task.ID)
+
+	for i := range snap.Tasks {
+		if snap.Tasks[i].ID == task.ID {
+			snap.Tasks[i].Title = "Changed"
+		}
+	}
+	res, err := database.Import(ctx, snap, db.ImportOptions{OnConflict: db.ImportReplace})
+	if err != nil {
+		t.Fatalf("Import replace: %v", err)
+	}
+	if res.Replaced != 2 || res.Created != 0 {
+		t.Errorf("replace result = %+v, want 2 replaced", res)
+	}
+	got, _ := database.GetTask(ctx, task.ID)
+	if got.Title != "Changed" {
+		t.Errorf("title = %q, want the snapshot's", got.Title)
+	}
+	if comments, _ := database.ListComments(ctx, task.ID); len(comments) != 1 {
+		t.Errorf("got %d comments after replace, want 1", len(comments))
+	}
+	if deps, _ := database.ListDependencies(ctx, later.ID); len(deps) != 1 || deps[0] != task.ID {
+		t.Errorf("board task's dependencies = %v, want the replaced task kept", deps)
+	}
+
+	res, err = database.Import(ctx, snap, db.ImportOptions{OnConflict: db.ImportCopy})
+	if err != nil {
+		t.Fatalf("Import copy: %v", err)
+	}
+	if res.Created != 2 {
+		t.Errorf("copy result = %+v, want 2 created", res)
+	}
+	copyID := res.IDs[task.ID]
+	if copyID == "" || copyID == task.ID {
+		t.Fatalf("copy ID = %q, want a fresh one", copyID)
+	}
+	// The copy depends on the copied blocker, not the original.
+	if deps, _ := database.ListDependencies(ctx, copyID); len(deps) != 1 || deps[0] != res.IDs[blocker.ID] {
+		t.Errorf("copy dependencies = %v, want [%s]", deps, res.IDs[blocker.ID])
+	}
+	if comments, _ := database.ListComments(ctx, copyID); len(comments) != 1 {
+		t.Errorf("copy has %d comments, want 1", len(comments))
+	}
+	if tasks, _ := database.ListTasks(ctx); len(tasks) != 5 {
+		t.Errorf("got %d tasks, want 5", len(tasks))
+	}
+
+	if _, err := database.Import(ctx, snap, db.ImportOptions{OnConflict: "merge"}); err == nil {
+		t.Error("unknown conflict policy should fail")
+	}
+	if _, err := database.Import(ctx, &db.Snapshot{Version: db.SnapshotVersion + 1}, db.ImportOptions{}); err == nil {
+		t.Error("newer snapshot version should fail")
+	}
+}
+
+func TestImportMinimalFile(t *testing.T) {
+	database := setupTestDB(t)
+	ctx := context.Background()
+
+	var snap db.Snapshot
+	err := json.Unmarshal([]byte(`{"version": 1, "tasks": [
+		{"id": "t1", "title": "Seed"},
+		{"id": "t2", "title": "Was running", "status": "planning", "blocked_by": ["t1"],
+		 "agent_status": "active", "agent_name": "claude", "branch_name": "agentboard/x"}
+	], "comments": [{"task_id": "t2", "author": "bob", "body": "hi"}]}`), &snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := database.Import(ctx, &snap, db.ImportOptions{})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if res.Created != 2 || res.Comments != 1 || len(res.Imported) != 2 {
+		t.Errorf("result = %+v, want 2 tasks and 1 comment", res)
+	}
+
+	for _, old := range []string{"t1", "t2"} {
+		if _, err := uuid.Parse(res.IDs[old]); err != nil {
+			t.Errorf("task %s got ID %q, want a fresh UUID", old, res.IDs[old])
+		}
+	}
+	seed, _ := database.GetTask(ctx, res.IDs["t1"])
+	if seed.Status != db.StatusBacklog || seed.AgentStatus != db.AgentIdle {
+		t.Errorf("seed task in %q with agent %q, want backlog and idle", seed.Status, seed.AgentStatus)
+	}
+	ran, _ := database.GetTask(ctx, res.IDs["t2"])
+	if ran.AgentStatus != db.AgentIdle || ran.AgentName != "" || ran.BranchName != "" {
+		t.Errorf("imported task kept agent state: %+v", ran)
+	}
+	if deps, _ := database.ListDependencies(ctx, ran.ID); len(deps) != 1 || deps[0] != seed.ID {
+		t.Errorf("dependencies = %v, want the seed task", deps)
+	}
+}
+
+func TestImportValidatesTasks(t *testing.T) {
+	database := setupTestDB(t)
+	ctx := context.Background()
+
+	for _, bad := range []db.Task{
+		{Title: "Bad priority", Priority: "critical"},
+		{Title: "Bad due date", DueDate: "next week"},
+	} {
+		snap := &db.Snapshot{Version: db.SnapshotVersion, Tasks: []db.Task{bad}}
+		if _, err := database.Import(ctx, snap, db.ImportOptions{}); err == nil {
+			t.Errorf("importing %q: expected an error", bad.Title)
+		}
+	}
+
+	snap := &db.Snapshot{Version: db.SnapshotVersion, Tasks: []db.Task{{Title: "Elsewhere", Status: "qa"}}}
+	res, err := database.Import(ctx, snap, db.ImportOptions{
+		DefaultStatus: db.StatusPlanning,
+		ValidStatus:   func(s db.TaskStatus) bool { return s != "qa" },
+	})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if task, _ := database.GetTask(ctx, res.Imported[0]); task.Status != db.StatusPlanning {
+		t.Errorf("status = %q, want the default column for an unknown one", task.Status)
+	}
+}
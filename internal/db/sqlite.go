@@ -202,6 +202,160 @@ func (d *DB) migrate(ctx context.Context) error {
 		}
 	}
 
+	if currentVersion < 8 {
+		tx, txErr := d.conn.BeginTx(ctx, nil)
+		if txErr != nil {
+			return fmt.Errorf("beginning v8 migration transaction: %w", txErr)
+		}
+		defer tx.Rollback()
+		if txErr = applyMigration(ctx, tx, 8, migrateV7toV8SQL); txErr != nil {
+			return txErr
+		}
+		if txErr = tx.Commit(); txErr != nil {
+			return fmt.Errorf("committing v8 migration: %w", txErr)
+		}
+	}
+
+	if currentVersion < 9 {
+		if err := d.migrateWithoutForeignKeys(ctx, 9, migrateV8toV9SQL); err != nil {
+			return err
+		}
+	}
+
+	if currentVersion < 10 {
+		tx, txErr := d.conn.BeginTx(ctx, nil)
+		if txErr != nil {
+			return fmt.Errorf("beginning v10 migration transaction: %w", txErr)
+		}
+		defer tx.Rollback()
+		if txErr = applyMigration(ctx, tx, 10, migrateV9toV10SQL); txErr != nil {
+			return txErr
+		}
+		if txErr = tx.Commit(); txErr != nil {
+			return fmt.Errorf("committing v10 migration: %w", txErr)
+		}
+	}
+
+	if currentVersion < 11 {
+		tx, txErr := d.conn.BeginTx(ctx, nil)
+		if txErr != nil {
+			return fmt.Errorf("beginning v11 migration transaction: %w", txErr)
+		}
+		defer tx.Rollback()
+		if txErr = applyMigration(ctx, tx, 11, migrateV10toV11SQL); txErr != nil {
+			return txErr
+		}
+		if txErr = tx.Commit(); txErr != nil {
+			return fmt.Errorf("committing v11 migration: %w", txErr)
+		}
+	}
+
+	if currentVersion < 12 {
+		tx, txErr := d.conn.BeginTx(ctx, nil)
+		if txErr != nil {
+			return fmt.Errorf("beginning v12 migration transaction: %w", txErr)
+		}
+		defer tx.Rollback()
+		if txErr = applyMigration(ctx, tx, 12, migrateV11toV12SQL); txErr != nil {
+			return txErr
+		}
+		if txErr = tx.Commit(); txErr != nil {
+			return fmt.Errorf("committing v12 migration: %w", txErr)
+		}
+	}
+
+	if currentVersion < 13 {
+		tx, txErr := d.conn.BeginTx(ctx, nil)
+		if txErr != nil {
+			return fmt.Errorf("beginning v13 migration transaction: %w", txErr)
+		}
+		defer tx.Rollback()
+		if txErr = applyMigration(ctx, tx, 13, migrateV12toV13SQL); txErr != nil {
+			return txErr
+		}
+		if txErr = tx.Commit(); txErr != nil {
+			return fmt.Errorf("committing v13 migration: %w", txErr)
+		}
+	}
+
+	if currentVersion < 14 {
+		if err := d.migrateV13toV14(ctx); err != nil {
+			return err
+		}
+	}
+
+	if currentVersion < 15 {
+		tx, txErr := d.conn.BeginTx(ctx, nil)
+		if txErr != nil {
+			return fmt.Errorf("beginning v15 migration transaction: %w", txErr)
+		}
+		defer tx.Rollback()
+		if txErr = applyMigration(ctx, tx, 15, migrateV14toV15SQL); txErr != nil {
+			return txErr
+		}
+		if txErr = tx.Commit(); txErr != nil {
+			return fmt.Errorf("committing v15 migration: %w", txErr)
+		}
+	}
+
+	if currentVersion < 16 {
+		tx, txErr := d.conn.BeginTx(ctx, nil)
+		if txErr != nil {
+			return fmt.Errorf("beginning v16 migration transaction: %w", txErr)
+		}
+		defer tx.Rollback()
+		if txErr = applyMigration(ctx, tx, 16, migrateV15toV16SQL); txErr != nil {
+			return txErr
+		}
+		if txErr = tx.Commit(); txErr != nil {
+			return fmt.Errorf("committing v16 migration: %w", txErr)
+		}
+	}
+
+	if currentVersion < 17 {
+		tx, txErr := d.conn.BeginTx(ctx, nil)
+		if txErr != nil {
+			return fmt.Errorf("beginning v17 migration transaction: %w", txErr)
+		}
+		defer tx.Rollback()
+		if txErr = applyMigration(ctx, tx, 17, migrateV16toV17SQL); txErr != nil {
+			return txErr
+		}
+		if txErr = tx.Commit(); txErr != nil {
+			return fmt.Errorf("committing v17 migration: %w", txErr)
+		}
+	}
+
+	if currentVersion < 18 {
+		if err := d.migrateV17toV18(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrateWithoutForeignKeys applies a migration that rebuilds tables. Foreign
+// keys are switched off OUTSIDE the transaction (SQLite ignores the pragma
+// inside one) so dropping the old table doesn't cascade.
+func (d *DB) migrateWithoutForeignKeys(ctx context.Context, version int, migrationSQL string) error {
+	if _, err := d.conn.ExecContext(ctx, "PRAGMA foreign_keys=OFF"); err != nil {
+		return fmt.Errorf("disabling foreign keys for v%d migration: %w", version, err)
+	}
+	defer d.conn.ExecContext(ctx, "PRAGMA foreign_keys=ON")
+
+	tx, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning v%d migration transaction: %w", version, err)
+	}
+	defer tx.Rollback()
+
+	if err := applyMigration(ctx, tx, version, migrationSQL); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing v%d migration: %w", version, err)
+	}
 	return nil
 }
 
@@ -347,3 +501,59 @@ func (d *DB) migrateV6toV7(ctx context.Context) error {
 
 	return nil
 }
+
+// migrateV13toV14 builds the search index. Comments are indexed only if
+// the database has a comments table.
+func (d *DB) migrateV13toV14(ctx context.Context) error {
+	// Inspect before the transaction: it holds the only connection.
+	hasComments, err := tableExists(ctx, d.conn, "comments")
+	if err != nil {
+		return fmt.Errorf("checking comments table: %w", err)
+	}
+
+	tx, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning v14 migration transaction: %w", err)
+	}
+	defer tx.Rollback()
+	if err := applyMigration(ctx, tx, 14, migrateV13toV14SQL); err != nil {
+		return err
+	}
+	if hasComments {
+		if _, err := tx.ExecContext(ctx, migrateV13toV14SQL_comments); err != nil {
+			return fmt.Errorf("indexing comments in v14: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing v14 migration: %w", err)
+	}
+	return nil
+}
+
+// migrateV17toV18 rebuilds the search index keyed by rowid. Like v14, it
+// indexes comments only if the database has a comments table.
+func (d *DB) migrateV17toV18(ctx context.Context) error {
+	// Inspect before the transaction: it holds the only connection.
+	hasComments, err := tableExists(ctx, d.conn, "comments")
+	if err != nil {
+		return fmt.Errorf("checking comments table: %w", err)
+	}
+
+	tx, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning v18 migration transaction: %w", err)
+	}
+	defer tx.Rollback()
+	if err := applyMigration(ctx, tx, 18, migrateV17toV18SQL); err != nil {
+		return err
+	}
+	if hasComments {
+		if _, err := tx.ExecContext(ctx, migrateV17toV18SQL_comments); err != nil {
+			return fmt.Errorf("indexing comments in v18: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing v18 migration: %w", err)
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"slices"
 	"strings"
 	"time"
 
@@ -16,6 +17,11 @@ type scanner interface {
 	Scan(dest ...interface{}) error
 }
 
+// execer is satisfied by both *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
 func scanTask(s scanner) (Task, error) {
 	var t Task
 	var createdAt, updatedAt string
@@ -27,7 +33,8 @@ func scanTask(s scanner) (Task, error) {
 		&resetRequested, &skipPermissions,
 		&t.EnrichmentStatus, &t.EnrichmentAgentName,
 		&t.AgentActivity, &t.Position,
-		&createdAt, &updatedAt); err != nil {
+		&createdAt, &updatedAt,
+		&t.Priority, &t.DueDate, &t.IssueNumber, &t.IssueRepo); err != nil {
 		return Task{}, err
 	}
 	t.ResetRequested = resetRequested != 0
@@ -48,63 +55,85 @@ const taskColumns = `id, title, description, status, assignee, branch_name, pr_u
 		        agent_name, agent_status, agent_started_at, agent_spawned_status,
 		        reset_requested, skip_permissions,
 		        enrichment_status, enrichment_agent_name,
-		        agent_activity, position, created_at, updated_at`
+		        agent_activity, position, created_at, updated_at,
+		        priority, due_date, issue_number, issue_repo`
 
 func (d *DB) CreateTask(ctx context.Context, title, description string) (*Task, error) {
+	return d.CreateTaskIn(ctx, StatusBacklog, title, description)
+}
+
+// CreateTaskIn creates a task at the end of the given column.
+func (d *DB) CreateTaskIn(ctx context.Context, status TaskStatus, title, description string) (*Task, error) {
+	task := &Task{
+		Title:            title,
+		Description:      description,
+		Status:           status,
+		AgentStatus:      AgentIdle,
+		EnrichmentStatus: EnrichmentSkipped,
+	}
+	if err := d.CreateTaskFrom(ctx, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// CreateTaskFrom stores task as a new task at the end of its column, in one
+// insert. It fills in the ID, position and timestamps.
+func (d *DB) CreateTaskFrom(ctx context.Context, task *Task) error {
 	tx, err := d.conn.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("beginning transaction: %w", err)
+		return fmt.Errorf("beginning transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	now := time.Now().UTC()
-	id := uuid.New().String()
-
-	// Get next position for backlog column (within transaction)
+	// Get next position in the column (within transaction)
 	var maxPos sql.NullInt64
 	err = tx.QueryRowContext(ctx,
-		"SELECT MAX(position) FROM tasks WHERE status = ?", StatusBacklog).Scan(&maxPos)
+		"SELECT MAX(position) FROM tasks WHERE status = ?", task.Status).Scan(&maxPos)
 	if err != nil {
-		return nil, fmt.Errorf("getting max position: %w", err)
+		return fmt.Errorf("getting max position: %w", err)
 	}
 	pos := 0
 	if maxPos.Valid {
 		pos = int(maxPos.Int64) + 1
 	}
 
-	task := &Task{
-		ID:               id,
-		Title:            title,
-		Description:      description,
-		Status:           StatusBacklog,
-		AgentStatus:      AgentIdle,
-		EnrichmentStatus: EnrichmentSkipped,
-		Position:         pos,
-		CreatedAt:        now,
-		UpdatedAt:        now,
+	now := time.Now().UTC()
+	task.ID = uuid.New().String()
+	task.Position = pos
+	task.CreatedAt = now
+	task.UpdatedAt = now
+
+	if err := insertTask(ctx, tx, task); err != nil {
+		return err
 	}
 
-	_, err = tx.ExecContext(ctx,
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing task: %w", err)
+	}
+	return nil
+}
+
+// insertTask stores every column of task as given.
+func insertTask(ctx context.Context, e execer, task *Task) error {
+	_, err := e.ExecContext(ctx,
 		`INSERT INTO tasks (id, title, description, status, assignee, branch_name, pr_url, pr_number,
 		 agent_name, agent_status, agent_started_at, agent_spawned_status, reset_requested,
 		 skip_permissions, enrichment_status, enrichment_agent_name, agent_activity,
-		 position, created_at, updated_at)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		 position, created_at, updated_at, priority, due_date, issue_number, issue_repo)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		task.ID, task.Title, task.Description, task.Status,
 		task.Assignee, task.BranchName, task.PRUrl, task.PRNumber,
 		task.AgentName, task.AgentStatus, task.AgentStartedAt, task.AgentSpawnedStatus,
 		boolToInt(task.ResetRequested), boolToInt(task.SkipPermissions),
 		task.EnrichmentStatus, task.EnrichmentAgentName, task.AgentActivity,
 		task.Position,
-		task.CreatedAt.Format(time.RFC3339), task.UpdatedAt.Format(time.RFC3339))
+		task.CreatedAt.Format(time.RFC3339), task.UpdatedAt.Format(time.RFC3339),
+		task.Priority, task.DueDate, task.IssueNumber, task.IssueRepo)
 	if err != nil {
-		return nil, fmt.Errorf("inserting task: %w", err)
+		return fmt.Errorf("inserting task: %w", err)
 	}
-
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("committing task: %w", err)
-	}
-	return task, nil
+	return nil
 }
 
 func (d *DB) GetTask(ctx context.Context, id string) (*Task, error) {
@@ -114,6 +143,21 @@ func (d *DB) GetTask(ctx context.Context, id string) (*Task, error) {
 	if err != nil {
 		return nil, fmt.Errorf("getting task: %w", err)
 	}
+	labels, err := d.taskLabels(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	t.Labels = labels[id]
+	checklists, err := d.taskChecklists(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	t.Checklist = checklists[id]
+	err = d.conn.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM agent_queue WHERE task_id = ?)", id).Scan(&t.AgentQueued)
+	if err != nil {
+		return nil, fmt.Errorf("getting task: %w", err)
+	}
 	return &t, nil
 }
 
@@ -133,7 +177,19 @@ func (d *DB) ListTasks(ctx context.Context) ([]Task, error) {
 		}
 		tasks = append(tasks, t)
 	}
-	return tasks, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := d.attachLabels(ctx, tasks); err != nil {
+		return nil, err
+	}
+	if err := d.attachChecklists(ctx, tasks); err != nil {
+		return nil, err
+	}
+	if err := d.attachAgentQueue(ctx, tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
 }
 
 func (d *DB) ListTasksByStatus(ctx context.Context, status TaskStatus) ([]Task, error) {
@@ -152,7 +208,19 @@ func (d *DB) ListTasksByStatus(ctx context.Context, status TaskStatus) ([]Task,
 		}
 		tasks = append(tasks, t)
 	}
-	return tasks, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := d.attachLabels(ctx, tasks); err != nil {
+		return nil, err
+	}
+	if err := d.attachChecklists(ctx, tasks); err != nil {
+		return nil, err
+	}
+	if err := d.attachAgentQueue(ctx, tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
 }
 
 func (d *DB) UpdateTask(ctx context.Context, task *Task) error {
@@ -162,13 +230,15 @@ func (d *DB) UpdateTask(ctx context.Context, task *Task) error {
 		 pr_url=?, pr_number=?, agent_name=?, agent_status=?, agent_started_at=?,
 		 agent_spawned_status=?, reset_requested=?, skip_permissions=?,
 		 enrichment_status=?, enrichment_agent_name=?,
-		 agent_activity=?, position=?, updated_at=?
+		 agent_activity=?, position=?, updated_at=?, priority=?, due_date=?,
+		 issue_number=?, issue_repo=?
 		 WHERE id=?`,
 		task.Title, task.Description, task.Status, task.Assignee, task.BranchName,
 		task.PRUrl, task.PRNumber, task.AgentName, task.AgentStatus, task.AgentStartedAt,
 		task.AgentSpawnedStatus, boolToInt(task.ResetRequested), boolToInt(task.SkipPermissions),
 		task.EnrichmentStatus, task.EnrichmentAgentName,
-		task.AgentActivity, task.Position, task.UpdatedAt.Format(time.RFC3339), task.ID)
+		task.AgentActivity, task.Position, task.UpdatedAt.Format(time.RFC3339),
+		task.Priority, task.DueDate, task.IssueNumber, task.IssueRepo, task.ID)
 	if err != nil {
 		return fmt.Errorf("updating task: %w", err)
 	}
@@ -176,8 +246,16 @@ func (d *DB) UpdateTask(ctx context.Context, task *Task) error {
 }
 
 // UpdateTaskFields updates only non-nil fields. Column names are hardcoded
-// (not user-supplied) so there is no SQL injection risk.
+// (not user-supplied) so there is no SQL injection risk. A status change
+// puts the task at the end of its new column, in the same transaction as
+// the other fields.
 func (d *DB) UpdateTaskFields(ctx context.Context, id string, fields TaskFieldUpdate) error {
+	tx, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	var setClauses []string
 	var args []interface{}
 
@@ -190,8 +268,19 @@ func (d *DB) UpdateTaskFields(ctx context.Context, id string, fields TaskFieldUp
 		args = append(args, *fields.Description)
 	}
 	if fields.Status != nil {
-		setClauses = append(setClauses, "status=?")
-		args = append(args, *fields.Status)
+		var current TaskStatus
+		if err := tx.QueryRowContext(ctx, "SELECT status FROM tasks WHERE id = ?", id).Scan(&current); err != nil {
+			return fmt.Errorf("getting task: %w", err)
+		}
+		if *fields.Status != current {
+			var pos int
+			if err := tx.QueryRowContext(ctx,
+				"SELECT COALESCE(MAX(position), -1) + 1 FROM tasks WHERE status = ?", *fields.Status).Scan(&pos); err != nil {
+				return fmt.Errorf("getting max position: %w", err)
+			}
+			setClauses = append(setClauses, "status=?", "position=?")
+			args = append(args, *fields.Status, pos)
+		}
 	}
 	if fields.Assignee != nil {
 		setClauses = append(setClauses, "assignee=?")
@@ -217,6 +306,14 @@ func (d *DB) UpdateTaskFields(ctx context.Context, id string, fields TaskFieldUp
 		setClauses = append(setClauses, "enrichment_agent_name=?")
 		args = append(args, *fields.EnrichmentAgentName)
 	}
+	if fields.Priority != nil {
+		setClauses = append(setClauses, "priority=?")
+		args = append(args, *fields.Priority)
+	}
+	if fields.DueDate != nil {
+		setClauses = append(setClauses, "due_date=?")
+		args = append(args, *fields.DueDate)
+	}
 
 	if len(setClauses) == 0 {
 		return nil
@@ -227,11 +324,10 @@ func (d *DB) UpdateTaskFields(ctx context.Context, id string, fields TaskFieldUp
 	args = append(args, id)
 
 	query := fmt.Sprintf("UPDATE tasks SET %s WHERE id=?", strings.Join(setClauses, ", "))
-	_, err := d.conn.ExecContext(ctx, query, args...)
-	if err != nil {
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 		return fmt.Errorf("updating task fields: %w", err)
 	}
-	return nil
+	return tx.Commit()
 }
 
 // UpdateAgentActivity updates the agent_activity field for a task.
@@ -282,6 +378,82 @@ func (d *DB) MoveTask(ctx context.Context, id string, newStatus TaskStatus) erro
 	return tx.Commit()
 }
 
+// ReorderTask moves a task within its column to just before beforeID or,
+// when beforeID is empty, just after afterID. The column is renumbered
+// 0..n-1 in one transaction; positions go through negative values first so
+// the unique (status, position) index holds at every step.
+func (d *DB) ReorderTask(ctx context.Context, id, beforeID, afterID string) error {
+	anchor := beforeID
+	if anchor == "" {
+		anchor = afterID
+	}
+	if anchor == "" {
+		return fmt.Errorf("reordering task: a task to place it before or after is required")
+	}
+	if anchor == id {
+		return fmt.Errorf("reordering task: a task can't be placed next to itself")
+	}
+
+	tx, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status, anchorStatus TaskStatus
+	if err := tx.QueryRowContext(ctx, "SELECT status FROM tasks WHERE id = ?", id).Scan(&status); err != nil {
+		return fmt.Errorf("getting task: %w", err)
+	}
+	if err := tx.QueryRowContext(ctx, "SELECT status FROM tasks WHERE id = ?", anchor).Scan(&anchorStatus); err != nil {
+		return fmt.Errorf("getting task to reorder against: %w", err)
+	}
+	if status != anchorStatus {
+		return fmt.Errorf("reordering task: tasks are in different columns (%s and %s)", status, anchorStatus)
+	}
+
+	rows, err := tx.QueryContext(ctx, "SELECT id FROM tasks WHERE status = ? ORDER BY position", status)
+	if err != nil {
+		return fmt.Errorf("listing column: %w", err)
+	}
+	var order []string
+	for rows.Next() {
+		var tid string
+		if err := rows.Scan(&tid); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning task id: %w", err)
+		}
+		if tid != id {
+			order = append(order, tid)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	at := slices.Index(order, anchor)
+	if beforeID == "" {
+		at++
+	}
+	order = slices.Insert(order, at, id)
+
+	for i, tid := range order {
+		if _, err := tx.ExecContext(ctx, "UPDATE tasks SET position = ? WHERE id = ?", -(i + 1), tid); err != nil {
+			return fmt.Errorf("reordering task: %w", err)
+		}
+	}
+	for i, tid := range order {
+		if _, err := tx.ExecContext(ctx, "UPDATE tasks SET position = ? WHERE id = ?", i, tid); err != nil {
+			return fmt.Errorf("reordering task: %w", err)
+		}
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	if _, err := tx.ExecContext(ctx, "UPDATE tasks SET updated_at = ? WHERE id = ?", now, id); err != nil {
+		return fmt.Errorf("reordering task: %w", err)
+	}
+	return tx.Commit()
+}
+
 func (d *DB) DeleteTask(ctx context.Context, id string) error {
 	_, err := d.conn.ExecContext(ctx, "DELETE FROM tasks WHERE id=?", id)
 	if err != nil {
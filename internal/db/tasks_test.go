@@ -5,7 +5,10 @@ import (
 	"database/sql"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/markx3/agentboard/internal/db"
 	_ "modernc.org/sqlite"
@@ -93,6 +96,79 @@ func TestMoveTask(t *testing.T) {
 	}
 }
 
+func TestReorderTask(t *testing.T) {
+	database := setupTestDB(t)
+	ctx := context.Background()
+
+	var ids []string
+	for _, title := range []string{"A", "B", "C", "D"} {
+		task, _ := database.CreateTask(ctx, title, "")
+		ids = append(ids, task.ID)
+	}
+	other, _ := database.CreateTaskIn(ctx, db.StatusPlanning, "Elsewhere", "")
+
+	order := func() string {
+		tasks, _ := database.ListTasksByStatus(ctx, db.StatusBacklog)
+		var b strings.Builder
+		for i, task := range tasks {
+			if task.Position != i {
+				t.Errorf("%s at position %d, want %d", task.Title, task.Position, i)
+			}
+			b.WriteString(task.Title)
+		}
+		return b.String()
+	}
+
+	if err := database.ReorderTask(ctx, ids[3], ids[0], ""); err != nil {
+		t.Fatalf("ReorderTask before: %v", err)
+	}
+	if got := order(); got != "DABC" {
+		t.Errorf("after moving D before A: %s, want DABC", got)
+	}
+	if err := database.ReorderTask(ctx, ids[3], "", ids[2]); err != nil {
+		t.Fatalf("ReorderTask after: %v", err)
+	}
+	if got := order(); got != "ABCD" {
+		t.Errorf("after moving D after C: %s, want ABCD", got)
+	}
+	if err := database.ReorderTask(ctx, ids[0], "", ids[1]); err != nil {
+		t.Fatalf("ReorderTask after: %v", err)
+	}
+	if got := order(); got != "BACD" {
+		t.Errorf("after moving A after B: %s, want BACD", got)
+	}
+
+	if err := database.ReorderTask(ctx, ids[0], other.ID, ""); err == nil {
+		t.Error("reordering against a task in another column should fail")
+	}
+	if err := database.ReorderTask(ctx, ids[0], ids[0], ""); err == nil {
+		t.Error("reordering a task against itself should fail")
+	}
+	if err := database.ReorderTask(ctx, ids[0], "", ""); err == nil {
+		t.Error("reordering without a target should fail")
+	}
+}
+
+func TestCustomStatuses(t *testing.T) {
+	database := setupTestDB(t)
+	ctx := context.Background()
+
+	task, err := database.CreateTaskIn(ctx, "todo", "Custom column", "")
+	if err != nil {
+		t.Fatalf("creating task in custom column: %v", err)
+	}
+	if task.Status != "todo" {
+		t.Errorf("got status %q, want todo", task.Status)
+	}
+	if err := database.MoveTask(ctx, task.ID, "qa"); err != nil {
+		t.Fatalf("moving to custom column: %v", err)
+	}
+	got, _ := database.GetTask(ctx, task.ID)
+	if got.Status != "qa" {
+		t.Errorf("got status %q, want qa", got.Status)
+	}
+}
+
 func TestDeleteTask(t *testing.T) {
 	database := setupTestDB(t)
 	ctx := context.Background()
@@ -367,6 +443,32 @@ func TestUpdateTaskFieldsNoop(t *testing.T) {
 	}
 }
 
+func TestUpdateTaskFieldsStatusIsAtomic(t *testing.T) {
+	database := setupTestDB(t)
+	ctx := context.Background()
+
+	task, _ := database.CreateTask(ctx, "Mover", "")
+	database.CreateTaskIn(ctx, db.StatusPlanning, "Already planned", "")
+
+	// A status change goes with the other fields or not at all.
+	title, status, bad := "Moved", db.StatusPlanning, db.TaskPriority("critical")
+	if err := database.UpdateTaskFields(ctx, task.ID, db.TaskFieldUpdate{Title: &title, Status: &status, Priority: &bad}); err == nil {
+		t.Fatal("expected CHECK constraint violation for unknown priority")
+	}
+	got, _ := database.GetTask(ctx, task.ID)
+	if got.Title != "Mover" || got.Status != db.StatusBacklog {
+		t.Errorf("after failed update got %q in %s, want nothing changed", got.Title, got.Status)
+	}
+
+	if err := database.UpdateTaskFields(ctx, task.ID, db.TaskFieldUpdate{Title: &title, Status: &status}); err != nil {
+		t.Fatalf("UpdateTaskFields: %v", err)
+	}
+	got, _ = database.GetTask(ctx, task.ID)
+	if got.Title != "Moved" || got.Status != db.StatusPlanning || got.Position != 1 {
+		t.Errorf("got %q in %s at %d, want Moved at the end of planning", got.Title, got.Status, got.Position)
+	}
+}
+
 func TestUpdateTaskFieldsMultiple(t *testing.T) {
 	database := setupTestDB(t)
 	ctx := context.Background()
@@ -661,6 +763,15 @@ func TestSchemaV4toV5Migration(t *testing.T) {
 		t.Errorf("comment body: got %q, want %q", comments[0].Body, "A v4 comment")
 	}
 
+	// Existing tasks and comments are indexed for search
+	hits, err := database.Search(ctx, "comment", 0)
+	if err != nil {
+		t.Fatalf("searching migrated db: %v", err)
+	}
+	if len(hits) != 1 || hits[0].CommentID != "comment-1" {
+		t.Errorf("search hits = %+v, want the migrated comment", hits)
+	}
+
 	// Verify new tables exist: create a dependency
 	t2, err := database.CreateTask(ctx, "V5 New Task", "")
 	if err != nil {
@@ -695,3 +806,51 @@ func TestSchemaV4toV5Migration(t *testing.T) {
 		t.Errorf("enrichment_status after update: got %q", got.EnrichmentStatus)
 	}
 }
+
+func TestPriorityAndDueDate(t *testing.T) {
+	database := setupTestDB(t)
+	ctx := context.Background()
+
+	task, _ := database.CreateTask(ctx, "Urgent", "")
+	high := db.PriorityHigh
+	due := "2026-03-01"
+	if err := database.UpdateTaskFields(ctx, task.ID, db.TaskFieldUpdate{Priority: &high, DueDate: &due}); err != nil {
+		t.Fatalf("UpdateTaskFields: %v", err)
+	}
+	got, _ := database.GetTask(ctx, task.ID)
+	if got.Priority != db.PriorityHigh || got.DueDate != due {
+		t.Errorf("got priority %q due %q, want high 2026-03-01", got.Priority, got.DueDate)
+	}
+
+	got.Priority = "critical"
+	if err := database.UpdateTask(ctx, got); err == nil {
+		t.Error("expected CHECK constraint violation for unknown priority")
+	}
+
+	if !got.Overdue(time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local), db.StatusDone) {
+		t.Error("task due yesterday should be overdue")
+	}
+	if got.Overdue(time.Date(2026, 3, 1, 23, 0, 0, 0, time.Local), db.StatusDone) {
+		t.Error("task due today should not be overdue")
+	}
+	if got.Overdue(time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local), got.Status) {
+		t.Error("task in the last column should never be overdue")
+	}
+}
+
+func TestCompareUrgency(t *testing.T) {
+	tasks := []db.Task{
+		{ID: "none"},
+		{ID: "low-late", Priority: db.PriorityLow, DueDate: "2026-05-01"},
+		{ID: "urgent", Priority: db.PriorityUrgent},
+		{ID: "low-soon", Priority: db.PriorityLow, DueDate: "2026-04-01"},
+		{ID: "due-only", DueDate: "2026-01-01"},
+	}
+	slices.SortStableFunc(tasks, db.CompareUrgency)
+	want := []string{"urgent", "low-soon", "low-late", "due-only", "none"}
+	for i, task := range tasks {
+		if task.ID != want[i] {
+			t.Errorf("position %d: got %s, want %s", i, task.ID, want[i])
+		}
+	}
+}
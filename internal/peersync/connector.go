@@ -3,82 +3,155 @@ package peersync
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"math/rand/v2"
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/markx3/agentboard/internal/server"
 )
 
+const (
+	reconnectBaseDelay = 500 * time.Millisecond
+	reconnectMaxDelay  = 30 * time.Second
+	// sendBufferSize caps how many messages Send queues while disconnected.
+	sendBufferSize = 256
+)
+
+// ConnState is the state of the connector's link to the server.
+type ConnState int
+
+const (
+	StateConnected ConnState = iota
+	StateReconnecting
+	StateClosed
+)
+
+func (s ConnState) String() string {
+	switch s {
+	case StateConnected:
+		return "connected"
+	case StateReconnecting:
+		return "reconnecting"
+	case StateClosed:
+		return "closed"
+	}
+	return fmt.Sprintf("ConnState(%d)", int(s))
+}
+
+// Connector keeps a WebSocket connection to a sync server. When the
+// connection drops it reconnects with exponential backoff, re-authenticates
+// and asks the server to replay what it missed. Messages sent while
+// disconnected are queued and flushed after reconnecting.
 type Connector struct {
-	addr     string
-	token    string
-	mu       sync.Mutex
-	conn     *websocket.Conn
+	addr    string
+	token   string
+	mu      sync.Mutex
+	conn    *websocket.Conn // nil while disconnected
+	queued  [][]byte        // sent while disconnected
+	closed  bool
+	stop    chan struct{}
+	stopped sync.Once
+	lastSeq atomic.Int64 // highest sequence number received
+
 	Messages chan server.Message
-	done     chan struct{}
+	// States receives each state transition. Sends don't block, so a reader
+	// that falls behind misses intermediate states.
+	States chan ConnState
+	done   chan struct{}
 }
 
 func NewConnector(addr, token string) *Connector {
 	return &Connector{
 		addr:     addr,
 		token:    token,
+		stop:     make(chan struct{}),
 		Messages: make(chan server.Message, 256),
+		States:   make(chan ConnState, 16),
 		done:     make(chan struct{}),
 	}
 }
 
+// Connect dials the server. After the first successful connection the
+// connector reconnects on its own until ctx is cancelled or Close is called.
 func (c *Connector) Connect(ctx context.Context) error {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.conn = conn
+	c.mu.Unlock()
+
+	go c.run(ctx, conn)
+	return nil
+}
+
+// dial opens a connection and authenticates.
+func (c *Connector) dial(ctx context.Context) (*websocket.Conn, error) {
 	wsURL := buildWSURL(c.addr)
 	conn, _, err := websocket.DefaultDialer.DialContext(ctx, wsURL, nil)
 	if err != nil {
-		return fmt.Errorf("connecting to %s: %w", c.addr, err)
+		return nil, fmt.Errorf("connecting to %s: %w", c.addr, err)
 	}
 
-	// Send auth token
+	// Send auth token. The server replays what we missed since lastSeq.
 	authMsg := struct {
-		Token string `json:"token"`
-	}{Token: c.token}
+		Token   string `json:"token"`
+		LastSeq int64  `json:"last_seq,omitempty"`
+	}{Token: c.token, LastSeq: c.lastSeq.Load()}
 	if err := conn.WriteJSON(authMsg); err != nil {
 		conn.Close()
-		return fmt.Errorf("sending auth: %w", err)
+		return nil, fmt.Errorf("sending auth: %w", err)
 	}
-
-	c.mu.Lock()
-	c.conn = conn
-	c.mu.Unlock()
-
-	// Start read pump
-	go c.readPump(ctx)
-
-	return nil
+	return conn, nil
 }
 
-func (c *Connector) readPump(ctx context.Context) {
+// run reads from the connection and reconnects whenever it drops.
+func (c *Connector) run(ctx context.Context, conn *websocket.Conn) {
 	defer func() {
+		c.setState(StateClosed)
 		close(c.done)
-		c.mu.Lock()
-		if c.conn != nil {
-			c.conn.Close()
-		}
-		c.mu.Unlock()
 	}()
 
 	// Close connection when context is cancelled to unblock ReadMessage
 	go func() {
-		<-ctx.Done()
-		c.mu.Lock()
-		if c.conn != nil {
-			c.conn.Close()
+		select {
+		case <-ctx.Done():
+			c.Close()
+		case <-c.stop:
 		}
-		c.mu.Unlock()
 	}()
 
 	for {
-		_, message, err := c.conn.ReadMessage()
+		c.readPump(ctx, conn)
+
+		c.mu.Lock()
+		c.conn = nil
+		closed := c.closed
+		c.mu.Unlock()
+		conn.Close()
+		if closed || ctx.Err() != nil {
+			return
+		}
+
+		c.setState(StateReconnecting)
+		if conn = c.reconnect(ctx); conn == nil {
+			return
+		}
+		c.setState(StateConnected)
+	}
+}
+
+func (c *Connector) readPump(ctx context.Context, conn *websocket.Conn) {
+	for {
+		_, message, err := conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
 				log.Printf("connection lost: %v", err)
@@ -90,37 +163,115 @@ func (c *Connector) readPump(ctx context.Context) {
 		if err := json.Unmarshal(message, &msg); err != nil {
 			continue
 		}
+		if msg.Seq > c.lastSeq.Load() {
+			c.lastSeq.Store(msg.Seq)
+		}
 
 		select {
 		case c.Messages <- msg:
 		case <-ctx.Done():
 			return
+		case <-c.stop:
+			return
 		}
 	}
 }
 
-func (c *Connector) Send(msg server.Message) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	if c.conn == nil {
-		return fmt.Errorf("not connected")
+// reconnect dials until it succeeds, flushes queued messages and returns
+// the new connection. It returns nil once the connector is closed.
+func (c *Connector) reconnect(ctx context.Context) *websocket.Conn {
+	for attempt := 0; ; attempt++ {
+		select {
+		case <-time.After(backoff(attempt)):
+		case <-ctx.Done():
+			return nil
+		case <-c.stop:
+			return nil
+		}
+
+		conn, err := c.dial(ctx)
+		if err != nil {
+			log.Printf("reconnect attempt %d: %v", attempt+1, err)
+			continue
+		}
+
+		c.mu.Lock()
+		if c.closed {
+			c.mu.Unlock()
+			conn.Close()
+			return nil
+		}
+		c.conn = conn
+		for len(c.queued) > 0 {
+			if err := conn.WriteMessage(websocket.TextMessage, c.queued[0]); err != nil {
+				break // the read side will notice and reconnect again
+			}
+			c.queued = c.queued[1:]
+		}
+		c.mu.Unlock()
+		return conn
+	}
+}
+
+// backoff returns the delay before reconnect attempt n: exponential from
+// reconnectBaseDelay up to reconnectMaxDelay, with jitter so peers that
+// lost the same server don't all retry at once.
+func backoff(attempt int) time.Duration {
+	d := reconnectMaxDelay
+	if attempt < 16 {
+		d = min(reconnectBaseDelay<<attempt, reconnectMaxDelay)
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+func (c *Connector) setState(s ConnState) {
+	select {
+	case c.States <- s:
+	default:
 	}
+}
+
+// Send writes msg to the server, or queues it while reconnecting.
+func (c *Connector) Send(msg server.Message) error {
 	data, err := json.Marshal(msg)
 	if err != nil {
 		return err
 	}
-	return c.conn.WriteMessage(websocket.TextMessage, data)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return errors.New("connection closed")
+	}
+	if c.conn != nil {
+		if err := c.conn.WriteMessage(websocket.TextMessage, data); err == nil {
+			return nil
+		}
+		// The connection just dropped; keep the message for the reconnect.
+	}
+	if len(c.queued) >= sendBufferSize {
+		return errors.New("not connected and send buffer is full")
+	}
+	c.queued = append(c.queued, data)
+	return nil
 }
 
+// Close shuts the connection down for good.
 func (c *Connector) Close() error {
+	c.stopped.Do(func() { close(c.stop) })
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	c.closed = true
 	if c.conn != nil {
 		return c.conn.Close()
 	}
 	return nil
 }
 
+// LastSeq returns the highest sequence number received from the server.
+func (c *Connector) LastSeq() int64 {
+	return c.lastSeq.Load()
+}
+
 func (c *Connector) Done() <-chan struct{} {
 	return c.done
 }
@@ -1,6 +1,18 @@
 package peersync
 
-import "testing"
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/markx3/agentboard/internal/server"
+)
 
 func TestBuildWSURL(t *testing.T) {
 	tests := []struct {
@@ -59,3 +71,107 @@ func TestBuildWSURL(t *testing.T) {
 		})
 	}
 }
+
+func TestIsAlive(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	addr := ln.Addr().String()
+	if !IsAlive(addr) {
+		t.Errorf("IsAlive(%s) = false for a listening server", addr)
+	}
+	ln.Close()
+	if IsAlive(addr) {
+		t.Errorf("IsAlive(%s) = true after the server closed", addr)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt := 0; attempt < 40; attempt++ {
+		d := backoff(attempt)
+		want := reconnectMaxDelay
+		if attempt < 6 {
+			want = reconnectBaseDelay << attempt
+		}
+		if d < want/2 || d > want {
+			t.Errorf("backoff(%d) = %v, want within [%v, %v]", attempt, d, want/2, want)
+		}
+	}
+}
+
+func TestConnectorReconnects(t *testing.T) {
+	type hello struct {
+		Token   string `json:"token"`
+		LastSeq int64  `json:"last_seq"`
+	}
+	hellos := make(chan hello, 4)
+	conns := make(chan *websocket.Conn, 4)
+	upgrader := websocket.Upgrader{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		var h hello
+		if err := conn.ReadJSON(&h); err != nil {
+			return
+		}
+		hellos <- h
+		conns <- conn
+	}))
+	defer ts.Close()
+
+	c := NewConnector(strings.TrimPrefix(ts.URL, "http://"), "secret")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer c.Close()
+
+	if h := <-hellos; h.Token != "secret" || h.LastSeq != 0 {
+		t.Fatalf("first hello = %+v, want token and no last_seq", h)
+	}
+	first := <-conns
+	first.WriteJSON(server.Message{Type: server.MsgTaskDelete, Seq: 7})
+	<-c.Messages
+
+	// Drop the connection; sends made meanwhile are queued.
+	first.Close()
+	waitState(t, c, StateReconnecting)
+	if err := c.Send(server.Message{Type: server.MsgPing}); err != nil {
+		t.Fatalf("Send while reconnecting: %v", err)
+	}
+
+	select {
+	case h := <-hellos:
+		if h.Token != "secret" || h.LastSeq != 7 {
+			t.Errorf("reconnect hello = %+v, want token and last_seq 7", h)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("connector did not reconnect")
+	}
+	waitState(t, c, StateConnected)
+
+	second := <-conns
+	second.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var queued server.Message
+	if err := second.ReadJSON(&queued); err != nil || queued.Type != server.MsgPing {
+		t.Errorf("got queued message %+v (%v), want %s", queued, err, server.MsgPing)
+	}
+}
+
+func waitState(t *testing.T, c *Connector, want ConnState) {
+	t.Helper()
+	for {
+		select {
+		case s := <-c.States:
+			if s == want {
+				return
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for state %s", want)
+		}
+	}
+}
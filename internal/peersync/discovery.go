@@ -2,19 +2,34 @@ package peersync
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"time"
+
+	"github.com/markx3/agentboard/internal/config"
 )
 
-const serverInfoPath = ".agentboard/server.json"
+// serverInfoPath is where the board's leader advertises itself. It lives
+// with the board, so TUIs started in a subdirectory or a task worktree
+// find the same leader.
+func serverInfoPath() string {
+	return filepath.Join(config.ProjectRoot(), config.Dir, "server.json")
+}
+
+func leaderLockPath() string {
+	return filepath.Join(config.ProjectRoot(), config.Dir, "leader.lock")
+}
 
 type ServerInfo struct {
 	Addr string `json:"addr"`
 }
 
 func WriteServerInfo(addr string) error {
-	dir := filepath.Dir(serverInfoPath)
+	path := serverInfoPath()
+	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return err
 	}
@@ -26,15 +41,15 @@ func WriteServerInfo(addr string) error {
 	}
 
 	// Write atomically via temp file
-	tmp := serverInfoPath + ".tmp"
+	tmp := path + ".tmp"
 	if err := os.WriteFile(tmp, data, 0o600); err != nil {
 		return err
 	}
-	return os.Rename(tmp, serverInfoPath)
+	return os.Rename(tmp, path)
 }
 
 func ReadServerInfo() (*ServerInfo, error) {
-	data, err := os.ReadFile(serverInfoPath)
+	data, err := os.ReadFile(serverInfoPath())
 	if err != nil {
 		return nil, fmt.Errorf("no server info: %w", err)
 	}
@@ -47,5 +62,57 @@ func ReadServerInfo() (*ServerInfo, error) {
 }
 
 func RemoveServerInfo() error {
-	return os.Remove(serverInfoPath)
+	return os.Remove(serverInfoPath())
+}
+
+// ErrLeaderTaken means another process holds the board's leader lock.
+var ErrLeaderTaken = errors.New("another process is the board's leader")
+
+// ClaimLeader takes the board's leader lock, so two processes starting at
+// once can't both run a server on the same database. The lock is released
+// by calling release, or by the OS when the process exits.
+func ClaimLeader() (release func(), err error) {
+	path := leaderLockPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening leader lock: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		if errors.Is(err, ErrLeaderTaken) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("locking leader lock: %w", err)
+	}
+	return func() { f.Close() }, nil
+}
+
+// WaitForLeader polls server.json until it names a live server, for a
+// process that lost the leader lock to one still starting up.
+func WaitForLeader(timeout time.Duration) (*ServerInfo, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		info, err := ReadServerInfo()
+		if err == nil && IsAlive(info.Addr) {
+			return info, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("leader did not come up within %s", timeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// IsAlive reports whether a server accepts connections at addr. A stale
+// server.json left by a crashed leader points at a closed port.
+func IsAlive(addr string) bool {
+	conn, err := net.DialTimeout("tcp", addr, 500*time.Millisecond)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
 }
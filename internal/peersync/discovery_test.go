@@ -0,0 +1,68 @@
+package peersync
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/markx3/agentboard/internal/config"
+)
+
+// inProjectSubdir makes a board under a temp dir and moves into a
+// subdirectory of it for the rest of the test.
+func inProjectSubdir(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, config.Dir), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, config.Dir, config.DBFileName), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(root, "src", "pkg")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	origDir, _ := os.Getwd()
+	os.Chdir(nested)
+	t.Cleanup(func() { os.Chdir(origDir) })
+	return root
+}
+
+func TestServerInfoAtProjectRoot(t *testing.T) {
+	root := inProjectSubdir(t)
+
+	if err := WriteServerInfo("127.0.0.1:4242"); err != nil {
+		t.Fatalf("WriteServerInfo: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, config.Dir, "server.json")); err != nil {
+		t.Errorf("server.json not at the project root: %v", err)
+	}
+	info, err := ReadServerInfo()
+	if err != nil || info.Addr != "127.0.0.1:4242" {
+		t.Errorf("ReadServerInfo = %+v, %v", info, err)
+	}
+	if err := RemoveServerInfo(); err != nil {
+		t.Errorf("RemoveServerInfo: %v", err)
+	}
+}
+
+func TestClaimLeader(t *testing.T) {
+	inProjectSubdir(t)
+
+	release, err := ClaimLeader()
+	if err != nil {
+		t.Fatalf("ClaimLeader: %v", err)
+	}
+	if _, err := ClaimLeader(); !errors.Is(err, ErrLeaderTaken) {
+		t.Errorf("second claim: got %v, want ErrLeaderTaken", err)
+	}
+	release()
+
+	release, err = ClaimLeader()
+	if err != nil {
+		t.Fatalf("claim after release: %v", err)
+	}
+	release()
+}
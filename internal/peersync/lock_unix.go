@@ -0,0 +1,19 @@
+//go:build unix
+
+package peersync
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lockFile takes an exclusive, non-blocking flock on f. It returns
+// ErrLeaderTaken if another process holds it.
+func lockFile(f *os.File) error {
+	err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return ErrLeaderTaken
+	}
+	return err
+}
@@ -0,0 +1,437 @@
+package peersync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/markx3/agentboard/internal/board"
+	"github.com/markx3/agentboard/internal/db"
+	"github.com/markx3/agentboard/internal/server"
+)
+
+// requestTimeout bounds how long a call waits for its reply when the
+// caller's context has no deadline of its own.
+const requestTimeout = 10 * time.Second
+
+// ErrConnectionClosed is returned for calls still waiting when the
+// connection to the server ends.
+var ErrConnectionClosed = errors.New("connection to server closed")
+
+var _ board.Service = (*RemoteService)(nil)
+
+// RemoteService implements board.Service against a sync server. Each call
+// sends one request tagged with a fresh ID and waits for the message that
+// carries it back: a result for queries, the broadcast of the change for
+// writes, or a sync.reject.
+type RemoteService struct {
+	conn *Connector
+
+	mu      sync.Mutex
+	pending map[string]chan server.Message
+	closed  bool
+}
+
+// NewRemoteService wraps a connected Connector. The service takes over the
+// connector's Messages channel.
+func NewRemoteService(c *Connector) *RemoteService {
+	s := &RemoteService{
+		conn:    c,
+		pending: make(map[string]chan server.Message),
+	}
+	go s.dispatch()
+	return s
+}
+
+// DialService connects to the server at addr and returns a service backed
+// by that connection.
+func DialService(ctx context.Context, addr, token string) (*RemoteService, error) {
+	c := NewConnector(addr, token)
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+	return NewRemoteService(c), nil
+}
+
+// Close closes the underlying connection.
+func (s *RemoteService) Close() error {
+	return s.conn.Close()
+}
+
+// dispatch hands each reply to the call waiting for its ID. Everything else
+// (full syncs, other peers' changes, presence) is dropped.
+func (s *RemoteService) dispatch() {
+	for {
+		select {
+		case msg := <-s.conn.Messages:
+			if msg.ID == "" {
+				continue
+			}
+			s.mu.Lock()
+			ch, ok := s.pending[msg.ID]
+			delete(s.pending, msg.ID)
+			s.mu.Unlock()
+			if ok {
+				ch <- msg
+			}
+		case <-s.conn.Done():
+			s.mu.Lock()
+			s.closed = true
+			for id, ch := range s.pending {
+				close(ch)
+				delete(s.pending, id)
+			}
+			s.mu.Unlock()
+			return
+		}
+	}
+}
+
+// call sends a request and decodes the reply payload into out (if non-nil).
+// Rejected moves come back as *board.TransitionError, like local ones.
+func (s *RemoteService) call(ctx context.Context, msgType string, payload, out interface{}) error {
+	msg, err := server.NewMessage(msgType, "", payload)
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", msgType, err)
+	}
+	msg.ID = uuid.NewString()
+
+	ch := make(chan server.Message, 1)
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrConnectionClosed
+	}
+	s.pending[msg.ID] = ch
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, msg.ID)
+		s.mu.Unlock()
+	}()
+
+	if err := s.conn.Send(msg); err != nil {
+		return fmt.Errorf("sending %s: %w", msgType, err)
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+	}
+
+	select {
+	case reply, ok := <-ch:
+		if !ok {
+			return ErrConnectionClosed
+		}
+		if reply.Type == server.MsgSyncReject {
+			var p server.SyncRejectPayload
+			if err := json.Unmarshal(reply.Payload, &p); err != nil {
+				return fmt.Errorf("%s rejected by server", msgType)
+			}
+			if p.Transition != nil {
+				return p.Transition
+			}
+			return errors.New(p.Reason)
+		}
+		if out == nil {
+			return nil
+		}
+		if err := json.Unmarshal(reply.Payload, out); err != nil {
+			return fmt.Errorf("decoding %s reply: %w", msgType, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for %s reply: %w", msgType, ctx.Err())
+	}
+}
+
+func (s *RemoteService) ListTasks(ctx context.Context) ([]db.Task, error) {
+	var tasks []db.Task
+	if err := s.call(ctx, server.MsgTaskList, nil, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (s *RemoteService) ListTasksByStatus(ctx context.Context, status db.TaskStatus) ([]db.Task, error) {
+	tasks, err := s.ListTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var filtered []db.Task
+	for _, t := range tasks {
+		if t.Status == status {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered, nil
+}
+
+func (s *RemoteService) GetTask(ctx context.Context, id string) (*db.Task, error) {
+	var task db.Task
+	if err := s.call(ctx, server.MsgTaskGet, server.TaskGetPayload{TaskID: id}, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (s *RemoteService) CreateTask(ctx context.Context, title, description string) (*db.Task, error) {
+	var task db.Task
+	p := server.TaskCreatePayload{Title: title, Description: description}
+	if err := s.call(ctx, server.MsgTaskCreate, p, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// UpdateTask sends the task's fields and its agent state as two updates.
+// Position is managed by the server and agent activity has its own call,
+// so neither is sent.
+func (s *RemoteService) UpdateTask(ctx context.Context, task *db.Task) error {
+	status := task.Status
+	enrichment := task.EnrichmentStatus
+	priority := task.Priority
+	fields := db.TaskFieldUpdate{
+		Title:               &task.Title,
+		Description:         &task.Description,
+		Status:              &status,
+		Assignee:            &task.Assignee,
+		BranchName:          &task.BranchName,
+		PRUrl:               &task.PRUrl,
+		PRNumber:            &task.PRNumber,
+		EnrichmentStatus:    &enrichment,
+		EnrichmentAgentName: &task.EnrichmentAgentName,
+		Priority:            &priority,
+		DueDate:             &task.DueDate,
+	}
+	if err := s.UpdateTaskFields(ctx, task.ID, fields); err != nil {
+		return err
+	}
+	agentStatus := string(task.AgentStatus)
+	p := server.AgentStatePayload{
+		TaskID:             task.ID,
+		AgentName:          &task.AgentName,
+		AgentStatus:        &agentStatus,
+		AgentStartedAt:     &task.AgentStartedAt,
+		AgentSpawnedStatus: &task.AgentSpawnedStatus,
+		ResetRequested:     &task.ResetRequested,
+		SkipPermissions:    &task.SkipPermissions,
+	}
+	return s.call(ctx, server.MsgAgentState, p, nil)
+}
+
+func (s *RemoteService) UpdateTaskFields(ctx context.Context, id string, fields db.TaskFieldUpdate) error {
+	p := server.TaskUpdatePayload{
+		TaskID:              id,
+		Title:               fields.Title,
+		Description:         fields.Description,
+		Assignee:            fields.Assignee,
+		BranchName:          fields.BranchName,
+		PRUrl:               fields.PRUrl,
+		PRNumber:            fields.PRNumber,
+		EnrichmentAgentName: fields.EnrichmentAgentName,
+		DueDate:             fields.DueDate,
+	}
+	if fields.Status != nil {
+		status := string(*fields.Status)
+		p.Status = &status
+	}
+	if fields.EnrichmentStatus != nil {
+		es := string(*fields.EnrichmentStatus)
+		p.EnrichmentStatus = &es
+	}
+	if fields.Priority != nil {
+		priority := string(*fields.Priority)
+		p.Priority = &priority
+	}
+	return s.call(ctx, server.MsgTaskUpdate, p, nil)
+}
+
+func (s *RemoteService) MoveTask(ctx context.Context, id string, newStatus db.TaskStatus) error {
+	p := server.TaskMovePayload{TaskID: id, ToColumn: string(newStatus)}
+	return s.call(ctx, server.MsgTaskMove, p, nil)
+}
+
+func (s *RemoteService) ReorderTask(ctx context.Context, id, beforeID, afterID string) error {
+	p := server.TaskReorderPayload{TaskID: id, BeforeID: beforeID, AfterID: afterID}
+	return s.call(ctx, server.MsgTaskReorder, p, nil)
+}
+
+func (s *RemoteService) DeleteTask(ctx context.Context, id string) error {
+	return s.call(ctx, server.MsgTaskDelete, server.TaskDeletePayload{TaskID: id}, nil)
+}
+
+// ClaimTask claims the task for the authenticated user; the server ignores
+// assignee.
+func (s *RemoteService) ClaimTask(ctx context.Context, id, assignee string) error {
+	return s.call(ctx, server.MsgTaskClaim, server.TaskClaimPayload{TaskID: id, Assignee: assignee}, nil)
+}
+
+func (s *RemoteService) UnclaimTask(ctx context.Context, id string) error {
+	return s.call(ctx, server.MsgTaskUnclaim, server.TaskUnclaimPayload{TaskID: id}, nil)
+}
+
+func (s *RemoteService) UpdateAgentActivity(ctx context.Context, id, activity string) error {
+	p := server.AgentActivityPayload{TaskID: id, Activity: activity}
+	return s.call(ctx, server.MsgAgentActivity, p, nil)
+}
+
+// Comments
+
+func (s *RemoteService) AddComment(ctx context.Context, taskID, author, body string) (*db.Comment, error) {
+	var c db.Comment
+	p := server.TaskCommentPayload{TaskID: taskID, Author: author, Body: body}
+	if err := s.call(ctx, server.MsgTaskComment, p, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (s *RemoteService) ListComments(ctx context.Context, taskID string) ([]db.Comment, error) {
+	var comments []db.Comment
+	if err := s.call(ctx, server.MsgCommentList, server.CommentListPayload{TaskID: taskID}, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// History
+
+func (s *RemoteService) ListTaskEvents(ctx context.Context, taskID string) ([]db.TaskEvent, error) {
+	var events []db.TaskEvent
+	if err := s.call(ctx, server.MsgHistoryList, server.HistoryListPayload{TaskID: taskID}, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Search
+
+func (s *RemoteService) Search(ctx context.Context, query string, limit int) ([]db.SearchHit, error) {
+	var hits []db.SearchHit
+	if err := s.call(ctx, server.MsgSearch, server.SearchPayload{Query: query, Limit: limit}, &hits); err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+// Labels
+
+func (s *RemoteService) AddLabel(ctx context.Context, taskID, name, color string) error {
+	return s.call(ctx, server.MsgLabelAdd, server.LabelPayload{TaskID: taskID, Name: name, Color: color}, nil)
+}
+
+func (s *RemoteService) RemoveLabel(ctx context.Context, taskID, name string) error {
+	return s.call(ctx, server.MsgLabelRemove, server.LabelPayload{TaskID: taskID, Name: name}, nil)
+}
+
+func (s *RemoteService) ListLabels(ctx context.Context) ([]db.Label, error) {
+	var labels []db.Label
+	if err := s.call(ctx, server.MsgLabelList, nil, &labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// Checklists
+
+// AddChecklistItem returns the new item from the updated task the server
+// broadcasts, where it is the last one.
+func (s *RemoteService) AddChecklistItem(ctx context.Context, taskID, text string) (*db.ChecklistItem, error) {
+	var task db.Task
+	if err := s.call(ctx, server.MsgCheckAdd, server.ChecklistPayload{TaskID: taskID, Text: text}, &task); err != nil {
+		return nil, err
+	}
+	if len(task.Checklist) == 0 {
+		return nil, fmt.Errorf("server did not return the checklist item")
+	}
+	item := task.Checklist[len(task.Checklist)-1]
+	return &item, nil
+}
+
+func (s *RemoteService) SetChecklistItemDone(ctx context.Context, taskID string, itemID int64, done bool) error {
+	return s.call(ctx, server.MsgCheckSet, server.ChecklistPayload{TaskID: taskID, ItemID: itemID, Done: done}, nil)
+}
+
+func (s *RemoteService) RemoveChecklistItem(ctx context.Context, taskID string, itemID int64) error {
+	return s.call(ctx, server.MsgCheckRemove, server.ChecklistPayload{TaskID: taskID, ItemID: itemID}, nil)
+}
+
+// Dependencies
+
+func (s *RemoteService) AddDependency(ctx context.Context, taskID, dependsOn string) error {
+	return s.call(ctx, server.MsgDepAdd, server.DepPayload{TaskID: taskID, DependsOn: dependsOn}, nil)
+}
+
+func (s *RemoteService) RemoveDependency(ctx context.Context, taskID, dependsOn string) error {
+	return s.call(ctx, server.MsgDepRemove, server.DepPayload{TaskID: taskID, DependsOn: dependsOn}, nil)
+}
+
+func (s *RemoteService) ListDependencies(ctx context.Context, taskID string) ([]string, error) {
+	var deps []string
+	if err := s.call(ctx, server.MsgDepList, server.DepListPayload{TaskID: taskID}, &deps); err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
+
+func (s *RemoteService) ListAllDependencies(ctx context.Context) (map[string][]string, error) {
+	var deps map[string][]string
+	if err := s.call(ctx, server.MsgDepList, server.DepListPayload{}, &deps); err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
+
+// Suggestions
+
+func (s *RemoteService) CreateSuggestion(ctx context.Context, taskID string, sugType db.SuggestionType, author, title, message string) (*db.Suggestion, error) {
+	var sug db.Suggestion
+	p := server.SuggestionCreatePayload{
+		TaskID:  taskID,
+		Type:    string(sugType),
+		Author:  author,
+		Title:   title,
+		Message: message,
+	}
+	if err := s.call(ctx, server.MsgSuggestionCreate, p, &sug); err != nil {
+		return nil, err
+	}
+	return &sug, nil
+}
+
+func (s *RemoteService) GetSuggestion(ctx context.Context, id string) (*db.Suggestion, error) {
+	var sug db.Suggestion
+	if err := s.call(ctx, server.MsgSuggestionGet, server.SuggestionGetPayload{ID: id}, &sug); err != nil {
+		return nil, err
+	}
+	return &sug, nil
+}
+
+func (s *RemoteService) ListPendingSuggestions(ctx context.Context) ([]db.Suggestion, error) {
+	return s.ListSuggestions(ctx, db.SuggestionPending)
+}
+
+func (s *RemoteService) ListSuggestions(ctx context.Context, status db.SuggestionStatus) ([]db.Suggestion, error) {
+	var sugs []db.Suggestion
+	p := server.SuggestionListPayload{Status: string(status)}
+	if err := s.call(ctx, server.MsgSuggestionList, p, &sugs); err != nil {
+		return nil, err
+	}
+	return sugs, nil
+}
+
+func (s *RemoteService) AcceptSuggestion(ctx context.Context, id string) error {
+	return s.call(ctx, server.MsgSuggestionAccept, server.SuggestionActionPayload{ID: id}, nil)
+}
+
+func (s *RemoteService) DismissSuggestion(ctx context.Context, id string) error {
+	return s.call(ctx, server.MsgSuggestionDismiss, server.SuggestionActionPayload{ID: id}, nil)
+}
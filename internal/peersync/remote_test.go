@@ -0,0 +1,139 @@
+package peersync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/markx3/agentboard/internal/board"
+	"github.com/markx3/agentboard/internal/db"
+	"github.com/markx3/agentboard/internal/server"
+)
+
+// startFakeServer accepts one client, skips auth and answers each request
+// with whatever reply returns. The reply's ID is set to the request's.
+func startFakeServer(t *testing.T, reply func(server.Message) server.Message) string {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var authMsg struct {
+			Token string `json:"token"`
+		}
+		if err := conn.ReadJSON(&authMsg); err != nil {
+			return
+		}
+		for {
+			var req server.Message
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			// Traffic meant for other requests must not confuse the client.
+			noise, _ := server.NewMessage(server.MsgTaskDelete, "server", server.TaskDeletePayload{TaskID: "other"})
+			noise.ID = "someone-else"
+			conn.WriteJSON(noise)
+
+			resp := reply(req)
+			resp.ID = req.ID
+			if err := conn.WriteJSON(resp); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return strings.TrimPrefix(ts.URL, "http://")
+}
+
+func TestRemoteServiceCorrelatesReplies(t *testing.T) {
+	addr := startFakeServer(t, func(req server.Message) server.Message {
+		switch req.Type {
+		case server.MsgTaskGet:
+			msg, _ := server.NewMessage(server.MsgResult, "server", db.Task{ID: "abc", Title: "Remote task"})
+			return msg
+		case server.MsgTaskMove:
+			msg, _ := server.NewMessage(server.MsgSyncReject, "server", server.SyncRejectPayload{
+				Reason:     "blocked",
+				Transition: &board.TransitionError{TaskID: "abc", To: db.StatusReview, Code: board.TransitionBlocked, Blockers: []string{"def"}},
+			})
+			return msg
+		default:
+			msg, _ := server.NewMessage(server.MsgSyncReject, "server", server.SyncRejectPayload{Reason: "invalid status"})
+			return msg
+		}
+	})
+
+	ctx := context.Background()
+	svc, err := DialService(ctx, addr, "token")
+	if err != nil {
+		t.Fatalf("DialService: %v", err)
+	}
+	defer svc.Close()
+
+	task, err := svc.GetTask(ctx, "abc")
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	if task.Title != "Remote task" {
+		t.Errorf("got title %q, want %q", task.Title, "Remote task")
+	}
+
+	err = svc.DeleteTask(ctx, "abc")
+	if err == nil || err.Error() != "invalid status" {
+		t.Errorf("DeleteTask error = %v, want the server's reject reason", err)
+	}
+
+	var terr *board.TransitionError
+	err = svc.MoveTask(ctx, "abc", db.StatusReview)
+	if !errors.As(err, &terr) || terr.Code != board.TransitionBlocked || len(terr.Blockers) != 1 {
+		t.Errorf("MoveTask error = %#v, want a blocked TransitionError", err)
+	}
+}
+
+func TestRemoteServiceUpdateSendsAllFields(t *testing.T) {
+	got := make(chan server.TaskUpdatePayload, 1)
+	addr := startFakeServer(t, func(req server.Message) server.Message {
+		var p server.TaskUpdatePayload
+		json.Unmarshal(req.Payload, &p)
+		got <- p
+		msg, _ := server.NewMessage(server.MsgTaskUpdate, "server", db.Task{ID: p.TaskID})
+		return msg
+	})
+
+	ctx := context.Background()
+	svc, err := DialService(ctx, addr, "token")
+	if err != nil {
+		t.Fatalf("DialService: %v", err)
+	}
+	defer svc.Close()
+
+	status := db.StatusReview
+	branch := "feature/x"
+	enrich := db.EnrichmentSkipped
+	priority := db.PriorityHigh
+	err = svc.UpdateTaskFields(ctx, "abc", db.TaskFieldUpdate{
+		Status:           &status,
+		BranchName:       &branch,
+		EnrichmentStatus: &enrich,
+		Priority:         &priority,
+	})
+	if err != nil {
+		t.Fatalf("UpdateTaskFields: %v", err)
+	}
+	p := <-got
+	if p.Status == nil || *p.Status != "review" || p.BranchName == nil || *p.BranchName != branch ||
+		p.EnrichmentStatus == nil || *p.EnrichmentStatus != "skipped" ||
+		p.Priority == nil || *p.Priority != "high" || p.Title != nil || p.DueDate != nil {
+		t.Errorf("sent payload %+v, want status, branch, enrichment status and priority only", p)
+	}
+}
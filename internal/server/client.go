@@ -25,6 +25,7 @@ type Client struct {
 	send     chan []byte
 	username string
 	joinedAt time.Time
+	lastSeq  int64 // last sequence number the client saw before connecting
 
 	mu          sync.Mutex
 	msgCount    int
@@ -83,20 +84,21 @@ func (c *Client) readPump(ctx context.Context) {
 			return
 		}
 
+		var msg Message
+		if err := json.Unmarshal(message, &msg); err != nil {
+			continue
+		}
+
 		if c.rateLimited() {
 			payload, _ := safeMarshal(SyncRejectPayload{Reason: "rate limited"})
 			reject, _ := json.Marshal(Message{
 				Type:    MsgSyncReject,
+				ID:      msg.ID,
 				Payload: payload,
 			})
 			c.send <- reject
 			continue
 		}
-
-		var msg Message
-		if err := json.Unmarshal(message, &msg); err != nil {
-			continue
-		}
 		msg.Sender = c.username
 
 		c.hub.incoming <- clientMessage{client: c, message: msg}
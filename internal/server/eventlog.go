@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/markx3/agentboard/internal/db"
+)
+
+// EventLog persists sequenced messages so reconnecting peers can catch up
+// on what they missed instead of reloading the whole board. The hub uses it
+// when its board.Service also implements it.
+type EventLog interface {
+	AppendEvent(ctx context.Context, ev db.Event) error
+	EventsSince(ctx context.Context, seq int64) ([]db.Event, error)
+	LatestEventSeq(ctx context.Context) (int64, error)
+}
+
+// record logs a sequenced message. Unnumbered messages (presence, replies
+// to one peer) are not logged.
+func (h *Hub) record(msg Message) {
+	if h.events == nil || msg.Seq == 0 {
+		return
+	}
+	ev := db.Event{Seq: msg.Seq, Type: msg.Type, Sender: msg.Sender, Payload: msg.Payload}
+	if err := h.events.AppendEvent(context.Background(), ev); err != nil {
+		log.Printf("failed to log event %d: %v", msg.Seq, err)
+	}
+}
+
+// syncClient brings a newly registered client up to date: it replays the
+// events after the client's last seen sequence number when the log still
+// covers them, and sends a full snapshot otherwise.
+func (h *Hub) syncClient(ctx context.Context, client *Client) {
+	events, ok := h.missedEvents(ctx, client.lastSeq)
+	if !ok {
+		h.sendFullSync(ctx, client)
+		return
+	}
+	for _, ev := range events {
+		if ev.Type == MsgPeerJoin || ev.Type == MsgPeerLeave {
+			continue // stale presence notices logged by older versions
+		}
+		data, err := json.Marshal(Message{
+			Type:    ev.Type,
+			Seq:     ev.Seq,
+			Sender:  ev.Sender,
+			Payload: ev.Payload,
+		})
+		if err != nil {
+			log.Printf("failed to marshal replayed event %d: %v", ev.Seq, err)
+			h.sendFullSync(ctx, client)
+			return
+		}
+		client.send <- data
+	}
+}
+
+// missedEvents returns the events after lastSeq, or false if they can't be
+// replayed: the client has no state, the log is gone or trimmed past
+// lastSeq, the client is ahead of this server, or a full sync logged by an
+// older version happened in between.
+func (h *Hub) missedEvents(ctx context.Context, lastSeq int64) ([]db.Event, bool) {
+	current := h.sequencer.Current()
+	if h.events == nil || lastSeq <= 0 || lastSeq > current {
+		return nil, false
+	}
+	if lastSeq == current {
+		return nil, true
+	}
+	events, err := h.events.EventsSince(ctx, lastSeq)
+	if err != nil {
+		log.Printf("failed to read event log: %v", err)
+		return nil, false
+	}
+	if len(events) == 0 || events[0].Seq != lastSeq+1 {
+		return nil, false
+	}
+	for _, ev := range events {
+		if ev.Type == MsgSyncFull {
+			return nil, false
+		}
+	}
+	return events, true
+}
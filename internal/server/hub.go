@@ -2,14 +2,25 @@ package server
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"reflect"
+	"slices"
+	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/markx3/agentboard/internal/board"
 	"github.com/markx3/agentboard/internal/db"
 )
 
+// changePollInterval is how often the hub checks the board for changes made
+// outside the hub (local TUI, CLI, agents) and pushes them to peers.
+const changePollInterval = 2 * time.Second
+
 type clientMessage struct {
 	client  *Client
 	message Message
@@ -22,21 +33,44 @@ type Hub struct {
 	incoming    chan clientMessage
 	sequencer   *Sequencer
 	service     board.Service
+	events      EventLog // nil when the service keeps no event log
 	clientCount atomic.Int32
+	// pollInterval, fingerprint and board drive change detection for edits
+	// that bypass the hub. board holds the tasks as peers last saw them.
+	pollInterval time.Duration
+	fingerprint  [sha256.Size]byte
+	board        map[string]db.Task
 }
 
 func NewHub(svc board.Service) *Hub {
-	return &Hub{
+	h := &Hub{
 		clients:    make(map[*Client]bool),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		incoming:   make(chan clientMessage, 256),
-		sequencer:  NewSequencer(),
-		service:    svc,
+		sequencer:    NewSequencer(),
+		service:      svc,
+		pollInterval: changePollInterval,
+	}
+	if events, ok := svc.(EventLog); ok {
+		h.events = events
 	}
+	return h
 }
 
 func (h *Hub) Run(ctx context.Context) {
+	if h.events != nil {
+		// Keep numbering across restarts so peers' last seen seq stays valid.
+		if seq, err := h.events.LatestEventSeq(ctx); err == nil {
+			h.sequencer.Restore(seq)
+		} else {
+			log.Printf("failed to read event log: %v", err)
+		}
+	}
+	h.rememberBoard(ctx)
+	ticker := time.NewTicker(h.pollInterval)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -51,27 +85,135 @@ func (h *Hub) Run(ctx context.Context) {
 			h.clientCount.Store(int32(len(h.clients)))
 			log.Printf("peer joined: %s (%d total)", client.username, len(h.clients))
 
-			// Send full state to new client
-			h.sendFullSync(ctx, client)
+			// Catch the new client up: replay what it missed or send full state
+			h.syncClient(ctx, client)
 
 			// Notify others
 			h.broadcastExcept(client, MsgPeerJoin, PeerPayload{Username: client.username})
 
 		case client := <-h.unregister:
-			if _, ok := h.clients[client]; ok {
-				delete(h.clients, client)
-				close(client.send)
-				h.clientCount.Store(int32(len(h.clients)))
-				log.Printf("peer left: %s (%d remaining)", client.username, len(h.clients))
-				h.broadcastAll(MsgPeerLeave, PeerPayload{Username: client.username})
-			}
+			h.removeClient(client)
 
 		case cm := <-h.incoming:
 			h.handleMessage(ctx, cm)
+			// The change was already broadcast; don't resend it as a diff.
+			h.rememberBoard(ctx)
+
+		case <-ticker.C:
+			h.syncExternalChanges(ctx)
 		}
 	}
 }
 
+// boardTasks lists all tasks with BlockedBy filled in, the shape peers
+// receive in a full sync.
+func (h *Hub) boardTasks(ctx context.Context) ([]db.Task, error) {
+	tasks, err := h.service.ListTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deps, err := h.service.ListAllDependencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		tasks[i].BlockedBy = deps[tasks[i].ID]
+	}
+	return tasks, nil
+}
+
+// boardFingerprint hashes the board so changes can be detected cheaply.
+func (h *Hub) boardFingerprint(ctx context.Context) ([sha256.Size]byte, []db.Task, error) {
+	tasks, err := h.boardTasks(ctx)
+	if err != nil {
+		return [sha256.Size]byte{}, nil, err
+	}
+	data, err := json.Marshal(tasks)
+	if err != nil {
+		return [sha256.Size]byte{}, nil, err
+	}
+	return sha256.Sum256(data), tasks, nil
+}
+
+// rememberBoard records the board as peers now know it.
+func (h *Hub) rememberBoard(ctx context.Context) {
+	fp, tasks, err := h.boardFingerprint(ctx)
+	if err != nil {
+		return
+	}
+	h.fingerprint = fp
+	h.board = indexTasks(tasks)
+}
+
+func indexTasks(tasks []db.Task) map[string]db.Task {
+	byID := make(map[string]db.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+	return byID
+}
+
+// syncExternalChanges broadcasts what changed on the board without going
+// through the hub as per-task messages. They are numbered and logged like
+// peers' changes, so reconnecting peers can still catch up by replay; this
+// happens even with no peers connected.
+func (h *Hub) syncExternalChanges(ctx context.Context) {
+	fp, tasks, err := h.boardFingerprint(ctx)
+	if err != nil {
+		log.Printf("failed to check board for changes: %v", err)
+		return
+	}
+	if fp == h.fingerprint {
+		return
+	}
+	prev := h.board
+	h.fingerprint = fp
+	h.board = indexTasks(tasks)
+	h.broadcastBoardDiff(prev, tasks)
+}
+
+// broadcastBoardDiff sends task.create, task.update, dep.add, dep.remove
+// and task.delete messages that take peers from prev to tasks. Task
+// payloads leave out BlockedBy; dependencies travel as dep messages.
+func (h *Hub) broadcastBoardDiff(prev map[string]db.Task, tasks []db.Task) {
+	for _, t := range tasks {
+		old, existed := prev[t.ID]
+		next := t
+		next.BlockedBy = nil
+		old.BlockedBy = nil
+		switch {
+		case !existed:
+			h.broadcastAll(MsgTaskCreate, next)
+		case !reflect.DeepEqual(old, next):
+			h.broadcastAll(MsgTaskUpdate, next)
+		}
+	}
+	for _, t := range tasks {
+		before := prev[t.ID].BlockedBy
+		for _, id := range t.BlockedBy {
+			if !slices.Contains(before, id) {
+				h.broadcastAll(MsgDepAdd, DepPayload{TaskID: t.ID, DependsOn: id})
+			}
+		}
+		for _, id := range before {
+			if !slices.Contains(t.BlockedBy, id) {
+				h.broadcastAll(MsgDepRemove, DepPayload{TaskID: t.ID, DependsOn: id})
+			}
+		}
+	}
+	current := indexTasks(tasks)
+	var deleted []string
+	for id := range prev {
+		if _, ok := current[id]; !ok {
+			deleted = append(deleted, id)
+		}
+	}
+	slices.Sort(deleted)
+	for _, id := range deleted {
+		h.broadcastAll(MsgTaskDelete, TaskDeletePayload{TaskID: id})
+	}
+}
+
 // ClientCount returns the current number of connected clients.
 func (h *Hub) ClientCount() int {
 	return int(h.clientCount.Load())
@@ -79,30 +221,33 @@ func (h *Hub) ClientCount() int {
 
 func (h *Hub) handleMessage(ctx context.Context, cm clientMessage) {
 	msg := cm.message
+	// Changes show up in task history under the peer's name.
+	ctx = board.WithActor(ctx, cm.client.username)
 
 	switch msg.Type {
 	case MsgTaskCreate:
 		var p TaskCreatePayload
 		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			h.sendReject(cm.client, msg.ID, "invalid payload")
 			return
 		}
 		if len(p.Title) == 0 || len(p.Title) > 500 {
-			h.sendReject(cm.client, "title must be 1-500 characters")
+			h.sendReject(cm.client, msg.ID, "title must be 1-500 characters")
 			return
 		}
 		if len(p.Description) > 5000 {
-			h.sendReject(cm.client, "description must be under 5000 characters")
+			h.sendReject(cm.client, msg.ID, "description must be under 5000 characters")
 			return
 		}
 		task, err := h.service.CreateTask(ctx, p.Title, p.Description)
 		if err != nil {
-			h.sendReject(cm.client, err.Error())
+			h.sendReject(cm.client, msg.ID, err.Error())
 			return
 		}
 		payload, err := safeMarshal(task)
 		if err != nil {
 			log.Printf("failed to marshal task: %v", err)
-			h.sendReject(cm.client, "internal error")
+			h.sendReject(cm.client, msg.ID, "internal error")
 			return
 		}
 		seq := h.sequencer.Next()
@@ -113,27 +258,58 @@ func (h *Hub) handleMessage(ctx context.Context, cm clientMessage) {
 	case MsgTaskMove:
 		var p TaskMovePayload
 		if err := json.Unmarshal(msg.Payload, &p); err != nil {
-			return
-		}
-		if !db.TaskStatus(p.ToColumn).Valid() {
-			h.sendReject(cm.client, "invalid status")
+			h.sendReject(cm.client, msg.ID, "invalid payload")
 			return
 		}
 		if err := h.service.MoveTask(ctx, p.TaskID, db.TaskStatus(p.ToColumn)); err != nil {
-			h.sendReject(cm.client, err.Error())
+			h.sendError(cm.client, msg.ID, err)
 			return
 		}
 		seq := h.sequencer.Next()
 		msg.Seq = seq
 		h.broadcastAllRaw(msg)
 
+	case MsgTaskReorder:
+		var p TaskReorderPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			h.sendReject(cm.client, msg.ID, "invalid payload")
+			return
+		}
+		if err := h.service.ReorderTask(ctx, p.TaskID, p.BeforeID, p.AfterID); err != nil {
+			h.sendError(cm.client, msg.ID, err)
+			return
+		}
+		task, err := h.service.GetTask(ctx, p.TaskID)
+		if err != nil {
+			log.Printf("failed to get reordered task: %v", err)
+			return
+		}
+		column, err := h.service.ListTasksByStatus(ctx, task.Status)
+		if err != nil {
+			log.Printf("failed to list reordered column: %v", err)
+			return
+		}
+		p.Order = make([]string, len(column))
+		for i, t := range column {
+			p.Order[i] = t.ID
+		}
+		payload, err := safeMarshal(p)
+		if err != nil {
+			log.Printf("failed to marshal reorder: %v", err)
+			return
+		}
+		msg.Seq = h.sequencer.Next()
+		msg.Payload = payload
+		h.broadcastAllRaw(msg)
+
 	case MsgTaskDelete:
 		var p TaskDeletePayload
 		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			h.sendReject(cm.client, msg.ID, "invalid payload")
 			return
 		}
 		if err := h.service.DeleteTask(ctx, p.TaskID); err != nil {
-			h.sendReject(cm.client, err.Error())
+			h.sendReject(cm.client, msg.ID, err.Error())
 			return
 		}
 		seq := h.sequencer.Next()
@@ -143,23 +319,33 @@ func (h *Hub) handleMessage(ctx context.Context, cm clientMessage) {
 	case MsgTaskClaim:
 		var p TaskClaimPayload
 		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			h.sendReject(cm.client, msg.ID, "invalid payload")
 			return
 		}
 		if err := h.service.ClaimTask(ctx, p.TaskID, cm.client.username); err != nil {
-			h.sendReject(cm.client, err.Error())
+			h.sendReject(cm.client, msg.ID, err.Error())
+			return
+		}
+		// Peers apply the assignee from the payload, so it must be the claimer.
+		p.Assignee = cm.client.username
+		payload, err := safeMarshal(p)
+		if err != nil {
+			log.Printf("failed to marshal claim: %v", err)
 			return
 		}
 		seq := h.sequencer.Next()
 		msg.Seq = seq
+		msg.Payload = payload
 		h.broadcastAllRaw(msg)
 
 	case MsgTaskUnclaim:
 		var p TaskUnclaimPayload
 		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			h.sendReject(cm.client, msg.ID, "invalid payload")
 			return
 		}
 		if err := h.service.UnclaimTask(ctx, p.TaskID); err != nil {
-			h.sendReject(cm.client, err.Error())
+			h.sendReject(cm.client, msg.ID, err.Error())
 			return
 		}
 		seq := h.sequencer.Next()
@@ -169,37 +355,151 @@ func (h *Hub) handleMessage(ctx context.Context, cm clientMessage) {
 	case MsgTaskUpdate:
 		var p TaskUpdatePayload
 		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			h.sendReject(cm.client, msg.ID, "invalid payload")
 			return
 		}
 		if p.TaskID == "" {
-			h.sendReject(cm.client, "task_id is required")
+			h.sendReject(cm.client, msg.ID, "task_id is required")
 			return
 		}
-		fields := db.TaskFieldUpdate{
-			Title:       p.Title,
-			Description: p.Description,
+		fields, reason := taskUpdateFields(p)
+		if reason != "" {
+			h.sendReject(cm.client, msg.ID, reason)
+			return
 		}
-		if p.Title != nil && (len(*p.Title) == 0 || len(*p.Title) > 500) {
-			h.sendReject(cm.client, "title must be 1-500 characters")
+		if err := h.service.UpdateTaskFields(ctx, p.TaskID, fields); err != nil {
+			h.sendError(cm.client, msg.ID, err)
 			return
 		}
-		if p.Description != nil && len(*p.Description) > 10000 {
-			h.sendReject(cm.client, "description must be under 10000 characters")
+		h.broadcastTask(ctx, msg, p.TaskID)
+
+	case MsgAgentState:
+		var p AgentStatePayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			h.sendReject(cm.client, msg.ID, "invalid payload")
 			return
 		}
-		if err := h.service.UpdateTaskFields(ctx, p.TaskID, fields); err != nil {
-			h.sendReject(cm.client, err.Error())
+		if p.AgentStatus != nil && !db.AgentStatus(*p.AgentStatus).Valid() {
+			h.sendReject(cm.client, msg.ID, "invalid agent status")
 			return
 		}
-		// Broadcast the updated task
 		task, err := h.service.GetTask(ctx, p.TaskID)
 		if err != nil {
-			log.Printf("failed to get updated task: %v", err)
+			h.sendReject(cm.client, msg.ID, err.Error())
 			return
 		}
-		payload, err := safeMarshal(task)
+		applyAgentState(task, p)
+		if err := h.service.UpdateTask(ctx, task); err != nil {
+			h.sendReject(cm.client, msg.ID, err.Error())
+			return
+		}
+		h.broadcastTask(ctx, msg, p.TaskID)
+
+	case MsgDepAdd, MsgDepRemove:
+		var p DepPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			h.sendReject(cm.client, msg.ID, "invalid payload")
+			return
+		}
+		if p.TaskID == "" || p.DependsOn == "" {
+			h.sendReject(cm.client, msg.ID, "task_id and depends_on are required")
+			return
+		}
+		var err error
+		if msg.Type == MsgDepAdd {
+			err = h.service.AddDependency(ctx, p.TaskID, p.DependsOn)
+		} else {
+			err = h.service.RemoveDependency(ctx, p.TaskID, p.DependsOn)
+		}
 		if err != nil {
-			log.Printf("failed to marshal updated task: %v", err)
+			h.sendReject(cm.client, msg.ID, err.Error())
+			return
+		}
+		seq := h.sequencer.Next()
+		msg.Seq = seq
+		h.broadcastAllRaw(msg)
+
+	case MsgLabelAdd, MsgLabelRemove:
+		var p LabelPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			h.sendReject(cm.client, msg.ID, "invalid payload")
+			return
+		}
+		if p.TaskID == "" || p.Name == "" {
+			h.sendReject(cm.client, msg.ID, "task_id and name are required")
+			return
+		}
+		var err error
+		if msg.Type == MsgLabelAdd {
+			err = h.service.AddLabel(ctx, p.TaskID, p.Name, p.Color)
+		} else {
+			err = h.service.RemoveLabel(ctx, p.TaskID, p.Name)
+		}
+		if err != nil {
+			h.sendReject(cm.client, msg.ID, err.Error())
+			return
+		}
+		h.broadcastTask(ctx, msg, p.TaskID)
+
+	case MsgCheckAdd, MsgCheckSet, MsgCheckRemove:
+		var p ChecklistPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			h.sendReject(cm.client, msg.ID, "invalid payload")
+			return
+		}
+		if p.TaskID == "" {
+			h.sendReject(cm.client, msg.ID, "task_id is required")
+			return
+		}
+		var err error
+		switch msg.Type {
+		case MsgCheckAdd:
+			_, err = h.service.AddChecklistItem(ctx, p.TaskID, p.Text)
+		case MsgCheckSet:
+			err = h.service.SetChecklistItemDone(ctx, p.TaskID, p.ItemID, p.Done)
+		default:
+			err = h.service.RemoveChecklistItem(ctx, p.TaskID, p.ItemID)
+		}
+		if err != nil {
+			h.sendReject(cm.client, msg.ID, err.Error())
+			return
+		}
+		h.broadcastTask(ctx, msg, p.TaskID)
+
+	case MsgSuggestionCreate:
+		var p SuggestionCreatePayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			h.sendReject(cm.client, msg.ID, "invalid payload")
+			return
+		}
+		sugType := db.SuggestionType(p.Type)
+		if !sugType.Valid() {
+			h.sendReject(cm.client, msg.ID, "invalid suggestion type")
+			return
+		}
+		if sugType == db.SuggestionProposal && (len(p.Title) == 0 || len(p.Title) > 500) {
+			h.sendReject(cm.client, msg.ID, "proposal title must be 1-500 characters")
+			return
+		}
+		if sugType != db.SuggestionProposal && p.TaskID == "" {
+			h.sendReject(cm.client, msg.ID, "task_id is required")
+			return
+		}
+		if len(p.Message) > 10000 {
+			h.sendReject(cm.client, msg.ID, "message must be under 10000 characters")
+			return
+		}
+		if p.Author == "" {
+			p.Author = cm.client.username
+		}
+		sug, err := h.service.CreateSuggestion(ctx, p.TaskID, sugType, p.Author, p.Title, p.Message)
+		if err != nil {
+			h.sendReject(cm.client, msg.ID, err.Error())
+			return
+		}
+		payload, err := safeMarshal(sug)
+		if err != nil {
+			log.Printf("failed to marshal suggestion: %v", err)
 			return
 		}
 		seq := h.sequencer.Next()
@@ -207,22 +507,48 @@ func (h *Hub) handleMessage(ctx context.Context, cm clientMessage) {
 		msg.Payload = payload
 		h.broadcastAllRaw(msg)
 
+	case MsgSuggestionAccept, MsgSuggestionDismiss:
+		var p SuggestionActionPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			h.sendReject(cm.client, msg.ID, "invalid payload")
+			return
+		}
+		var err error
+		if msg.Type == MsgSuggestionAccept {
+			err = h.service.AcceptSuggestion(ctx, p.ID)
+		} else {
+			err = h.service.DismissSuggestion(ctx, p.ID)
+		}
+		if err != nil {
+			h.sendReject(cm.client, msg.ID, err.Error())
+			return
+		}
+		seq := h.sequencer.Next()
+		msg.Seq = seq
+		h.broadcastAllRaw(msg)
+		// Accepting a proposal creates a task; push it to peers now.
+		h.syncExternalChanges(ctx)
+
 	case MsgTaskComment:
 		var p TaskCommentPayload
 		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			h.sendReject(cm.client, msg.ID, "invalid payload")
 			return
 		}
+		if p.Author == "" {
+			p.Author = cm.client.username
+		}
 		if p.TaskID == "" || p.Author == "" || p.Body == "" {
-			h.sendReject(cm.client, "task_id, author, and body are required")
+			h.sendReject(cm.client, msg.ID, "task_id, author, and body are required")
 			return
 		}
 		if len(p.Body) > 10000 {
-			h.sendReject(cm.client, "comment body must be under 10000 characters")
+			h.sendReject(cm.client, msg.ID, "comment body must be under 10000 characters")
 			return
 		}
 		comment, err := h.service.AddComment(ctx, p.TaskID, p.Author, p.Body)
 		if err != nil {
-			h.sendReject(cm.client, err.Error())
+			h.sendReject(cm.client, msg.ID, err.Error())
 			return
 		}
 		payload, err := safeMarshal(comment)
@@ -235,14 +561,224 @@ func (h *Hub) handleMessage(ctx context.Context, cm clientMessage) {
 		msg.Payload = payload
 		h.broadcastAllRaw(msg)
 
+	case MsgAgentActivity:
+		var p AgentActivityPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			h.sendReject(cm.client, msg.ID, "invalid payload")
+			return
+		}
+		if len(p.Activity) > 200 {
+			p.Activity = p.Activity[:200]
+		}
+		if err := h.service.UpdateAgentActivity(ctx, p.TaskID, p.Activity); err != nil {
+			h.sendReject(cm.client, msg.ID, err.Error())
+			return
+		}
+		payload, err := safeMarshal(p)
+		if err != nil {
+			log.Printf("failed to marshal activity: %v", err)
+			return
+		}
+		seq := h.sequencer.Next()
+		msg.Seq = seq
+		msg.Payload = payload
+		h.broadcastAllRaw(msg)
+
+	case MsgTaskList, MsgTaskGet, MsgCommentList, MsgHistoryList, MsgSearch, MsgLabelList, MsgDepList, MsgSuggestionList, MsgSuggestionGet:
+		result, err := h.query(ctx, msg)
+		if err != nil {
+			h.sendReject(cm.client, msg.ID, err.Error())
+			return
+		}
+		h.sendResult(cm.client, msg.ID, result)
+
 	case MsgPing:
 		ack, _ := json.Marshal(Message{Type: MsgPong, Sender: "server"})
 		cm.client.send <- ack
+
+	default:
+		if msg.ID != "" {
+			h.sendReject(cm.client, msg.ID, "unsupported message type: "+msg.Type)
+		}
+	}
+}
+
+// taskUpdateFields validates an update the same way the CLI does and
+// converts it to a db.TaskFieldUpdate. A non-empty reason means rejection.
+func taskUpdateFields(p TaskUpdatePayload) (db.TaskFieldUpdate, string) {
+	fields := db.TaskFieldUpdate{
+		Title:               p.Title,
+		Description:         p.Description,
+		Assignee:            p.Assignee,
+		BranchName:          p.BranchName,
+		PRUrl:               p.PRUrl,
+		PRNumber:            p.PRNumber,
+		EnrichmentAgentName: p.EnrichmentAgentName,
+		DueDate:             p.DueDate,
+	}
+	if p.Title != nil && (len(strings.TrimSpace(*p.Title)) == 0 || len(*p.Title) > 500) {
+		return fields, "title must be 1-500 characters"
+	}
+	if p.Description != nil && len(*p.Description) > 10000 {
+		return fields, "description must be under 10000 characters"
+	}
+	// The service checks the status against the board's workflow.
+	if p.Status != nil {
+		status := db.TaskStatus(*p.Status)
+		fields.Status = &status
+	}
+	if p.EnrichmentStatus != nil {
+		es := db.EnrichmentStatus(*p.EnrichmentStatus)
+		if !es.Valid() {
+			return fields, "invalid enrichment status"
+		}
+		fields.EnrichmentStatus = &es
+	}
+	if p.Priority != nil {
+		priority := db.TaskPriority(*p.Priority)
+		if !priority.Valid() {
+			return fields, "invalid priority"
+		}
+		fields.Priority = &priority
+	}
+	if p.DueDate != nil && !db.ValidDueDate(*p.DueDate) {
+		return fields, "due date must be YYYY-MM-DD"
+	}
+	return fields, ""
+}
+
+// applyAgentState copies the set fields of p onto task.
+func applyAgentState(task *db.Task, p AgentStatePayload) {
+	if p.AgentName != nil {
+		task.AgentName = *p.AgentName
+	}
+	if p.AgentStatus != nil {
+		task.AgentStatus = db.AgentStatus(*p.AgentStatus)
+	}
+	if p.AgentStartedAt != nil {
+		task.AgentStartedAt = *p.AgentStartedAt
+	}
+	if p.AgentSpawnedStatus != nil {
+		task.AgentSpawnedStatus = *p.AgentSpawnedStatus
+	}
+	if p.ResetRequested != nil {
+		task.ResetRequested = *p.ResetRequested
+	}
+	if p.SkipPermissions != nil {
+		task.SkipPermissions = *p.SkipPermissions
+	}
+}
+
+// broadcastTask sends msg to all peers with the current state of the task
+// as its payload.
+func (h *Hub) broadcastTask(ctx context.Context, msg Message, taskID string) {
+	task, err := h.service.GetTask(ctx, taskID)
+	if err != nil {
+		log.Printf("failed to get updated task: %v", err)
+		return
+	}
+	payload, err := safeMarshal(task)
+	if err != nil {
+		log.Printf("failed to marshal updated task: %v", err)
+		return
+	}
+	msg.Seq = h.sequencer.Next()
+	msg.Payload = payload
+	h.broadcastAllRaw(msg)
+}
+
+// query answers a read-only request. Queries change nothing, so the result
+// goes to the requester alone.
+func (h *Hub) query(ctx context.Context, msg Message) (interface{}, error) {
+	switch msg.Type {
+	case MsgTaskList:
+		return h.service.ListTasks(ctx)
+
+	case MsgTaskGet:
+		var p TaskGetPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			return nil, errors.New("invalid payload")
+		}
+		return h.service.GetTask(ctx, p.TaskID)
+
+	case MsgCommentList:
+		var p CommentListPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			return nil, errors.New("invalid payload")
+		}
+		return h.service.ListComments(ctx, p.TaskID)
+
+	case MsgHistoryList:
+		var p HistoryListPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			return nil, errors.New("invalid payload")
+		}
+		return h.service.ListTaskEvents(ctx, p.TaskID)
+
+	case MsgSearch:
+		var p SearchPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			return nil, errors.New("invalid payload")
+		}
+		return h.service.Search(ctx, p.Query, p.Limit)
+
+	case MsgLabelList:
+		return h.service.ListLabels(ctx)
+
+	case MsgDepList:
+		var p DepListPayload
+		if len(msg.Payload) > 0 {
+			if err := json.Unmarshal(msg.Payload, &p); err != nil {
+				return nil, errors.New("invalid payload")
+			}
+		}
+		if p.TaskID == "" {
+			return h.service.ListAllDependencies(ctx)
+		}
+		return h.service.ListDependencies(ctx, p.TaskID)
+
+	case MsgSuggestionList:
+		var p SuggestionListPayload
+		if len(msg.Payload) > 0 {
+			if err := json.Unmarshal(msg.Payload, &p); err != nil {
+				return nil, errors.New("invalid payload")
+			}
+		}
+		if p.Status == "" {
+			return h.service.ListPendingSuggestions(ctx)
+		}
+		return h.service.ListSuggestions(ctx, db.SuggestionStatus(p.Status))
+
+	case MsgSuggestionGet:
+		var p SuggestionGetPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			return nil, errors.New("invalid payload")
+		}
+		return h.service.GetSuggestion(ctx, p.ID)
+	}
+	return nil, fmt.Errorf("unsupported query: %s", msg.Type)
+}
+
+// sendResult answers a query from client.
+func (h *Hub) sendResult(client *Client, id string, result interface{}) {
+	msg, err := NewMessage(MsgResult, "server", result)
+	if err != nil {
+		log.Printf("failed to create result message: %v", err)
+		h.sendReject(client, id, "internal error")
+		return
+	}
+	msg.ID = id
+	msg.Seq = h.sequencer.Current()
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("failed to marshal result message: %v", err)
+		return
 	}
+	client.send <- data
 }
 
 func (h *Hub) sendFullSync(ctx context.Context, client *Client) {
-	tasks, err := h.service.ListTasks(ctx)
+	tasks, err := h.boardTasks(ctx)
 	if err != nil {
 		log.Printf("failed to get tasks for sync: %v", err)
 		return
@@ -261,12 +797,28 @@ func (h *Hub) sendFullSync(ctx context.Context, client *Client) {
 	client.send <- data
 }
 
-func (h *Hub) sendReject(client *Client, reason string) {
-	msg, err := NewMessage(MsgSyncReject, "server", SyncRejectPayload{Reason: reason})
+func (h *Hub) sendReject(client *Client, id, reason string) {
+	h.sendRejectPayload(client, id, SyncRejectPayload{Reason: reason})
+}
+
+// sendError rejects a request with a service error, passing transition
+// details along so remote callers can rebuild the board.TransitionError.
+func (h *Hub) sendError(client *Client, id string, err error) {
+	p := SyncRejectPayload{Reason: err.Error()}
+	var terr *board.TransitionError
+	if errors.As(err, &terr) {
+		p.Transition = terr
+	}
+	h.sendRejectPayload(client, id, p)
+}
+
+func (h *Hub) sendRejectPayload(client *Client, id string, p SyncRejectPayload) {
+	msg, err := NewMessage(MsgSyncReject, "server", p)
 	if err != nil {
 		log.Printf("failed to create reject message: %v", err)
 		return
 	}
+	msg.ID = id
 	data, err := json.Marshal(msg)
 	if err != nil {
 		log.Printf("failed to marshal reject message: %v", err)
@@ -275,26 +827,29 @@ func (h *Hub) sendReject(client *Client, reason string) {
 	client.send <- data
 }
 
+// isPresence reports whether msgType announces peers rather than a board
+// change. Presence messages are not numbered or logged: replaying them
+// later would announce peers that have long come and gone.
+func isPresence(msgType string) bool {
+	return msgType == MsgPeerJoin || msgType == MsgPeerLeave
+}
+
 func (h *Hub) broadcastAll(msgType string, payload interface{}) {
 	msg, err := NewMessage(msgType, "server", payload)
 	if err != nil {
 		log.Printf("failed to create broadcast message: %v", err)
 		return
 	}
-	msg.Seq = h.sequencer.Next()
+	if !isPresence(msgType) {
+		msg.Seq = h.sequencer.Next()
+	}
+	h.record(msg)
 	data, err := json.Marshal(msg)
 	if err != nil {
 		log.Printf("failed to marshal broadcast message: %v", err)
 		return
 	}
-	for client := range h.clients {
-		select {
-		case client.send <- data:
-		default:
-			close(client.send)
-			delete(h.clients, client)
-		}
-	}
+	h.deliver(nil, data)
 }
 
 func (h *Hub) broadcastExcept(except *Client, msgType string, payload interface{}) {
@@ -303,12 +858,22 @@ func (h *Hub) broadcastExcept(except *Client, msgType string, payload interface{
 		log.Printf("failed to create broadcast message: %v", err)
 		return
 	}
-	msg.Seq = h.sequencer.Next()
+	if !isPresence(msgType) {
+		msg.Seq = h.sequencer.Next()
+	}
+	h.record(msg)
 	data, err := json.Marshal(msg)
 	if err != nil {
 		log.Printf("failed to marshal broadcast message: %v", err)
 		return
 	}
+	h.deliver(except, data)
+}
+
+// deliver queues data for every client but except. A client whose send
+// buffer is full can't keep up; it is dropped like one that left.
+func (h *Hub) deliver(except *Client, data []byte) {
+	var slow []*Client
 	for client := range h.clients {
 		if client == except {
 			continue
@@ -316,25 +881,34 @@ func (h *Hub) broadcastExcept(except *Client, msgType string, payload interface{
 		select {
 		case client.send <- data:
 		default:
-			close(client.send)
-			delete(h.clients, client)
+			slow = append(slow, client)
 		}
 	}
+	for _, client := range slow {
+		h.removeClient(client)
+	}
+}
+
+// removeClient forgets a client that left or was dropped and tells the
+// others. It is a no-op for a client already removed.
+func (h *Hub) removeClient(client *Client) {
+	if _, ok := h.clients[client]; !ok {
+		return
+	}
+	delete(h.clients, client)
+	close(client.send)
+	h.clientCount.Store(int32(len(h.clients)))
+	log.Printf("peer left: %s (%d remaining)", client.username, len(h.clients))
+	h.broadcastAll(MsgPeerLeave, PeerPayload{Username: client.username})
 }
 
 func (h *Hub) broadcastAllRaw(msg Message) {
+	h.record(msg)
 	data, err := json.Marshal(msg)
 	if err != nil {
 		log.Printf("failed to marshal raw broadcast: %v", err)
 		return
 	}
-	for client := range h.clients {
-		select {
-		case client.send <- data:
-		default:
-			close(client.send)
-			delete(h.clients, client)
-		}
-	}
+	h.deliver(nil, data)
 }
 
@@ -0,0 +1,536 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/markx3/agentboard/internal/board"
+	"github.com/markx3/agentboard/internal/db"
+)
+
+func setupTestHub(t *testing.T) (*Hub, board.Service) {
+	t.Helper()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("opening test db: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	svc := board.NewLocalService(database)
+	h := NewHub(svc)
+	h.pollInterval = 20 * time.Millisecond
+	return h, svc
+}
+
+// nextMessage reads the next message sent to client, failing after timeout.
+func nextMessage(t *testing.T, c *Client, timeout time.Duration) Message {
+	t.Helper()
+	select {
+	case data := <-c.send:
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("decoding message: %v", err)
+		}
+		return msg
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for message")
+	}
+	return Message{}
+}
+
+func TestHubBroadcastsExternalChanges(t *testing.T) {
+	h, svc := setupTestHub(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go h.Run(ctx)
+
+	client := &Client{hub: h, send: make(chan []byte, 16), username: "alice"}
+	h.register <- client
+	if msg := nextMessage(t, client, time.Second); msg.Type != MsgSyncFull {
+		t.Fatalf("first message = %s, want %s", msg.Type, MsgSyncFull)
+	}
+
+	// Changes made directly through the service (as the local TUI or CLI
+	// would) reach connected peers as per-task messages.
+	a, err := svc.CreateTask(ctx, "Made locally", "")
+	if err != nil {
+		t.Fatalf("creating task: %v", err)
+	}
+	msg := nextMessage(t, client, time.Second)
+	var task db.Task
+	json.Unmarshal(msg.Payload, &task)
+	if msg.Type != MsgTaskCreate || msg.Seq == 0 || task.Title != "Made locally" {
+		t.Fatalf("got %s seq %d %+v, want a numbered create of the new task", msg.Type, msg.Seq, task)
+	}
+	seen := msg.Seq
+
+	b, _ := svc.CreateTask(ctx, "Blocker", "")
+	svc.AddDependency(ctx, a.ID, b.ID)
+	a.Title = "Renamed locally"
+	svc.UpdateTask(ctx, a)
+	// The poll may split the changes over two ticks.
+	pending := map[string]bool{MsgTaskCreate: true, MsgTaskUpdate: true, MsgDepAdd: true}
+	for len(pending) > 0 {
+		delete(pending, nextMessage(t, client, time.Second).Type)
+	}
+	svc.DeleteTask(ctx, b.ID)
+	for {
+		msg := nextMessage(t, client, time.Second)
+		if msg.Type == MsgTaskDelete {
+			break
+		}
+	}
+
+	// A peer that saw only the first create catches up by replay, not a
+	// full sync.
+	bob := &Client{hub: h, send: make(chan []byte, 16), username: "bob", lastSeq: seen}
+	h.register <- bob
+	replayed := map[string]bool{}
+	for {
+		msg := nextMessage(t, bob, time.Second)
+		if msg.Type == MsgSyncFull {
+			t.Fatal("got a full sync, want the external changes replayed")
+		}
+		replayed[msg.Type] = true
+		if msg.Type == MsgTaskDelete {
+			break
+		}
+	}
+	for _, typ := range []string{MsgTaskCreate, MsgTaskUpdate, MsgDepAdd} {
+		if !replayed[typ] {
+			t.Errorf("replay is missing %s", typ)
+		}
+	}
+
+	// Presence isn't board state: it is neither numbered nor replayed.
+	if msg := nextMessage(t, client, time.Second); msg.Type != MsgPeerJoin || msg.Seq != 0 {
+		t.Errorf("got %s seq %d, want an unnumbered peer.join", msg.Type, msg.Seq)
+	}
+}
+
+func TestHubClaimBroadcastsClaimer(t *testing.T) {
+	h, svc := setupTestHub(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	task, err := svc.CreateTask(ctx, "Claim me", "")
+	if err != nil {
+		t.Fatalf("creating task: %v", err)
+	}
+	go h.Run(ctx)
+
+	client := &Client{hub: h, send: make(chan []byte, 16), username: "alice"}
+	h.register <- client
+	nextMessage(t, client, time.Second) // sync.full
+
+	msg, _ := NewMessage(MsgTaskClaim, "alice", TaskClaimPayload{TaskID: task.ID})
+	h.incoming <- clientMessage{client: client, message: msg}
+
+	got := nextMessage(t, client, time.Second)
+	if got.Type != MsgTaskClaim {
+		t.Fatalf("got %s, want %s", got.Type, MsgTaskClaim)
+	}
+	var p TaskClaimPayload
+	json.Unmarshal(got.Payload, &p)
+	if p.Assignee != "alice" {
+		t.Errorf("broadcast assignee = %q, want alice", p.Assignee)
+	}
+
+	// The claim was broadcast directly; no redundant full sync follows.
+	select {
+	case data := <-client.send:
+		t.Errorf("unexpected follow-up message: %s", data)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestHubDropsSlowClients(t *testing.T) {
+	h, svc := setupTestHub(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go h.Run(ctx)
+
+	alice := &Client{hub: h, send: make(chan []byte, 16), username: "alice"}
+	h.register <- alice
+	nextMessage(t, alice, time.Second) // sync.full
+
+	// bob's buffer is full once his sync.full is queued.
+	bob := &Client{hub: h, send: make(chan []byte, 1), username: "bob"}
+	h.register <- bob
+	if got := nextMessage(t, alice, time.Second); got.Type != MsgPeerJoin {
+		t.Fatalf("got %s, want %s", got.Type, MsgPeerJoin)
+	}
+	if n := h.ClientCount(); n != 2 {
+		t.Fatalf("ClientCount = %d, want 2", n)
+	}
+
+	if _, err := svc.CreateTask(ctx, "Made elsewhere", ""); err != nil {
+		t.Fatalf("creating task: %v", err)
+	}
+	if got := nextMessage(t, alice, time.Second); got.Type != MsgTaskCreate {
+		t.Fatalf("got %s, want %s", got.Type, MsgTaskCreate)
+	}
+	if got := nextMessage(t, alice, time.Second); got.Type != MsgPeerLeave {
+		t.Fatalf("got %s, want %s", got.Type, MsgPeerLeave)
+	}
+	if n := h.ClientCount(); n != 1 {
+		t.Errorf("ClientCount = %d after dropping bob, want 1", n)
+	}
+	<-bob.send // sync.full
+	if _, ok := <-bob.send; ok {
+		t.Error("bob's send channel is still open")
+	}
+}
+
+func TestHubAnswersQueriesToRequesterOnly(t *testing.T) {
+	h, svc := setupTestHub(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	task, err := svc.CreateTask(ctx, "Find me", "")
+	if err != nil {
+		t.Fatalf("creating task: %v", err)
+	}
+	go h.Run(ctx)
+
+	alice := &Client{hub: h, send: make(chan []byte, 16), username: "alice"}
+	bob := &Client{hub: h, send: make(chan []byte, 16), username: "bob"}
+	h.register <- alice
+	nextMessage(t, alice, time.Second) // sync.full
+	h.register <- bob
+	nextMessage(t, bob, time.Second)   // sync.full
+	nextMessage(t, alice, time.Second) // peer.join
+
+	msg, _ := NewMessage(MsgTaskGet, "alice", TaskGetPayload{TaskID: task.ID})
+	msg.ID = "req-1"
+	h.incoming <- clientMessage{client: alice, message: msg}
+
+	got := nextMessage(t, alice, time.Second)
+	if got.Type != MsgResult || got.ID != "req-1" {
+		t.Fatalf("got %s %q, want %s req-1", got.Type, got.ID, MsgResult)
+	}
+	var result db.Task
+	if err := json.Unmarshal(got.Payload, &result); err != nil {
+		t.Fatalf("decoding result: %v", err)
+	}
+	if result.ID != task.ID {
+		t.Errorf("result task = %s, want %s", result.ID, task.ID)
+	}
+
+	select {
+	case data := <-bob.send:
+		t.Errorf("query result leaked to another peer: %s", data)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestHubRejectCarriesRequestID(t *testing.T) {
+	h, _ := setupTestHub(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go h.Run(ctx)
+
+	client := &Client{hub: h, send: make(chan []byte, 16), username: "alice"}
+	h.register <- client
+	nextMessage(t, client, time.Second) // sync.full
+
+	for _, msgType := range []string{MsgTaskMove, "task.frobnicate"} {
+		msg, _ := NewMessage(msgType, "alice", TaskMovePayload{TaskID: "x", ToColumn: "nowhere"})
+		msg.ID = "req-" + msgType
+		h.incoming <- clientMessage{client: client, message: msg}
+
+		got := nextMessage(t, client, time.Second)
+		if got.Type != MsgSyncReject || got.ID != msg.ID {
+			t.Errorf("%s: got %s %q, want %s %q", msgType, got.Type, got.ID, MsgSyncReject, msg.ID)
+		}
+	}
+}
+
+func TestHubReplaysMissedEvents(t *testing.T) {
+	h, svc := setupTestHub(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go h.Run(ctx)
+
+	alice := &Client{hub: h, send: make(chan []byte, 16), username: "alice"}
+	h.register <- alice
+	nextMessage(t, alice, time.Second) // sync.full
+
+	var seqs []int64
+	for _, title := range []string{"First", "Second"} {
+		msg, _ := NewMessage(MsgTaskCreate, "alice", TaskCreatePayload{Title: title})
+		h.incoming <- clientMessage{client: alice, message: msg}
+		seqs = append(seqs, nextMessage(t, alice, time.Second).Seq)
+	}
+
+	// A peer that saw the first create gets only the second one.
+	bob := &Client{hub: h, send: make(chan []byte, 16), username: "bob", lastSeq: seqs[0]}
+	h.register <- bob
+	got := nextMessage(t, bob, time.Second)
+	if got.Type != MsgTaskCreate || got.Seq != seqs[1] {
+		t.Fatalf("got %s seq %d, want replayed %s seq %d", got.Type, got.Seq, MsgTaskCreate, seqs[1])
+	}
+	var task db.Task
+	json.Unmarshal(got.Payload, &task)
+	if task.Title != "Second" {
+		t.Errorf("replayed task %q, want Second", task.Title)
+	}
+
+	// A peer from an unknown future gets a full snapshot.
+	carol := &Client{hub: h, send: make(chan []byte, 16), username: "carol", lastSeq: 1000}
+	h.register <- carol
+	if got := nextMessage(t, carol, time.Second); got.Type != MsgSyncFull {
+		t.Errorf("got %s, want %s", got.Type, MsgSyncFull)
+	}
+
+	// A restarted hub keeps numbering after the logged events.
+	cancel()
+	h2 := NewHub(svc)
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+	go h2.Run(ctx2)
+	dave := &Client{hub: h2, send: make(chan []byte, 16), username: "dave", lastSeq: seqs[1]}
+	h2.register <- dave
+	msg, _ := NewMessage(MsgTaskCreate, "dave", TaskCreatePayload{Title: "Third"})
+	h2.incoming <- clientMessage{client: dave, message: msg}
+	got = nextMessage(t, dave, time.Second)
+	if got.Type != MsgTaskCreate || got.Seq <= seqs[1] {
+		t.Errorf("got %s seq %d, want a create numbered after %d", got.Type, got.Seq, seqs[1])
+	}
+}
+
+func TestHubDependenciesAndUpdates(t *testing.T) {
+	h, svc := setupTestHub(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a, _ := svc.CreateTask(ctx, "A", "")
+	b, _ := svc.CreateTask(ctx, "B", "")
+	go h.Run(ctx)
+
+	client := &Client{hub: h, send: make(chan []byte, 16), username: "alice"}
+	h.register <- client
+	nextMessage(t, client, time.Second) // sync.full
+
+	send := func(msgType string, payload interface{}) Message {
+		msg, _ := NewMessage(msgType, "alice", payload)
+		msg.ID = msgType
+		h.incoming <- clientMessage{client: client, message: msg}
+		return nextMessage(t, client, time.Second)
+	}
+
+	if got := send(MsgDepAdd, DepPayload{TaskID: a.ID, DependsOn: b.ID}); got.Type != MsgDepAdd {
+		t.Fatalf("dep.add: got %s, want broadcast", got.Type)
+	}
+	deps, _ := svc.ListDependencies(ctx, a.ID)
+	if len(deps) != 1 || deps[0] != b.ID {
+		t.Errorf("dependencies = %v, want [%s]", deps, b.ID)
+	}
+
+	bad := "bogus"
+	if got := send(MsgTaskUpdate, TaskUpdatePayload{TaskID: a.ID, EnrichmentStatus: &bad}); got.Type != MsgSyncReject {
+		t.Errorf("invalid enrichment status: got %s, want %s", got.Type, MsgSyncReject)
+	}
+
+	// a is blocked by b, which isn't done.
+	status, branch := "review", "feature/a"
+	got := send(MsgTaskUpdate, TaskUpdatePayload{TaskID: a.ID, Status: &status})
+	var reject SyncRejectPayload
+	json.Unmarshal(got.Payload, &reject)
+	if got.Type != MsgSyncReject || reject.Transition == nil || reject.Transition.Code != board.TransitionBlocked {
+		t.Errorf("blocked update: got %s %+v, want a blocked transition reject", got.Type, reject)
+	}
+
+	got = send(MsgTaskUpdate, TaskUpdatePayload{TaskID: b.ID, Status: &status, BranchName: &branch})
+	var task db.Task
+	json.Unmarshal(got.Payload, &task)
+	if got.Type != MsgTaskUpdate || task.Status != db.StatusReview || task.BranchName != branch {
+		t.Errorf("update broadcast %s %+v, want task in review on %s", got.Type, task, branch)
+	}
+
+	active := "active"
+	got = send(MsgAgentState, AgentStatePayload{TaskID: b.ID, AgentStatus: &active})
+	json.Unmarshal(got.Payload, &task)
+	if got.Type != MsgAgentState || task.AgentStatus != db.AgentActive {
+		t.Errorf("agent.state broadcast %s %+v, want active agent", got.Type, task)
+	}
+}
+
+func TestHubAcceptProposalSyncsNewTask(t *testing.T) {
+	h, svc := setupTestHub(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go h.Run(ctx)
+
+	client := &Client{hub: h, send: make(chan []byte, 16), username: "alice"}
+	h.register <- client
+	nextMessage(t, client, time.Second) // sync.full
+
+	msg, _ := NewMessage(MsgSuggestionCreate, "alice", SuggestionCreatePayload{
+		Type: string(db.SuggestionProposal), Title: "Proposed", Message: "why",
+	})
+	h.incoming <- clientMessage{client: client, message: msg}
+	got := nextMessage(t, client, time.Second)
+	var sug db.Suggestion
+	json.Unmarshal(got.Payload, &sug)
+	if got.Type != MsgSuggestionCreate || sug.Author != "alice" {
+		t.Fatalf("got %s %+v, want suggestion by alice", got.Type, sug)
+	}
+
+	msg, _ = NewMessage(MsgSuggestionAccept, "alice", SuggestionActionPayload{ID: sug.ID})
+	h.incoming <- clientMessage{client: client, message: msg}
+	if got := nextMessage(t, client, time.Second); got.Type != MsgSuggestionAccept {
+		t.Fatalf("got %s, want %s", got.Type, MsgSuggestionAccept)
+	}
+	got = nextMessage(t, client, time.Second)
+	var task db.Task
+	json.Unmarshal(got.Payload, &task)
+	if got.Type != MsgTaskCreate || task.Title != "Proposed" {
+		t.Errorf("got %s %+v, want the accepted task created", got.Type, task)
+	}
+	if tasks, _ := svc.ListTasks(ctx); len(tasks) != 1 {
+		t.Errorf("got %d tasks, want 1", len(tasks))
+	}
+}
+
+func TestHubRecordsPeerInHistory(t *testing.T) {
+	h, svc := setupTestHub(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	task, err := svc.CreateTask(ctx, "Watch me", "")
+	if err != nil {
+		t.Fatalf("creating task: %v", err)
+	}
+	go h.Run(ctx)
+
+	client := &Client{hub: h, send: make(chan []byte, 16), username: "alice"}
+	h.register <- client
+	nextMessage(t, client, time.Second) // sync.full
+
+	msg, _ := NewMessage(MsgTaskMove, "alice", TaskMovePayload{TaskID: task.ID, ToColumn: "planning"})
+	h.incoming <- clientMessage{client: client, message: msg}
+	nextMessage(t, client, time.Second) // task.move
+
+	msg, _ = NewMessage(MsgHistoryList, "alice", HistoryListPayload{TaskID: task.ID})
+	msg.ID = "req-1"
+	h.incoming <- clientMessage{client: client, message: msg}
+
+	got := nextMessage(t, client, time.Second)
+	if got.Type != MsgResult || got.ID != "req-1" {
+		t.Fatalf("got %s %q, want %s req-1", got.Type, got.ID, MsgResult)
+	}
+	var events []db.TaskEvent
+	if err := json.Unmarshal(got.Payload, &events); err != nil {
+		t.Fatalf("decoding result: %v", err)
+	}
+	if len(events) == 0 {
+		t.Fatal("got no history")
+	}
+	last := events[len(events)-1]
+	if last.Actor != "alice" || last.Field != "status" || last.NewValue != "planning" {
+		t.Errorf("last event = %+v, want alice moving the task to planning", last)
+	}
+}
+
+func TestHubLabelBroadcastsTask(t *testing.T) {
+	h, svc := setupTestHub(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	task, err := svc.CreateTask(ctx, "Tag me", "")
+	if err != nil {
+		t.Fatalf("creating task: %v", err)
+	}
+	go h.Run(ctx)
+
+	client := &Client{hub: h, send: make(chan []byte, 16), username: "alice"}
+	h.register <- client
+	nextMessage(t, client, time.Second) // sync.full
+
+	msg, _ := NewMessage(MsgLabelAdd, "alice", LabelPayload{TaskID: task.ID, Name: "bug", Color: "#ff5555"})
+	msg.ID = "req-1"
+	h.incoming <- clientMessage{client: client, message: msg}
+
+	got := nextMessage(t, client, time.Second)
+	if got.Type != MsgLabelAdd || got.ID != "req-1" {
+		t.Fatalf("got %s %q, want %s req-1", got.Type, got.ID, MsgLabelAdd)
+	}
+	var updated db.Task
+	if err := json.Unmarshal(got.Payload, &updated); err != nil {
+		t.Fatalf("decoding task: %v", err)
+	}
+	if len(updated.Labels) != 1 || updated.Labels[0] != (db.Label{Name: "bug", Color: "#ff5555"}) {
+		t.Errorf("broadcast labels = %+v, want bug #ff5555", updated.Labels)
+	}
+}
+
+func TestHubChecklistBroadcastsTask(t *testing.T) {
+	h, svc := setupTestHub(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	task, err := svc.CreateTask(ctx, "Check me", "")
+	if err != nil {
+		t.Fatalf("creating task: %v", err)
+	}
+	go h.Run(ctx)
+
+	client := &Client{hub: h, send: make(chan []byte, 16), username: "alice"}
+	h.register <- client
+	nextMessage(t, client, time.Second) // sync.full
+
+	send := func(msgType string, p ChecklistPayload) db.Task {
+		t.Helper()
+		msg, _ := NewMessage(msgType, "alice", p)
+		h.incoming <- clientMessage{client: client, message: msg}
+		got := nextMessage(t, client, time.Second)
+		if got.Type != msgType {
+			t.Fatalf("got %s, want %s", got.Type, msgType)
+		}
+		var updated db.Task
+		if err := json.Unmarshal(got.Payload, &updated); err != nil {
+			t.Fatalf("decoding task: %v", err)
+		}
+		return updated
+	}
+
+	updated := send(MsgCheckAdd, ChecklistPayload{TaskID: task.ID, Text: "Tests pass"})
+	if len(updated.Checklist) != 1 || updated.Checklist[0].Text != "Tests pass" {
+		t.Fatalf("broadcast checklist = %+v, want one item", updated.Checklist)
+	}
+	updated = send(MsgCheckSet, ChecklistPayload{TaskID: task.ID, ItemID: updated.Checklist[0].ID, Done: true})
+	if !updated.Checklist[0].Done {
+		t.Errorf("item not ticked: %+v", updated.Checklist[0])
+	}
+}
+
+func TestHubReorderBroadcastsColumnOrder(t *testing.T) {
+	h, svc := setupTestHub(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	first, _ := svc.CreateTask(ctx, "First", "")
+	second, _ := svc.CreateTask(ctx, "Second", "")
+	go h.Run(ctx)
+
+	client := &Client{hub: h, send: make(chan []byte, 16), username: "alice"}
+	h.register <- client
+	nextMessage(t, client, time.Second) // sync.full
+
+	msg, _ := NewMessage(MsgTaskReorder, "alice", TaskReorderPayload{TaskID: second.ID, BeforeID: first.ID})
+	h.incoming <- clientMessage{client: client, message: msg}
+	got := nextMessage(t, client, time.Second)
+	if got.Type != MsgTaskReorder {
+		t.Fatalf("got %s, want %s", got.Type, MsgTaskReorder)
+	}
+	var p TaskReorderPayload
+	if err := json.Unmarshal(got.Payload, &p); err != nil {
+		t.Fatalf("decoding payload: %v", err)
+	}
+	if len(p.Order) != 2 || p.Order[0] != second.ID || p.Order[1] != first.ID {
+		t.Errorf("order = %v, want second then first", p.Order)
+	}
+
+	msg, _ = NewMessage(MsgTaskReorder, "alice", TaskReorderPayload{TaskID: second.ID, BeforeID: second.ID})
+	msg.ID = "bad-reorder"
+	h.incoming <- clientMessage{client: client, message: msg}
+	if got := nextMessage(t, client, time.Second); got.Type != MsgSyncReject || got.ID != "bad-reorder" {
+		t.Errorf("got %s (%s), want a reject of the invalid reorder", got.Type, got.ID)
+	}
+}
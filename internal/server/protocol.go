@@ -1,10 +1,20 @@
 package server
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"github.com/markx3/agentboard/internal/board"
+)
 
 // Message is the wire protocol envelope for all WebSocket communication.
+//
+// ID is an optional client-chosen request ID. The server copies it onto the
+// reply to that request: the result of a query, the broadcast of a change,
+// or a sync.reject. Clients should use globally unique IDs since broadcasts
+// reach every peer.
 type Message struct {
 	Type    string          `json:"type"`
+	ID      string          `json:"id,omitempty"`
 	Seq     int64           `json:"seq"`
 	Sender  string          `json:"sender"`
 	Payload json.RawMessage `json:"payload,omitempty"`
@@ -12,19 +22,47 @@ type Message struct {
 
 // Message types
 const (
-	MsgSyncFull    = "sync.full"
-	MsgSyncReject  = "sync.reject"
-	MsgTaskCreate  = "task.create"
-	MsgTaskMove    = "task.move"
-	MsgTaskDelete  = "task.delete"
-	MsgTaskClaim   = "task.claim"
-	MsgTaskUnclaim = "task.unclaim"
-	MsgTaskUpdate  = "task.update"
-	MsgTaskComment = "task.comment"
-	MsgPeerJoin    = "peer.join"
-	MsgPeerLeave   = "peer.leave"
-	MsgPing        = "ping"
-	MsgPong        = "pong"
+	MsgSyncFull          = "sync.full"
+	MsgSyncReject        = "sync.reject"
+	MsgTaskCreate        = "task.create"
+	MsgTaskMove          = "task.move"
+	MsgTaskReorder       = "task.reorder"
+	MsgTaskDelete        = "task.delete"
+	MsgTaskClaim         = "task.claim"
+	MsgTaskUnclaim       = "task.unclaim"
+	MsgTaskUpdate        = "task.update"
+	MsgTaskComment       = "task.comment"
+	MsgAgentActivity     = "agent.activity"
+	MsgAgentState        = "agent.state"
+	MsgDepAdd            = "dep.add"
+	MsgDepRemove         = "dep.remove"
+	MsgLabelAdd          = "label.add"
+	MsgLabelRemove       = "label.remove"
+	MsgCheckAdd          = "check.add"
+	MsgCheckSet          = "check.set"
+	MsgCheckRemove       = "check.remove"
+	MsgSuggestionCreate  = "suggestion.create"
+	MsgSuggestionAccept  = "suggestion.accept"
+	MsgSuggestionDismiss = "suggestion.dismiss"
+	MsgPeerJoin          = "peer.join"
+	MsgPeerLeave         = "peer.leave"
+	MsgPing              = "ping"
+	MsgPong              = "pong"
+)
+
+// Query message types. The server answers each one with a MsgResult sent
+// only to the requester.
+const (
+	MsgTaskList       = "task.list"
+	MsgTaskGet        = "task.get"
+	MsgCommentList    = "comment.list"
+	MsgHistoryList    = "history.list"
+	MsgSearch         = "search"
+	MsgLabelList      = "label.list"
+	MsgDepList        = "dep.list"
+	MsgSuggestionList = "suggestion.list"
+	MsgSuggestionGet  = "suggestion.get"
+	MsgResult         = "result"
 )
 
 // Payload types for typed access
@@ -40,6 +78,16 @@ type TaskMovePayload struct {
 	ToColumn   string `json:"to_column"`
 }
 
+// TaskReorderPayload places a task before BeforeID or, when that is empty,
+// after AfterID. The server fills in Order, the IDs of the column's tasks
+// in their new order, before broadcasting it.
+type TaskReorderPayload struct {
+	TaskID   string   `json:"task_id"`
+	BeforeID string   `json:"before_id,omitempty"`
+	AfterID  string   `json:"after_id,omitempty"`
+	Order    []string `json:"order,omitempty"`
+}
+
 type TaskDeletePayload struct {
 	TaskID string `json:"task_id"`
 }
@@ -53,10 +101,21 @@ type TaskUnclaimPayload struct {
 	TaskID string `json:"task_id"`
 }
 
+// TaskUpdatePayload mirrors db.TaskFieldUpdate: only set fields change.
+// A status change moves the task to the end of the new column.
 type TaskUpdatePayload struct {
-	TaskID      string  `json:"task_id"`
-	Title       *string `json:"title,omitempty"`
-	Description *string `json:"description,omitempty"`
+	TaskID              string  `json:"task_id"`
+	Title               *string `json:"title,omitempty"`
+	Description         *string `json:"description,omitempty"`
+	Status              *string `json:"status,omitempty"`
+	Assignee            *string `json:"assignee,omitempty"`
+	BranchName          *string `json:"branch_name,omitempty"`
+	PRUrl               *string `json:"pr_url,omitempty"`
+	PRNumber            *int    `json:"pr_number,omitempty"`
+	EnrichmentStatus    *string `json:"enrichment_status,omitempty"`
+	EnrichmentAgentName *string `json:"enrichment_agent_name,omitempty"`
+	Priority            *string `json:"priority,omitempty"`
+	DueDate             *string `json:"due_date,omitempty"`
 }
 
 type TaskCommentPayload struct {
@@ -65,12 +124,101 @@ type TaskCommentPayload struct {
 	Body   string `json:"body"`
 }
 
+type AgentActivityPayload struct {
+	TaskID   string `json:"task_id"`
+	Activity string `json:"activity"`
+}
+
+// AgentStatePayload updates the agent bookkeeping on a task; only set
+// fields change. The server broadcasts the updated task.
+type AgentStatePayload struct {
+	TaskID             string  `json:"task_id"`
+	AgentName          *string `json:"agent_name,omitempty"`
+	AgentStatus        *string `json:"agent_status,omitempty"`
+	AgentStartedAt     *string `json:"agent_started_at,omitempty"`
+	AgentSpawnedStatus *string `json:"agent_spawned_status,omitempty"`
+	ResetRequested     *bool   `json:"reset_requested,omitempty"`
+	SkipPermissions    *bool   `json:"skip_permissions,omitempty"`
+}
+
+type DepPayload struct {
+	TaskID    string `json:"task_id"`
+	DependsOn string `json:"depends_on"`
+}
+
+// LabelPayload adds a label to a task or removes it. Both are broadcast
+// with the updated task as their payload.
+type LabelPayload struct {
+	TaskID string `json:"task_id"`
+	Name   string `json:"name"`
+	Color  string `json:"color,omitempty"`
+}
+
+// ChecklistPayload adds an item to a task's checklist (Text), ticks or
+// unticks one (ItemID, Done) or removes one (ItemID). All are broadcast
+// with the updated task as their payload.
+type ChecklistPayload struct {
+	TaskID string `json:"task_id"`
+	ItemID int64  `json:"item_id,omitempty"`
+	Text   string `json:"text,omitempty"`
+	Done   bool   `json:"done,omitempty"`
+}
+
+type SuggestionCreatePayload struct {
+	TaskID  string `json:"task_id,omitempty"`
+	Type    string `json:"type"`
+	Author  string `json:"author"`
+	Title   string `json:"title"`
+	Message string `json:"message"`
+}
+
+// SuggestionActionPayload accepts or dismisses a suggestion.
+type SuggestionActionPayload struct {
+	ID string `json:"id"`
+}
+
+type TaskGetPayload struct {
+	TaskID string `json:"task_id"`
+}
+
+type CommentListPayload struct {
+	TaskID string `json:"task_id"`
+}
+
+type HistoryListPayload struct {
+	TaskID string `json:"task_id"`
+}
+
+// SearchPayload runs a full-text search. A Limit of 0 returns every
+// matching task.
+type SearchPayload struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit,omitempty"`
+}
+
+// DepListPayload asks for one task's dependencies, or for the whole
+// dependency map when TaskID is empty.
+type DepListPayload struct {
+	TaskID string `json:"task_id,omitempty"`
+}
+
+// SuggestionListPayload filters suggestions by status; empty means pending.
+type SuggestionListPayload struct {
+	Status string `json:"status,omitempty"`
+}
+
+type SuggestionGetPayload struct {
+	ID string `json:"id"`
+}
+
 type PeerPayload struct {
 	Username string `json:"username"`
 }
 
 type SyncRejectPayload struct {
 	Reason string `json:"reason"`
+	// Transition is set when a move broke a workflow rule.
+	Transition *board.TransitionError `json:"transition,omitempty"`
 }
 
 func NewMessage(msgType string, sender string, payload interface{}) (Message, error) {
@@ -19,3 +19,8 @@ func (s *Sequencer) Next() int64 {
 func (s *Sequencer) Current() int64 {
 	return s.seq.Load()
 }
+
+// Restore continues numbering after seq, e.g. the last persisted event.
+func (s *Sequencer) Restore(seq int64) {
+	s.seq.Store(seq)
+}
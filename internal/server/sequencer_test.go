@@ -66,3 +66,11 @@ func TestSequencerCurrent(t *testing.T) {
 		t.Errorf("after 3 Next(): got %d, want 3", seq.Current())
 	}
 }
+
+func TestSequencerRestore(t *testing.T) {
+	seq := server.NewSequencer()
+	seq.Restore(41)
+	if got := seq.Next(); got != 42 {
+		t.Errorf("got %d after Restore(41), want 42", got)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -17,6 +18,8 @@ import (
 type Server struct {
 	hub          *Hub
 	addr         string
+	auth         auth.Authenticator
+	mu           sync.Mutex // guards listener
 	listener     net.Listener
 	tunnelActive bool
 }
@@ -27,7 +30,7 @@ func newUpgrader(tunnelActive bool) websocket.Upgrader {
 		WriteBufferSize: 1024,
 		CheckOrigin: func(r *http.Request) bool {
 			if tunnelActive {
-				return true // GitHub auth is the real gate
+				return true // token auth is the real gate
 			}
 			origin := r.Header.Get("Origin")
 			if origin == "" {
@@ -49,11 +52,20 @@ func New(svc board.Service, host string, port int) *Server {
 	return &Server{
 		hub:  hub,
 		addr: addr,
+		auth: auth.GitHub{},
 	}
 }
 
+// SetAuthenticator replaces the default GitHub authentication. Call it
+// before Start.
+func (s *Server) SetAuthenticator(a auth.Authenticator) {
+	s.auth = a
+}
+
 // SetListener sets an external listener (e.g. ngrok) for the server to use.
 func (s *Server) SetListener(ln net.Listener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.listener = ln
 	s.tunnelActive = true
 }
@@ -73,15 +85,19 @@ func (s *Server) Start(ctx context.Context) error {
 	})
 
 	// Use pre-set listener (e.g. ngrok) or create a local one
+	s.mu.Lock()
 	if s.listener == nil {
-		var err error
-		s.listener, err = net.Listen("tcp", s.addr)
+		ln, err := net.Listen("tcp", s.addr)
 		if err != nil {
+			s.mu.Unlock()
 			return fmt.Errorf("listening on %s: %w", s.addr, err)
 		}
+		s.listener = ln
 	}
+	ln := s.listener
+	s.mu.Unlock()
 
-	log.Printf("WebSocket server listening on %s", s.listener.Addr().String())
+	log.Printf("WebSocket server listening on %s", ln.Addr().String())
 
 	srv := &http.Server{Handler: mux}
 	go func() {
@@ -89,19 +105,30 @@ func (s *Server) Start(ctx context.Context) error {
 		srv.Close()
 	}()
 
-	if err := srv.Serve(s.listener); err != http.ErrServerClosed {
+	if err := srv.Serve(ln); err != http.ErrServerClosed {
 		return err
 	}
 	return nil
 }
 
 func (s *Server) Addr() string {
-	if s.listener != nil {
-		return s.listener.Addr().String()
+	if addr := s.ListenAddr(); addr != "" {
+		return addr
 	}
 	return s.addr
 }
 
+// ListenAddr returns the address the server is listening on, or "" until
+// Start has opened its listener.
+func (s *Server) ListenAddr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
 func (s *Server) handleWS(ctx context.Context, w http.ResponseWriter, r *http.Request, upgrader websocket.Upgrader) {
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
@@ -109,9 +136,11 @@ func (s *Server) handleWS(ctx context.Context, w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	// First message should be auth token
+	// First message should be auth token, plus the last sequence number seen
+	// if the client is reconnecting.
 	var authMsg struct {
-		Token string `json:"token"`
+		Token   string `json:"token"`
+		LastSeq int64  `json:"last_seq"`
 	}
 	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
 	if err := conn.ReadJSON(&authMsg); err != nil {
@@ -121,7 +150,7 @@ func (s *Server) handleWS(ctx context.Context, w http.ResponseWriter, r *http.Re
 	}
 	conn.SetReadDeadline(time.Time{}) // clear deadline
 
-	username, err := auth.VerifyTokenString(ctx, authMsg.Token)
+	username, err := s.auth.Authenticate(ctx, authMsg.Token)
 	if err != nil {
 		log.Printf("auth failed: %v", err)
 		conn.WriteJSON(map[string]string{"error": "authentication failed"})
@@ -130,6 +159,7 @@ func (s *Server) handleWS(ctx context.Context, w http.ResponseWriter, r *http.Re
 	}
 
 	client := newClient(s.hub, conn, username)
+	client.lastSeq = authMsg.LastSeq
 	s.hub.register <- client
 
 	go client.writePump(ctx)
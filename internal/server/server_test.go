@@ -1,8 +1,17 @@
 package server
 
 import (
+	"context"
 	"net/http"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/markx3/agentboard/internal/auth"
+	"github.com/markx3/agentboard/internal/board"
+	"github.com/markx3/agentboard/internal/db"
 )
 
 func TestNewUpgraderOriginCheck(t *testing.T) {
@@ -53,3 +62,51 @@ func TestHubClientCount(t *testing.T) {
 		t.Errorf("ClientCount() = %d, want 5", got)
 	}
 }
+
+func TestServerHandshakeWithSharedSecret(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("opening test db: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	srv := New(board.NewLocalService(database), "127.0.0.1", 0)
+	srv.SetAuthenticator(auth.SharedSecret{Secret: "s3cret"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go srv.Start(ctx)
+
+	var addr string
+	for i := 0; i < 50 && addr == ""; i++ {
+		time.Sleep(10 * time.Millisecond)
+		addr = srv.ListenAddr()
+	}
+	if addr == "" {
+		t.Fatal("server did not start")
+	}
+
+	dial := func(token string) map[string]interface{} {
+		t.Helper()
+		conn, _, err := websocket.DefaultDialer.Dial("ws://"+addr+"/ws", nil)
+		if err != nil {
+			t.Fatalf("dialing: %v", err)
+		}
+		defer conn.Close()
+		if err := conn.WriteJSON(map[string]string{"token": token}); err != nil {
+			t.Fatalf("sending auth: %v", err)
+		}
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		var reply map[string]interface{}
+		if err := conn.ReadJSON(&reply); err != nil {
+			t.Fatalf("reading reply: %v", err)
+		}
+		return reply
+	}
+
+	if reply := dial("alice:s3cret"); reply["type"] != MsgSyncFull {
+		t.Errorf("valid secret: got %v, want a %s", reply, MsgSyncFull)
+	}
+	if reply := dial("alice:wrong"); reply["error"] != "authentication failed" {
+		t.Errorf("wrong secret: got %v, want an authentication error", reply)
+	}
+}
@@ -24,12 +24,17 @@ type agentSelectedMsg struct {
 	runner agent.AgentRunner
 }
 
-func newAgentPicker(runners []agent.AgentRunner, task db.Task, w, h int) agentPicker {
-	// Pre-select the task's previous agent if available
+func newAgentPicker(runners []agent.AgentRunner, task db.Task, preferred string, w, h int) agentPicker {
+	// Pre-select the task's previous agent if available, otherwise the
+	// configured preferred agent.
+	want := task.AgentName
+	if want == "" {
+		want = preferred
+	}
 	selected := 0
-	if task.AgentName != "" {
+	if want != "" {
 		for i, r := range runners {
-			if r.ID() == task.AgentName {
+			if r.ID() == want {
 				selected = i
 				break
 			}
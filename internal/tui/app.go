@@ -2,6 +2,7 @@ package tui
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
@@ -13,14 +14,16 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/markx3/agentboard/internal/agent"
 	"github.com/markx3/agentboard/internal/board"
+	"github.com/markx3/agentboard/internal/config"
 	"github.com/markx3/agentboard/internal/db"
+	"github.com/markx3/agentboard/internal/peersync"
+	"github.com/markx3/agentboard/internal/server"
 	"github.com/markx3/agentboard/internal/tmux"
 )
 
-const (
-	agentPollInterval = 2500 * time.Millisecond
-	gracePeriod       = 5 * time.Second
-)
+// localUser is the name used for claims and comments made from a local board,
+// matching the CLI default.
+const localUser = "local"
 
 type overlayType int
 
@@ -66,6 +69,8 @@ type App struct {
 	height       int
 	ready        bool
 	mode         boardMode // Agent mode (default) vs Detail mode
+	// cfg holds project settings from .agentboard/config.toml.
+	cfg *config.Config
 	// pendingSpawnTask holds a task awaiting skip-permissions confirmation.
 	pendingSpawnTask *db.Task
 	// availableRunners is cached at startup for agent detection.
@@ -82,16 +87,30 @@ type App struct {
 	tunnelURL    string
 	peerCount    int
 	serverActive bool
+	// Remote mode: when connector is set, tasks come from remote (fed by the
+	// sync server) and edits are sent to the server instead of the local DB.
+	connector *peersync.Connector
+	remote    *remoteStore
+	connState peersync.ConnState
+	// feed is set instead of connector on a follower: a TUI sharing the
+	// leader's database, which only listens for when to reload.
+	feed *peersync.Connector
+	// peerCounter reports connected peers when this TUI is the sync leader.
+	peerCounter func() int
 	// Enrichment tracking (from HEAD)
 	enrichmentSeen    map[string]db.EnrichmentStatus // task ID -> last known status
 	enrichmentActive  int                            // current enrichment count
-	enrichmentMaxConc int                            // max concurrent (default 3)
+	enrichmentMaxConc int                            // max concurrent (agent.max_concurrent_enrichments)
 	// Agent state transition tracking (from main)
 	prevAgentStates map[string]db.AgentStatus
 	// Search state (from main)
 	searching   bool
 	searchInput textinput.Model
 	searchQuery string
+	// searchHits holds the tasks the full-text index matched for
+	// searchHitsFor, the free text of a search query.
+	searchHits    map[string]bool
+	searchHitsFor string
 	// Suggestions
 	pendingSuggestions []db.Suggestion
 	lastPendingCount   int
@@ -109,14 +128,52 @@ func WithConnectAddr(addr string) AppOption {
 	}
 }
 
+// WithConfig applies project settings. Without it, config.Default() is used.
+func WithConfig(cfg *config.Config) AppOption {
+	return func(a *App) {
+		a.cfg = cfg
+		a.enrichmentMaxConc = cfg.Agent.MaxConcurrentEnrichments
+	}
+}
+
+// WithLeader marks this TUI as the sync leader listening on addr. peers is
+// polled on every tick to show the connected peer count.
+func WithLeader(addr string, peers func() int) AppOption {
+	return func(a *App) {
+		a.tunnelURL = addr
+		a.serverActive = true
+		a.peerCounter = peers
+	}
+}
+
+// WithConnector switches the TUI to remote mode: the board renders the
+// server's state and edits are sent through the connector.
+func WithConnector(c *peersync.Connector) AppOption {
+	return func(a *App) {
+		a.connector = c
+		a.remote = newRemoteStore()
+	}
+}
+
+// WithFollower makes this TUI a follower of the leader at addr on the same
+// machine. It keeps working on the shared database and agents; the leader's
+// broadcasts only tell it when the board changed.
+func WithFollower(addr string, c *peersync.Connector) AppOption {
+	return func(a *App) {
+		a.tunnelURL = addr
+		a.serverActive = true
+		a.feed = c
+	}
+}
+
 func NewApp(svc board.Service, opts ...AppOption) App {
 	si := textinput.New()
 	si.Prompt = "/ "
-	si.Placeholder = "search tasks..."
+	si.Placeholder = "search tasks and comments... (label:name to filter by label)"
 	si.CharLimit = 100
 	a := App{
-		board:             newKanban(),
 		service:           svc,
+		cfg:               config.Default(),
 		form:              newTaskForm(),
 		availableRunners:  agent.AvailableRunners(),
 		pendingRecons:     make(map[string]pendingRecon),
@@ -128,14 +185,25 @@ func NewApp(svc board.Service, opts ...AppOption) App {
 	for _, opt := range opts {
 		opt(&a)
 	}
+	a.board = newKanban(a.cfg.BoardWorkflow())
 	return a
 }
 
 func (a App) Init() tea.Cmd {
-	return tea.Batch(a.loadTasks(), a.scheduleAgentTick())
+	cmds := []tea.Cmd{a.loadTasks(), a.scheduleAgentTick()}
+	if a.connector != nil || a.feed != nil {
+		cmds = append(cmds, a.listen())
+	}
+	return tea.Batch(cmds...)
 }
 
 func (a App) loadTasks() tea.Cmd {
+	if a.remote != nil {
+		store := a.remote
+		return func() tea.Msg {
+			return tasksLoadedMsg{tasks: store.snapshot(), deps: store.deps()}
+		}
+	}
 	return func() tea.Msg {
 		ctx := context.Background()
 		tasks, err := a.service.ListTasks(ctx)
@@ -160,7 +228,7 @@ func (a App) loadTasks() tea.Cmd {
 
 // scheduleAgentTick returns a Cmd that fires after the poll interval.
 func (a App) scheduleAgentTick() tea.Cmd {
-	return tea.Tick(agentPollInterval, func(time.Time) tea.Msg {
+	return tea.Tick(a.cfg.TUI.PollInterval, func(time.Time) tea.Msg {
 		return agentTickMsg{}
 	})
 }
@@ -190,6 +258,9 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.board.SelectTaskByID(a.cursorFollow.taskID)
 			a.cursorFollow = nil
 		}
+		if a.remote != nil {
+			return a, nil
+		}
 		// Startup reconciliation: check for stale active states
 		a.reconcileStaleOnStartup()
 		// Check for agent state transitions -> notifications
@@ -204,14 +275,15 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.form.Reset()
 		return a, tea.Batch(
 			a.loadTasks(),
-			a.notify(fmt.Sprintf("Created: %s", msg.task.Title)),
+			a.notifyDone(fmt.Sprintf("Created: %s", msg.task.Title)),
 		)
 
 	case taskMovedMsg:
 		a.cursorFollow = &pendingFocus{taskID: msg.taskID, newStatus: msg.newStatus}
+		a.reloadHistory()
 		cmds := []tea.Cmd{
 			a.loadTasks(),
-			a.notify(fmt.Sprintf("Moved to %s", msg.newStatus)),
+			a.notifyDone(fmt.Sprintf("Moved to %s", msg.newStatus)),
 		}
 		// Auto-respawn agent if it was active (new column -> new workflow)
 		if msg.hadAgent {
@@ -219,10 +291,14 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return a, tea.Batch(cmds...)
 
+	case taskReorderedMsg:
+		a.cursorFollow = &pendingFocus{taskID: msg.taskID, newStatus: msg.status}
+		return a, a.loadTasks()
+
 	case taskDeletedMsg:
 		return a, tea.Batch(
 			a.loadTasks(),
-			a.notify("Task deleted"),
+			a.notifyDone("Task deleted"),
 		)
 
 	case taskSaveRequestedMsg:
@@ -245,18 +321,31 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case taskSavedMsg:
 		// Refresh the detail view with saved data
 		a.detail.task = msg.task
+		a.reloadHistory()
 		return a, tea.Batch(
 			a.loadTasks(),
-			a.notify("Task saved"),
+			a.notifyDone("Task saved"),
 		)
 
 	case agentTickMsg:
+		if a.remote != nil {
+			// Agents, enrichment and proposals live with the server's board.
+			return a, a.scheduleAgentTick()
+		}
 		// Cache tmux.ListWindows() once per tick (shared across reconciliation functions)
 		windows, _ := tmux.ListWindows()
 		cmds := a.reconcileAgentsWithWindows(windows)
 		cmds = append(cmds, a.reconcileEnrichments(windows)...)
-		cmds = append(cmds, a.checkForEnrichableNewTasks()...)
+		if a.feed == nil {
+			// Queued agents and enrichments are started by the leader only,
+			// so two TUIs on one board don't both start them.
+			cmds = append(cmds, a.checkForEnrichableNewTasks()...)
+			cmds = append(cmds, a.startQueuedAgents())
+		}
 		cmds = append(cmds, a.scheduleAgentTick(), a.loadTasks(), a.loadSuggestions())
+		if a.peerCounter != nil {
+			cmds = append(cmds, a.leaderStatus())
+		}
 		return a, tea.Batch(cmds...)
 
 	case suggestionsLoadedMsg:
@@ -301,9 +390,105 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.notify("Agent killed"),
 		)
 
+	case agentQueuedMsg:
+		text := fmt.Sprintf("All %d agent slots busy — agent queued", a.cfg.Agent.MaxConcurrentAgents)
+		if !msg.queued {
+			text = "Agent removed from queue"
+		}
+		return a, tea.Batch(a.loadTasks(), a.notify(text))
+
+	case queuedAgentsStartedMsg:
+		text := fmt.Sprintf("Started queued agent: %s", msg.tasks[0].Title)
+		if len(msg.tasks) > 1 {
+			text = fmt.Sprintf("Started %d queued agents", len(msg.tasks))
+		}
+		return a, tea.Batch(a.loadTasks(), a.notify(text))
+
 	case agentViewDoneMsg:
 		return a, a.loadTasks()
 
+	case searchResultsMsg:
+		a.applySearchResults(msg)
+		return a, nil
+
+	case remoteMsg:
+		if a.feed != nil {
+			return a, a.followChange(msg.msg)
+		}
+		if msg.msg.Type == server.MsgResult && strings.HasPrefix(msg.msg.ID, historyQueryPrefix) {
+			if a.overlay == overlayDetail && msg.msg.ID == historyQueryPrefix+a.detail.task.ID {
+				var events []db.TaskEvent
+				if err := json.Unmarshal(msg.msg.Payload, &events); err == nil {
+					a.detail.history = events
+				}
+			}
+			return a, a.listen()
+		}
+		if msg.msg.Type == server.MsgResult && strings.HasPrefix(msg.msg.ID, commentQueryPrefix) {
+			var comments []db.Comment
+			if err := json.Unmarshal(msg.msg.Payload, &comments); err == nil {
+				a.remote.setComments(strings.TrimPrefix(msg.msg.ID, commentQueryPrefix), comments)
+				a.refreshRemoteDetail()
+			}
+			return a, a.listen()
+		}
+		if msg.msg.Type == server.MsgResult && strings.HasPrefix(msg.msg.ID, searchQueryPrefix) {
+			var hits []db.SearchHit
+			if err := json.Unmarshal(msg.msg.Payload, &hits); err == nil {
+				a.applySearchResults(searchResultsMsg{
+					query: strings.TrimPrefix(msg.msg.ID, searchQueryPrefix),
+					ids:   searchHitIDs(hits),
+				})
+			}
+			return a, a.listen()
+		}
+		cmds := []tea.Cmd{a.listen(), a.loadTasks()}
+		notice, err := a.remote.apply(msg.msg)
+		if err != nil {
+			cmds = append(cmds, a.notify(fmt.Sprintf("Error: %s", err)))
+		} else if notice != "" {
+			cmds = append(cmds, a.notify(notice))
+		}
+		a.refreshRemoteDetail()
+		if a.overlay == overlayDetail && changesTasks(msg.msg.Type) {
+			cmds = append(cmds, a.requestRemoteHistory(a.detail.task.ID))
+		}
+		return a, tea.Batch(cmds...)
+
+	case remoteStateMsg:
+		a.connState = msg.state
+		a.serverActive = msg.state == peersync.StateConnected
+		cmds := []tea.Cmd{a.listen()}
+		switch msg.state {
+		case peersync.StateReconnecting:
+			cmds = append(cmds, a.notify("Connection lost, reconnecting..."))
+		case peersync.StateConnected:
+			cmds = append(cmds, a.notify("Reconnected to server"))
+		}
+		return a, tea.Batch(cmds...)
+
+	case remoteClosedMsg:
+		a.connState = peersync.StateClosed
+		a.serverActive = false
+		return a, a.notify("Disconnected from server")
+
+	case commentRequestedMsg:
+		return a, a.addComment(msg.taskID, msg.body)
+
+	case commentAddedMsg:
+		if a.remote == nil && a.overlay == overlayDetail && a.detail.task.ID == msg.taskID {
+			a.detail.comments, _ = a.service.ListComments(context.Background(), msg.taskID)
+			a.reloadHistory()
+		}
+		return a, a.notifyDone("Comment added")
+
+	case taskClaimedMsg:
+		text := "Task unclaimed"
+		if msg.claimed {
+			text = "Task claimed"
+		}
+		return a, tea.Batch(a.loadTasks(), a.notifyDone(text))
+
 	case serverStatusMsg:
 		a.tunnelURL = msg.tunnelURL
 		a.peerCount = msg.peerCount
@@ -395,7 +580,7 @@ func (a *App) reconcileAgentsWithWindows(windows map[string]bool) []tea.Cmd {
 			continue
 		}
 
-		if time.Since(pending.detectedAt) < gracePeriod {
+		if time.Since(pending.detectedAt) < a.cfg.TUI.GracePeriod {
 			// Still in grace period -- wait
 			continue
 		}
@@ -476,6 +661,10 @@ func (a App) updateOverlay(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if a.overlay == overlayConfirm {
 		return a.updateConfirm(msg)
 	}
+	// The comment input uses esc to cancel, not to close the detail view
+	if a.overlay == overlayDetail && a.detail.commenting {
+		return a.updateDetail(msg)
+	}
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -612,19 +801,33 @@ func (a App) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case msg.String() == "e":
 			a.detail.enterEditMode()
 			return a, nil
+		case key.Matches(msg, keys.Claim):
+			a.detail.startComment()
+			return a, nil
 		case key.Matches(msg, keys.MoveRight):
 			return a, a.moveTask(a.detail.task.ID, a.nextStatus(a.detail.task.Status))
 		case key.Matches(msg, keys.MoveLeft):
 			return a, a.moveTask(a.detail.task.ID, a.prevStatus(a.detail.task.Status))
+		case key.Matches(msg, keys.SpawnAgent) && a.remote != nil,
+			key.Matches(msg, keys.KillAgent) && a.remote != nil,
+			key.Matches(msg, keys.ViewAgent) && a.remote != nil,
+			key.Matches(msg, keys.ToggleEnrich) && a.remote != nil:
+			return a, a.notify(remoteAgentsUnsupported)
 		case key.Matches(msg, keys.SpawnAgent):
 			if a.detail.task.AgentStatus == db.AgentActive {
 				return a, a.notify("Agent already running")
 			}
+			if a.detail.task.AgentQueued {
+				return a, a.notify("Agent already queued")
+			}
 			t := a.detail.task
 			a.pendingSpawnTask = &t
 			a.overlay = overlayConfirm
 			return a, nil
 		case key.Matches(msg, keys.KillAgent):
+			if a.detail.task.AgentQueued {
+				return a, a.unqueueAgent(a.detail.task)
+			}
 			if a.detail.task.AgentStatus != db.AgentActive {
 				return a, a.notify("No agent running")
 			}
@@ -661,6 +864,7 @@ func (a App) updateBoard(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if a.searchQuery != "" {
 				a.searchQuery = ""
 				a.searchInput.SetValue("")
+				a.searchHits, a.searchHitsFor = nil, ""
 				a.board.LoadTasks(a.lastTasks, a.lastDeps)
 				return a, nil
 			}
@@ -686,14 +890,22 @@ func (a App) updateBoard(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, nil
 		case key.Matches(msg, keys.Enter):
 			if task := a.board.SelectedTask(); task != nil {
-				if a.mode == modeAgent && task.AgentStatus == db.AgentActive {
+				if a.remote == nil && a.mode == modeAgent && task.AgentStatus == db.AgentActive {
 					return a, a.viewAgent(*task)
 				}
-				a.detail = newTaskDetail(*task, a.service)
-				a.detail.SetSize(a.width, a.height)
-				a.overlay = overlayDetail
+				return a, a.openDetail(*task)
 			}
 			return a, nil
+		case key.Matches(msg, keys.Claim):
+			if task := a.board.SelectedTask(); task != nil {
+				return a, a.toggleClaim(*task)
+			}
+			return a, nil
+		case key.Matches(msg, keys.SpawnAgent) && a.remote != nil,
+			key.Matches(msg, keys.KillAgent) && a.remote != nil,
+			key.Matches(msg, keys.ViewAgent) && a.remote != nil,
+			key.Matches(msg, keys.ToggleEnrich) && a.remote != nil:
+			return a, a.notify(remoteAgentsUnsupported)
 		case key.Matches(msg, keys.MoveRight):
 			if task := a.board.SelectedTask(); task != nil {
 				return a, a.moveTask(task.ID, a.board.NextColumn())
@@ -704,11 +916,24 @@ func (a App) updateBoard(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return a, a.moveTask(task.ID, a.board.PrevColumn())
 			}
 			return a, nil
+		case key.Matches(msg, keys.MoveUp):
+			if task, prev := a.board.SelectedTask(), a.board.Neighbor(-1); task != nil && prev != nil {
+				return a, a.reorderTask(*task, prev.ID, "")
+			}
+			return a, nil
+		case key.Matches(msg, keys.MoveDown):
+			if task, next := a.board.SelectedTask(), a.board.Neighbor(1); task != nil && next != nil {
+				return a, a.reorderTask(*task, "", next.ID)
+			}
+			return a, nil
 		case key.Matches(msg, keys.SpawnAgent):
 			if task := a.board.SelectedTask(); task != nil {
 				if task.AgentStatus == db.AgentActive {
 					return a, a.notify("Agent already running")
 				}
+				if task.AgentQueued {
+					return a, a.notify("Agent already queued")
+				}
 				t := *task
 				a.pendingSpawnTask = &t
 				a.overlay = overlayConfirm
@@ -717,6 +942,9 @@ func (a App) updateBoard(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, nil
 		case key.Matches(msg, keys.KillAgent):
 			if task := a.board.SelectedTask(); task != nil {
+				if task.AgentQueued {
+					return a, a.unqueueAgent(*task)
+				}
 				if task.AgentStatus != db.AgentActive {
 					return a, a.notify("No agent running")
 				}
@@ -771,6 +999,7 @@ func (a App) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.searching = false
 			a.searchQuery = ""
 			a.searchInput.SetValue("")
+			a.searchHits, a.searchHitsFor = nil, ""
 			a.searchInput.Blur()
 			// Reload with all tasks
 			a.board.LoadTasks(a.lastTasks, a.lastDeps)
@@ -786,10 +1015,11 @@ func (a App) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	var cmd tea.Cmd
 	a.searchInput, cmd = a.searchInput.Update(msg)
-	// Live filter as user types
+	// Live filter as user types; full-text matches are added when they
+	// arrive.
 	a.searchQuery = a.searchInput.Value()
 	a.board.LoadTasks(a.filteredTasks(a.lastTasks), a.lastDeps)
-	return a, cmd
+	return a, tea.Batch(cmd, a.searchIndex())
 }
 
 func (a App) View() string {
@@ -809,6 +1039,9 @@ func (a App) View() string {
 	if a.tunnelURL != "" {
 		serverStatus := serverStatusBar(a.tunnelURL, a.peerCount, a.serverActive, a.width)
 		statusBar = statusBar + serverStatus
+		if (a.connector != nil || a.feed != nil) && a.connState != peersync.StateConnected {
+			statusBar += "  " + tunnelDisconnectedStyle.Render(a.connState.String())
+		}
 	}
 
 	if a.notification != nil {
@@ -827,7 +1060,7 @@ func (a App) View() string {
 		if a.searchQuery != "" {
 			filterHint = fmt.Sprintf("  [filter: %s] esc:clear", a.searchQuery)
 		}
-		help = helpStyle.Render(fmt.Sprintf(" %s  h/l:columns  j/k:tasks  tab:mode  o:new  m/M:move  a:agent  v:view  E:enrich  enter:open  s:proposals  /:search  ?:help  q:quit%s", modeStr, filterHint))
+		help = helpStyle.Render(fmt.Sprintf(" %s  h/l:columns  j/k:tasks  tab:mode  o:new  m/M:move  c:claim  a:agent  v:view  E:enrich  enter:open  s:proposals  /:search  ?:help  q:quit%s", modeStr, filterHint))
 	}
 
 	mainView := lipgloss.JoinVertical(lipgloss.Left, summaryBar, boardView, statusBar, help)
@@ -874,9 +1107,11 @@ Actions:
   o         Create new task
   m         Move task right
   M         Move task left
+  K / J     Move task up / down in its column
   enter     Open task detail (or view agent in Agent mode)
   e         Edit task (in detail view)
   x         Delete task
+  c         Claim/unclaim task (add comment in detail view)
   a         Spawn agent (select if multiple available)
   v         View agent (split pane, Ctrl+q to close)
   A         Kill running agent
@@ -920,18 +1155,112 @@ func (a App) filteredTasks(tasks []db.Task) []db.Task {
 	if a.searchQuery == "" {
 		return tasks
 	}
-	q := strings.ToLower(a.searchQuery)
+	labels, q := parseSearch(a.searchQuery)
 	var filtered []db.Task
 	for _, t := range tasks {
-		if strings.Contains(strings.ToLower(t.Title), q) ||
-			strings.Contains(strings.ToLower(t.Description), q) ||
-			strings.Contains(strings.ToLower(t.Assignee), q) {
+		indexed := q != "" && q == a.searchHitsFor && a.searchHits[t.ID]
+		if hasAllLabels(t, labels) && (indexed || matchesText(t, q)) {
 			filtered = append(filtered, t)
 		}
 	}
 	return filtered
 }
 
+// searchIndex asks the full-text index, which also covers comments, for
+// the tasks matching the free text of the search query. Locally the answer
+// is a searchResultsMsg; a remote board answers with a tagged result.
+func (a App) searchIndex() tea.Cmd {
+	_, text := parseSearch(a.searchQuery)
+	if text == "" || text == a.searchHitsFor {
+		return nil
+	}
+	if a.connector != nil {
+		return func() tea.Msg {
+			msg, err := server.NewMessage(server.MsgSearch, "", server.SearchPayload{Query: text})
+			if err != nil {
+				return errMsg{err}
+			}
+			msg.ID = searchQueryPrefix + text
+			if err := a.connector.Send(msg); err != nil {
+				return errMsg{fmt.Errorf("sending to server: %w", err)}
+			}
+			return nil
+		}
+	}
+	svc := a.service
+	return func() tea.Msg {
+		hits, err := svc.Search(context.Background(), text, 0)
+		if err != nil {
+			// Substring matching still applies.
+			return nil
+		}
+		return searchResultsMsg{query: text, ids: searchHitIDs(hits)}
+	}
+}
+
+// searchQueryPrefix tags search requests so their results can be told
+// apart from other replies on the shared connection.
+const searchQueryPrefix = "search:"
+
+func searchHitIDs(hits []db.SearchHit) map[string]bool {
+	ids := make(map[string]bool, len(hits))
+	for _, h := range hits {
+		ids[h.TaskID] = true
+	}
+	return ids
+}
+
+// applySearchResults refilters the board with full-text matches, unless
+// the query changed while they were on their way.
+func (a *App) applySearchResults(msg searchResultsMsg) {
+	if _, text := parseSearch(a.searchQuery); text != msg.query {
+		return
+	}
+	a.searchHits, a.searchHitsFor = msg.ids, msg.query
+	a.board.LoadTasks(a.filteredTasks(a.lastTasks), a.lastDeps)
+}
+
+// parseSearch splits a search query into "label:name" terms, which a task
+// must all carry, and the remaining free text.
+func parseSearch(query string) (labels []string, text string) {
+	var words []string
+	for _, w := range strings.Fields(strings.ToLower(query)) {
+		if name, ok := strings.CutPrefix(w, "label:"); ok {
+			if name != "" {
+				labels = append(labels, name)
+			}
+			continue
+		}
+		words = append(words, w)
+	}
+	return labels, strings.Join(words, " ")
+}
+
+func hasAllLabels(t db.Task, labels []string) bool {
+	for _, l := range labels {
+		if !t.HasLabel(l) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesText reports whether q appears in the task's title, description,
+// assignee or one of its labels. An empty q matches everything.
+func matchesText(t db.Task, q string) bool {
+	if strings.Contains(strings.ToLower(t.Title), q) ||
+		strings.Contains(strings.ToLower(t.Description), q) ||
+		strings.Contains(strings.ToLower(t.Assignee), q) {
+		return true
+	}
+	for _, l := range t.Labels {
+		if strings.Contains(l.Name, q) {
+			return true
+		}
+	}
+	return false
+}
+
 // Command helpers
 
 func bellCmd() tea.Cmd {
@@ -947,6 +1276,16 @@ func (a App) notify(text string) tea.Cmd {
 	}
 }
 
+// notifyDone confirms a change to the board. Changes sent to a sync server
+// are only done once it broadcasts them, and a sync.reject may come
+// instead, so they get no notice here.
+func (a App) notifyDone(text string) tea.Cmd {
+	if a.remote != nil {
+		return nil
+	}
+	return a.notify(text)
+}
+
 func (a App) loadSuggestions() tea.Cmd {
 	return func() tea.Msg {
 		items, err := a.service.ListPendingSuggestions(context.Background())
@@ -976,6 +1315,11 @@ func (a App) dismissSuggestion(id string) tea.Cmd {
 }
 
 func (a App) createTask(title, description string) tea.Cmd {
+	if a.connector != nil {
+		return a.sendRemote(server.MsgTaskCreate,
+			server.TaskCreatePayload{Title: title, Description: description},
+			taskCreatedMsg{task: &db.Task{Title: title, Description: description}})
+	}
 	return func() tea.Msg {
 		task, err := a.service.CreateTask(context.Background(), title, description)
 		if err != nil {
@@ -986,6 +1330,12 @@ func (a App) createTask(title, description string) tea.Cmd {
 }
 
 func (a App) saveTask(task db.Task) tea.Cmd {
+	if a.connector != nil {
+		// The sync protocol only carries title and description edits.
+		return a.sendRemote(server.MsgTaskUpdate,
+			server.TaskUpdatePayload{TaskID: task.ID, Title: &task.Title, Description: &task.Description},
+			taskSavedMsg{task: task})
+	}
 	return func() tea.Msg {
 		if err := a.service.UpdateTask(context.Background(), &task); err != nil {
 			return errMsg{fmt.Errorf("saving task: %w", err)}
@@ -1003,6 +1353,18 @@ func (a App) moveTask(id string, newStatus db.TaskStatus) tea.Cmd {
 		hadAgent = a.detail.task.AgentStatus == db.AgentActive
 	}
 
+	if a.connector != nil {
+		from := ""
+		for _, t := range a.lastTasks {
+			if t.ID == id {
+				from = string(t.Status)
+			}
+		}
+		return a.sendRemote(server.MsgTaskMove,
+			server.TaskMovePayload{TaskID: id, FromColumn: from, ToColumn: string(newStatus)},
+			taskMovedMsg{taskID: id, newStatus: newStatus})
+	}
+
 	return func() tea.Msg {
 		ctx := context.Background()
 		if err := a.service.MoveTask(ctx, id, newStatus); err != nil {
@@ -1027,7 +1389,26 @@ func (a App) moveTask(id string, newStatus db.TaskStatus) tea.Cmd {
 	}
 }
 
+// reorderTask places task just before beforeID or just after afterID in
+// its column.
+func (a App) reorderTask(task db.Task, beforeID, afterID string) tea.Cmd {
+	done := taskReorderedMsg{taskID: task.ID, status: task.Status}
+	if a.connector != nil {
+		return a.sendRemote(server.MsgTaskReorder,
+			server.TaskReorderPayload{TaskID: task.ID, BeforeID: beforeID, AfterID: afterID}, done)
+	}
+	return func() tea.Msg {
+		if err := a.service.ReorderTask(context.Background(), task.ID, beforeID, afterID); err != nil {
+			return errMsg{err}
+		}
+		return done
+	}
+}
+
 func (a App) deleteTask(id string) tea.Cmd {
+	if a.connector != nil {
+		return a.sendRemote(server.MsgTaskDelete, server.TaskDeletePayload{TaskID: id}, taskDeletedMsg{taskID: id})
+	}
 	return func() tea.Msg {
 		ctx := context.Background()
 		// Kill agent window before deleting
@@ -1043,22 +1424,134 @@ func (a App) deleteTask(id string) tea.Cmd {
 	}
 }
 
-func (a App) nextStatus(current db.TaskStatus) db.TaskStatus {
-	for i, s := range columnOrder {
-		if s == current && i < len(columnOrder)-1 {
-			return columnOrder[i+1]
+// leaderStatus reports the embedded server's peer count.
+func (a App) leaderStatus() tea.Cmd {
+	addr, count := a.tunnelURL, a.peerCounter
+	return func() tea.Msg {
+		return serverStatusMsg{tunnelURL: addr, peerCount: count(), connected: true}
+	}
+}
+
+// toggleClaim claims an unassigned task or unclaims an assigned one. Remote
+// claims are made as the authenticated user.
+func (a App) toggleClaim(task db.Task) tea.Cmd {
+	claim := task.Assignee == ""
+	if a.connector != nil {
+		if claim {
+			return a.sendRemote(server.MsgTaskClaim, server.TaskClaimPayload{TaskID: task.ID}, taskClaimedMsg{claimed: true})
+		}
+		return a.sendRemote(server.MsgTaskUnclaim, server.TaskUnclaimPayload{TaskID: task.ID}, taskClaimedMsg{})
+	}
+	return func() tea.Msg {
+		ctx := context.Background()
+		var err error
+		if claim {
+			err = a.service.ClaimTask(ctx, task.ID, localUser)
+		} else {
+			err = a.service.UnclaimTask(ctx, task.ID)
+		}
+		if err != nil {
+			return errMsg{err}
 		}
+		return taskClaimedMsg{claimed: claim}
 	}
-	return current
 }
 
-func (a App) prevStatus(current db.TaskStatus) db.TaskStatus {
-	for i, s := range columnOrder {
-		if s == current && i > 0 {
-			return columnOrder[i-1]
+// addComment adds a comment as the local user, or as the authenticated
+// user when connected.
+func (a App) addComment(taskID, body string) tea.Cmd {
+	if a.connector != nil {
+		return a.sendRemote(server.MsgTaskComment, server.TaskCommentPayload{TaskID: taskID, Body: body}, commentAddedMsg{taskID: taskID})
+	}
+	return func() tea.Msg {
+		if _, err := a.service.AddComment(context.Background(), taskID, localUser, body); err != nil {
+			return errMsg{err}
+		}
+		return commentAddedMsg{taskID: taskID}
+	}
+}
+
+// openDetail shows the detail overlay for task. In remote mode comments come
+// from those received this session and the history is fetched from the
+// server.
+func (a *App) openDetail(task db.Task) tea.Cmd {
+	var cmd tea.Cmd
+	if a.remote != nil {
+		a.detail = taskDetail{task: task, comments: a.remote.commentsFor(task.ID)}
+		cmd = tea.Batch(a.requestRemoteHistory(task.ID), a.requestRemoteComments(task.ID))
+	} else {
+		a.detail = newTaskDetail(task, a.service)
+	}
+	a.detail.lastColumn = a.board.workflow.Last()
+	a.detail.SetSize(a.width, a.height)
+	a.overlay = overlayDetail
+	return cmd
+}
+
+// reloadHistory refreshes the history of the open local detail view.
+func (a *App) reloadHistory() {
+	if a.remote == nil && a.overlay == overlayDetail {
+		a.detail.history, _ = a.service.ListTaskEvents(context.Background(), a.detail.task.ID)
+	}
+}
+
+// historyQueryPrefix and commentQueryPrefix tag history.list and
+// comment.list requests so their results can be told apart from other
+// replies on the shared connection.
+const (
+	historyQueryPrefix = "history:"
+	commentQueryPrefix = "comments:"
+)
+
+// requestRemoteHistory asks the server for a task's history. The answer
+// arrives as a remoteMsg.
+func (a App) requestRemoteHistory(taskID string) tea.Cmd {
+	return a.queryRemote(server.MsgHistoryList, historyQueryPrefix+taskID, server.HistoryListPayload{TaskID: taskID})
+}
+
+// requestRemoteComments asks the server for a task's comments, which the
+// remote store otherwise only learns of as they are broadcast. The answer
+// arrives as a remoteMsg.
+func (a App) requestRemoteComments(taskID string) tea.Cmd {
+	return a.queryRemote(server.MsgCommentList, commentQueryPrefix+taskID, server.CommentListPayload{TaskID: taskID})
+}
+
+// queryRemote sends a request tagged with id to the sync server.
+func (a App) queryRemote(msgType, id string, payload interface{}) tea.Cmd {
+	return func() tea.Msg {
+		msg, err := server.NewMessage(msgType, "", payload)
+		if err != nil {
+			return errMsg{err}
 		}
+		msg.ID = id
+		if err := a.connector.Send(msg); err != nil {
+			return errMsg{fmt.Errorf("sending to server: %w", err)}
+		}
+		return nil
+	}
+}
+
+// refreshRemoteDetail updates an open, non-editing detail view with the
+// latest remote state.
+func (a *App) refreshRemoteDetail() {
+	if a.overlay != overlayDetail || a.detail.editing {
+		return
 	}
-	return current
+	for _, t := range a.remote.snapshot() {
+		if t.ID == a.detail.task.ID {
+			a.detail.task = t
+			a.detail.comments = a.remote.commentsFor(t.ID)
+			return
+		}
+	}
+}
+
+func (a App) nextStatus(current db.TaskStatus) db.TaskStatus {
+	return a.board.workflow.Next(current)
+}
+
+func (a App) prevStatus(current db.TaskStatus) db.TaskStatus {
+	return a.board.workflow.Prev(current)
 }
 
 // Agent command helpers
@@ -1073,16 +1566,25 @@ func (a *App) spawnAgent(task db.Task) tea.Cmd {
 	case 1:
 		return a.spawnAgentWithRunner(task, runners[0])
 	default:
-		a.picker = newAgentPicker(runners, task, a.width, a.height)
+		a.picker = newAgentPicker(runners, task, a.cfg.Agent.Preferred, a.width, a.height)
 		a.overlay = overlayPicker
 		return nil
 	}
 }
 
-// spawnAgentWithRunner spawns a specific agent runner on a task.
+// spawnAgentWithRunner spawns a specific agent runner on a task, or queues
+// it when all agent slots are busy.
 func (a App) spawnAgentWithRunner(task db.Task, runner agent.AgentRunner) tea.Cmd {
+	if q, ok := a.service.(agent.Queue); ok && agent.ActiveAgents(a.lastTasks) >= a.cfg.Agent.MaxConcurrentAgents {
+		return func() tea.Msg {
+			if err := q.EnqueueAgent(context.Background(), task.ID, runner.ID()); err != nil {
+				return errMsg{fmt.Errorf("queueing agent: %w", err)}
+			}
+			return agentQueuedMsg{taskID: task.ID, queued: true}
+		}
+	}
 	return func() tea.Msg {
-		if err := agent.Spawn(context.Background(), a.service, task, runner); err != nil {
+		if err := agent.Spawn(context.Background(), a.service, task, runner, a.cfg); err != nil {
 			return errMsg{fmt.Errorf("%s", err)}
 		}
 		return agentSpawnedMsg{taskID: task.ID}
@@ -1133,10 +1635,12 @@ func (a App) respawnAgent(taskID string, newStatus db.TaskStatus) tea.Cmd {
 		}
 
 		// Deactivate any active ralph loop so the new agent runs once without looping
-		_ = agent.DeactivateRalphLoop(*task)
+		if dir, err := agent.FindWorktree(*task); err == nil && dir != "" {
+			_ = agent.DeactivateRalphLoop(dir)
+		}
 
 		// Spawn handles killing the old window and creating a new one
-		if err := agent.Spawn(ctx, a.service, *task, runner); err != nil {
+		if err := agent.Spawn(ctx, a.service, *task, runner, a.cfg); err != nil {
 			return errMsg{fmt.Errorf("respawn agent: %w", err)}
 		}
 		return agentSpawnedMsg{taskID: taskID}
@@ -1170,6 +1674,38 @@ func (a App) toggleEnrichment(task db.Task) tea.Cmd {
 	}
 }
 
+// unqueueAgent takes a task's waiting agent off the queue.
+func (a App) unqueueAgent(task db.Task) tea.Cmd {
+	q, ok := a.service.(agent.Queue)
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg {
+		if _, err := q.DequeueAgent(context.Background(), task.ID); err != nil {
+			return errMsg{fmt.Errorf("unqueueing agent: %w", err)}
+		}
+		return agentQueuedMsg{taskID: task.ID}
+	}
+}
+
+// startQueuedAgents spawns queued agents into the free agent slots.
+func (a App) startQueuedAgents() tea.Cmd {
+	q, ok := a.service.(agent.Queue)
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg {
+		started, err := agent.StartQueued(context.Background(), a.service, q, a.cfg)
+		if err != nil {
+			return errMsg{fmt.Errorf("starting queued agents: %w", err)}
+		}
+		if len(started) == 0 {
+			return nil
+		}
+		return queuedAgentsStartedMsg{tasks: started}
+	}
+}
+
 func (a App) killAgent(task db.Task) tea.Cmd {
 	return func() tea.Msg {
 		if err := agent.Kill(context.Background(), a.service, task); err != nil {
@@ -1,9 +1,11 @@
 package tui
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/markx3/agentboard/internal/db"
+	"github.com/markx3/agentboard/internal/workflow"
 )
 
 func TestPruneEnrichmentSeen(t *testing.T) {
@@ -56,3 +58,78 @@ func TestPruneEnrichmentSeenNoOp(t *testing.T) {
 		t.Errorf("expected 2 entries (no pruning needed), got %d", len(seen))
 	}
 }
+
+func TestSearchAddsIndexHits(t *testing.T) {
+	tasks := []db.Task{
+		{ID: "1", Title: "Fix login"},
+		{ID: "2", Title: "Add cache", Labels: []db.Label{{Name: "backend"}}},
+		{ID: "3", Title: "Dark mode"},
+	}
+	// Task 2 matched in a comment; task 3 matched an earlier query.
+	a := App{searchQuery: "login label:backend"}
+	a.applySearchResults(searchResultsMsg{query: "login", ids: map[string]bool{"2": true}})
+	a.applySearchResults(searchResultsMsg{query: "dark", ids: map[string]bool{"3": true}})
+
+	got := a.filteredTasks(tasks)
+	if len(got) != 1 || got[0].ID != "2" {
+		t.Errorf("got %+v, want only the labelled index hit", got)
+	}
+
+	a.searchQuery = "login"
+	if got := a.filteredTasks(tasks); len(got) != 2 {
+		t.Errorf("got %d tasks, want the substring match and the index hit", len(got))
+	}
+}
+
+func TestSearchByLabel(t *testing.T) {
+	tasks := []db.Task{
+		{ID: "1", Title: "Fix login", Labels: []db.Label{{Name: "bug"}, {Name: "backend"}}},
+		{ID: "2", Title: "Dark mode", Labels: []db.Label{{Name: "ui"}}},
+		{ID: "3", Title: "Login page", Labels: []db.Label{{Name: "ui"}, {Name: "bug"}}},
+	}
+
+	tests := []struct {
+		query   string
+		wantIDs []string
+	}{
+		{"label:bug", []string{"1", "3"}},
+		{"label:bug label:ui", []string{"3"}},
+		{"LABEL:UI login", []string{"3"}},
+		{"backend", []string{"1"}}, // free text also matches label names
+		{"label:", []string{"1", "2", "3"}},
+	}
+	for _, tt := range tests {
+		a := App{searchQuery: tt.query}
+		got := a.filteredTasks(tasks)
+		var ids []string
+		for _, task := range got {
+			ids = append(ids, task.ID)
+		}
+		if strings.Join(ids, ",") != strings.Join(tt.wantIDs, ",") {
+			t.Errorf("%q: got %v, want %v", tt.query, ids, tt.wantIDs)
+		}
+	}
+}
+
+func TestKanbanNeighbor(t *testing.T) {
+	b := newKanban(workflow.Default())
+	b.SetSize(120, 40)
+	b.LoadTasks([]db.Task{
+		{ID: "a", Title: "A", Status: db.StatusBacklog},
+		{ID: "b", Title: "B", Status: db.StatusBacklog},
+	}, nil)
+
+	if got := b.Neighbor(-1); got != nil {
+		t.Errorf("first task has a task above it: %+v", got)
+	}
+	if got := b.Neighbor(1); got == nil || got.ID != "b" {
+		t.Errorf("Neighbor(1) = %+v, want b", got)
+	}
+	b.SelectTaskByID("b")
+	if got := b.Neighbor(-1); got == nil || got.ID != "a" {
+		t.Errorf("Neighbor(-1) = %+v, want a", got)
+	}
+	if got := b.Neighbor(1); got != nil {
+		t.Errorf("last task has a task below it: %+v", got)
+	}
+}
@@ -7,42 +7,29 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/markx3/agentboard/internal/db"
+	"github.com/markx3/agentboard/internal/workflow"
 )
 
-var columnOrder = []db.TaskStatus{
-	db.StatusBacklog,
-	db.StatusBrainstorm,
-	db.StatusPlanning,
-	db.StatusInProgress,
-	db.StatusReview,
-	db.StatusDone,
-}
-
-var columnTitles = map[db.TaskStatus]string{
-	db.StatusBacklog:    "Backlog",
-	db.StatusBrainstorm: "Brainstorm",
-	db.StatusPlanning:   "Planning",
-	db.StatusInProgress: "In Progress",
-	db.StatusReview:     "Review",
-	db.StatusDone:       "Done",
-}
-
 type kanban struct {
+	workflow   *workflow.Workflow
 	columns    []column
 	focusedCol int
 	width      int
 	height     int
 }
 
-func newKanban() kanban {
-	cols := make([]column, len(columnOrder))
-	for i, status := range columnOrder {
-		cols[i] = newColumn(columnTitles[status], status)
+func newKanban(wf *workflow.Workflow) kanban {
+	wfCols := wf.Columns()
+	cols := make([]column, len(wfCols))
+	for i, c := range wfCols {
+		cols[i] = newColumn(c.Name, c.Status)
+		cols[i].last = wf.Last()
 	}
 	cols[0].focused = true
 
 	return kanban{
-		columns: cols,
+		workflow: wf,
+		columns:  cols,
 	}
 }
 
@@ -73,8 +60,8 @@ func (b *kanban) LoadTasks(tasks []db.Task, deps map[string][]string) {
 	for _, t := range tasks {
 		grouped[t.Status] = append(grouped[t.Status], t)
 	}
-	for i, status := range columnOrder {
-		b.columns[i].SetItems(grouped[status], deps)
+	for i := range b.columns {
+		b.columns[i].SetItems(grouped[b.columns[i].status], deps)
 	}
 }
 
@@ -82,20 +69,18 @@ func (b *kanban) SelectedTask() *db.Task {
 	return b.columns[b.focusedCol].SelectedTask()
 }
 
+// Neighbor returns the task above (offset -1) or below (offset 1) the
+// selected one in the focused column.
+func (b *kanban) Neighbor(offset int) *db.Task {
+	return b.columns[b.focusedCol].Neighbor(offset)
+}
+
 func (b *kanban) NextColumn() db.TaskStatus {
-	idx := b.focusedCol
-	if idx < len(columnOrder)-1 {
-		return columnOrder[idx+1]
-	}
-	return columnOrder[idx]
+	return b.workflow.Next(b.columns[b.focusedCol].status)
 }
 
 func (b *kanban) PrevColumn() db.TaskStatus {
-	idx := b.focusedCol
-	if idx > 0 {
-		return columnOrder[idx-1]
-	}
-	return columnOrder[idx]
+	return b.workflow.Prev(b.columns[b.focusedCol].status)
 }
 
 func (b kanban) Update(msg tea.Msg) (kanban, tea.Cmd) {
@@ -122,8 +107,8 @@ func (b *kanban) FocusRight() {
 
 // FocusOnStatus moves column focus to the column matching the given status.
 func (b *kanban) FocusOnStatus(status db.TaskStatus) {
-	for i, s := range columnOrder {
-		if s == status {
+	for i, c := range b.columns {
+		if c.status == status {
 			b.columns[b.focusedCol].focused = false
 			b.focusedCol = i
 			b.columns[b.focusedCol].focused = true
@@ -164,18 +149,19 @@ func (b kanban) summaryBar() string {
 	parts = append(parts, fmt.Sprintf("Agents: %d active", agentActive))
 
 	var taskParts []string
-	for _, s := range columnOrder {
-		if s == db.StatusDone || s == db.StatusBacklog {
+	first, last := b.workflow.First(), b.workflow.Last()
+	for _, col := range b.columns {
+		if col.status == first || col.status == last {
 			continue
 		}
-		if c := statusCounts[s]; c > 0 {
-			taskParts = append(taskParts, fmt.Sprintf("%d %s", c, columnTitles[s]))
+		if c := statusCounts[col.status]; c > 0 {
+			taskParts = append(taskParts, fmt.Sprintf("%d %s", c, col.title))
 		}
 	}
 	if len(taskParts) > 0 {
 		parts = append(parts, "Tasks: "+strings.Join(taskParts, ", "))
 	}
-	parts = append(parts, fmt.Sprintf("%d done", statusCounts[db.StatusDone]))
+	parts = append(parts, fmt.Sprintf("%d %s", statusCounts[last], strings.ToLower(b.workflow.Name(last))))
 
 	return summaryBarStyle.Render("  " + strings.Join(parts, " | "))
 }
@@ -11,6 +11,7 @@ import (
 type column struct {
 	title   string
 	status  db.TaskStatus
+	last    db.TaskStatus // last column of the workflow: tasks there are finished
 	list    list.Model
 	focused bool
 	width   int
@@ -50,7 +51,7 @@ func (c *column) SetItems(tasks []db.Task, deps map[string][]string) {
 		if deps != nil {
 			depCount = len(deps[t.ID])
 		}
-		items[i] = taskItem{task: t, depCount: depCount}
+		items[i] = taskItem{task: t, depCount: depCount, last: c.last}
 	}
 	c.list.SetItems(items)
 }
@@ -64,6 +65,18 @@ func (c *column) SelectedTask() *db.Task {
 	return &ti.task
 }
 
+// Neighbor returns the task offset places from the selected one, or nil
+// past either end of the column.
+func (c *column) Neighbor(offset int) *db.Task {
+	items := c.list.Items()
+	i := c.list.Index() + offset
+	if c.list.SelectedItem() == nil || i < 0 || i >= len(items) {
+		return nil
+	}
+	ti := items[i].(taskItem)
+	return &ti.task
+}
+
 // SelectTaskByID moves the list cursor to the task with the given ID.
 func (c *column) SelectTaskByID(taskID string) {
 	for i, item := range c.list.Items() {
@@ -9,6 +9,8 @@ type keyMap struct {
 	Down        key.Binding
 	MoveRight   key.Binding
 	MoveLeft    key.Binding
+	MoveUp      key.Binding
+	MoveDown    key.Binding
 	New         key.Binding
 	Enter       key.Binding
 	Delete      key.Binding
@@ -22,6 +24,7 @@ type keyMap struct {
 	Search      key.Binding
 	Suggestions    key.Binding
 	ToggleEnrich   key.Binding
+	Claim          key.Binding
 }
 
 var keys = keyMap{
@@ -49,6 +52,14 @@ var keys = keyMap{
 		key.WithKeys("M"),
 		key.WithHelp("M", "move task left"),
 	),
+	MoveUp: key.NewBinding(
+		key.WithKeys("K", "shift+up"),
+		key.WithHelp("K", "move task up"),
+	),
+	MoveDown: key.NewBinding(
+		key.WithKeys("J", "shift+down"),
+		key.WithHelp("J", "move task down"),
+	),
 	New: key.NewBinding(
 		key.WithKeys("o"),
 		key.WithHelp("o", "new task"),
@@ -67,7 +78,7 @@ var keys = keyMap{
 	),
 	KillAgent: key.NewBinding(
 		key.WithKeys("A"),
-		key.WithHelp("A", "kill agent"),
+		key.WithHelp("A", "kill/unqueue agent"),
 	),
 	ViewAgent: key.NewBinding(
 		key.WithKeys("v"),
@@ -101,4 +112,8 @@ var keys = keyMap{
 		key.WithKeys("E"),
 		key.WithHelp("E", "toggle enrichment"),
 	),
+	Claim: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "claim/unclaim (comment in detail)"),
+	),
 }
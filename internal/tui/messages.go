@@ -17,6 +17,11 @@ type taskMovedMsg struct {
 	hadAgent  bool
 }
 
+type taskReorderedMsg struct {
+	taskID string
+	status db.TaskStatus
+}
+
 type taskDeletedMsg struct {
 	taskID string
 }
@@ -39,6 +44,18 @@ type agentKilledMsg struct {
 	taskID string
 }
 
+// agentQueuedMsg reports a task's agent was queued or, when queued is
+// false, taken off the queue.
+type agentQueuedMsg struct {
+	taskID string
+	queued bool
+}
+
+// queuedAgentsStartedMsg lists the tasks whose queued agents were started.
+type queuedAgentsStartedMsg struct {
+	tasks []db.Task
+}
+
 type agentViewDoneMsg struct{}
 
 type agentTickMsg struct{}
@@ -63,6 +80,22 @@ type taskSaveRequestedMsg struct {
 	task db.Task
 }
 
+// commentRequestedMsg is emitted by the task detail overlay when the user submits a comment.
+type commentRequestedMsg struct {
+	taskID string
+	body   string
+}
+
+// commentAddedMsg is emitted after a comment is saved or sent to the server.
+type commentAddedMsg struct {
+	taskID string
+}
+
+// taskClaimedMsg is emitted after a task is claimed or unclaimed.
+type taskClaimedMsg struct {
+	claimed bool
+}
+
 type suggestionsLoadedMsg struct {
 	items []db.Suggestion
 }
@@ -75,4 +108,11 @@ type suggestionDismissedMsg struct {
 	suggestionID string
 }
 
+// searchResultsMsg carries the IDs of the tasks the full-text index
+// matched for query.
+type searchResultsMsg struct {
+	query string
+	ids   map[string]bool
+}
+
 type clearNotificationMsg struct{}
@@ -0,0 +1,329 @@
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/markx3/agentboard/internal/db"
+	"github.com/markx3/agentboard/internal/peersync"
+	"github.com/markx3/agentboard/internal/server"
+)
+
+const remoteAgentsUnsupported = "Agents can't be managed from a remote board yet"
+
+// remoteStore holds the board state received from a sync server. While
+// connected, the TUI renders from it instead of the local database.
+type remoteStore struct {
+	mu       sync.Mutex
+	tasks    map[string]db.Task
+	comments map[string][]db.Comment
+}
+
+func newRemoteStore() *remoteStore {
+	return &remoteStore{
+		tasks:    make(map[string]db.Task),
+		comments: make(map[string][]db.Comment),
+	}
+}
+
+// apply folds one server message into the store. It returns a notice for
+// messages the user should see (rejects, peers joining or leaving).
+func (s *remoteStore) apply(msg server.Message) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch msg.Type {
+	case server.MsgSyncFull:
+		var tasks []db.Task
+		if err := json.Unmarshal(msg.Payload, &tasks); err != nil {
+			return "", fmt.Errorf("decoding %s: %w", msg.Type, err)
+		}
+		s.tasks = make(map[string]db.Task, len(tasks))
+		for _, t := range tasks {
+			s.tasks[t.ID] = t
+		}
+
+	case server.MsgTaskCreate, server.MsgTaskUpdate, server.MsgAgentState,
+		server.MsgLabelAdd, server.MsgLabelRemove,
+		server.MsgCheckAdd, server.MsgCheckSet, server.MsgCheckRemove:
+		var t db.Task
+		if err := json.Unmarshal(msg.Payload, &t); err != nil {
+			return "", fmt.Errorf("decoding %s: %w", msg.Type, err)
+		}
+		// Single-task payloads don't carry dependencies.
+		if prev, ok := s.tasks[t.ID]; ok && t.BlockedBy == nil {
+			t.BlockedBy = prev.BlockedBy
+		}
+		s.tasks[t.ID] = t
+
+	case server.MsgDepAdd, server.MsgDepRemove:
+		var p server.DepPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			return "", fmt.Errorf("decoding %s: %w", msg.Type, err)
+		}
+		if t, ok := s.tasks[p.TaskID]; ok {
+			var blockers []string
+			for _, id := range t.BlockedBy {
+				if id != p.DependsOn {
+					blockers = append(blockers, id)
+				}
+			}
+			if msg.Type == server.MsgDepAdd {
+				blockers = append(blockers, p.DependsOn)
+			}
+			t.BlockedBy = blockers
+			s.tasks[t.ID] = t
+		}
+
+	case server.MsgTaskMove:
+		var p server.TaskMovePayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			return "", fmt.Errorf("decoding %s: %w", msg.Type, err)
+		}
+		if t, ok := s.tasks[p.TaskID]; ok {
+			t.Status = db.TaskStatus(p.ToColumn)
+			t.Position = s.nextPosition(t.Status)
+			s.tasks[t.ID] = t
+		}
+
+	case server.MsgTaskReorder:
+		var p server.TaskReorderPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			return "", fmt.Errorf("decoding %s: %w", msg.Type, err)
+		}
+		for i, id := range p.Order {
+			if t, ok := s.tasks[id]; ok {
+				t.Position = i
+				s.tasks[id] = t
+			}
+		}
+
+	case server.MsgTaskDelete:
+		var p server.TaskDeletePayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			return "", fmt.Errorf("decoding %s: %w", msg.Type, err)
+		}
+		delete(s.tasks, p.TaskID)
+		delete(s.comments, p.TaskID)
+
+	case server.MsgTaskClaim:
+		var p server.TaskClaimPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			return "", fmt.Errorf("decoding %s: %w", msg.Type, err)
+		}
+		if t, ok := s.tasks[p.TaskID]; ok {
+			t.Assignee = p.Assignee
+			s.tasks[t.ID] = t
+		}
+
+	case server.MsgTaskUnclaim:
+		var p server.TaskUnclaimPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			return "", fmt.Errorf("decoding %s: %w", msg.Type, err)
+		}
+		if t, ok := s.tasks[p.TaskID]; ok {
+			t.Assignee = ""
+			s.tasks[t.ID] = t
+		}
+
+	case server.MsgAgentActivity:
+		var p server.AgentActivityPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			return "", fmt.Errorf("decoding %s: %w", msg.Type, err)
+		}
+		if t, ok := s.tasks[p.TaskID]; ok {
+			t.AgentActivity = p.Activity
+			s.tasks[t.ID] = t
+		}
+
+	case server.MsgTaskComment:
+		var c db.Comment
+		if err := json.Unmarshal(msg.Payload, &c); err != nil {
+			return "", fmt.Errorf("decoding %s: %w", msg.Type, err)
+		}
+		s.comments[c.TaskID] = append(s.comments[c.TaskID], c)
+
+	case server.MsgSyncReject:
+		var p server.SyncRejectPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			return "", fmt.Errorf("decoding %s: %w", msg.Type, err)
+		}
+		return "Rejected by server: " + p.Reason, nil
+
+	case server.MsgPeerJoin, server.MsgPeerLeave:
+		var p server.PeerPayload
+		if err := json.Unmarshal(msg.Payload, &p); err != nil {
+			return "", fmt.Errorf("decoding %s: %w", msg.Type, err)
+		}
+		if msg.Type == server.MsgPeerJoin {
+			return p.Username + " joined", nil
+		}
+		return p.Username + " left", nil
+	}
+	return "", nil
+}
+
+// changesTasks reports whether a message from the server records a change
+// to a task, so an open detail view's history may be stale. Agent activity
+// isn't kept in history.
+func changesTasks(msgType string) bool {
+	switch msgType {
+	case server.MsgTaskCreate, server.MsgTaskUpdate, server.MsgAgentState,
+		server.MsgTaskMove, server.MsgTaskClaim, server.MsgTaskUnclaim,
+		server.MsgTaskComment, server.MsgDepAdd, server.MsgDepRemove,
+		server.MsgLabelAdd, server.MsgLabelRemove,
+		server.MsgCheckAdd, server.MsgCheckSet, server.MsgCheckRemove:
+		return true
+	}
+	return false
+}
+
+// nextPosition mirrors the database: a moved task goes to the end of its
+// new column. Callers must hold s.mu.
+func (s *remoteStore) nextPosition(status db.TaskStatus) int {
+	pos := 0
+	for _, t := range s.tasks {
+		if t.Status == status && t.Position >= pos {
+			pos = t.Position + 1
+		}
+	}
+	return pos
+}
+
+// snapshot returns all tasks ordered like db.ListTasks (status, position).
+func (s *remoteStore) snapshot() []db.Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tasks := make([]db.Task, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		tasks = append(tasks, t)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].Status != tasks[j].Status {
+			return tasks[i].Status < tasks[j].Status
+		}
+		return tasks[i].Position < tasks[j].Position
+	})
+	return tasks
+}
+
+// deps returns the dependency map of the stored tasks.
+func (s *remoteStore) deps() map[string][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deps := make(map[string][]string)
+	for id, t := range s.tasks {
+		if len(t.BlockedBy) > 0 {
+			deps[id] = append([]string(nil), t.BlockedBy...)
+		}
+	}
+	return deps
+}
+
+// setComments stores the comments fetched for a task, keeping any
+// broadcast since the request that the fetch missed.
+func (s *remoteStore) setComments(taskID string, fetched []db.Comment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seen := make(map[string]bool, len(fetched))
+	for _, c := range fetched {
+		seen[c.ID] = true
+	}
+	comments := append([]db.Comment(nil), fetched...)
+	for _, c := range s.comments[taskID] {
+		if !seen[c.ID] {
+			comments = append(comments, c)
+		}
+	}
+	s.comments[taskID] = comments
+}
+
+// commentsFor returns the comments fetched for a task and those broadcast
+// since.
+func (s *remoteStore) commentsFor(taskID string) []db.Comment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]db.Comment(nil), s.comments[taskID]...)
+}
+
+// remoteMsg carries one message read from the sync server.
+type remoteMsg struct {
+	msg server.Message
+}
+
+// remoteStateMsg reports a change in the connection to the sync server.
+type remoteStateMsg struct {
+	state peersync.ConnState
+}
+
+// remoteClosedMsg is emitted when the connection to the sync server ends.
+type remoteClosedMsg struct{}
+
+// listenRemote waits for the next message or state change from the connector.
+func listenRemote(c *peersync.Connector) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case msg := <-c.Messages:
+			return remoteMsg{msg: msg}
+		case state := <-c.States:
+			return remoteStateMsg{state: state}
+		case <-c.Done():
+			return remoteClosedMsg{}
+		}
+	}
+}
+
+// listen waits for the next message from the sync server, whether this TUI
+// renders the server's board or only follows the leader's changes.
+func (a App) listen() tea.Cmd {
+	if a.feed != nil {
+		return listenRemote(a.feed)
+	}
+	return listenRemote(a.connector)
+}
+
+// followChange handles a broadcast on a follower. The board is already in
+// the shared database, so changes only trigger a reload.
+func (a *App) followChange(msg server.Message) tea.Cmd {
+	cmds := []tea.Cmd{a.listen()}
+	switch {
+	case msg.Type == server.MsgPeerJoin || msg.Type == server.MsgPeerLeave:
+		var p server.PeerPayload
+		if err := json.Unmarshal(msg.Payload, &p); err == nil {
+			verb := " joined"
+			if msg.Type == server.MsgPeerLeave {
+				verb = " left"
+			}
+			cmds = append(cmds, a.notify(p.Username+verb))
+		}
+	case changesTasks(msg.Type), msg.Type == server.MsgTaskDelete,
+		msg.Type == server.MsgTaskReorder, msg.Type == server.MsgSyncFull:
+		a.reloadHistory()
+		if msg.Type == server.MsgTaskComment && a.overlay == overlayDetail {
+			a.detail.comments, _ = a.service.ListComments(context.Background(), a.detail.task.ID)
+		}
+		cmds = append(cmds, a.loadTasks())
+	}
+	return tea.Batch(cmds...)
+}
+
+// sendRemote sends a message to the sync server. The result arrives later
+// as a broadcast (or a sync.reject), so success returns done, whose
+// handler must not announce the change as made (see notifyDone). While
+// reconnecting, the connector queues the message.
+func (a App) sendRemote(msgType string, payload interface{}, done tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, err := server.NewMessage(msgType, "", payload)
+		if err != nil {
+			return errMsg{err}
+		}
+		if err := a.connector.Send(msg); err != nil {
+			return errMsg{fmt.Errorf("sending to server: %w", err)}
+		}
+		return done
+	}
+}
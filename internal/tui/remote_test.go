@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/markx3/agentboard/internal/board"
+	"github.com/markx3/agentboard/internal/db"
+	"github.com/markx3/agentboard/internal/peersync"
+	"github.com/markx3/agentboard/internal/server"
+)
+
+func mustMessage(t *testing.T, msgType string, payload interface{}) server.Message {
+	t.Helper()
+	msg, err := server.NewMessage(msgType, "server", payload)
+	if err != nil {
+		t.Fatalf("NewMessage(%s): %v", msgType, err)
+	}
+	return msg
+}
+
+func TestRemoteStoreApply(t *testing.T) {
+	s := newRemoteStore()
+
+	full := []db.Task{
+		{ID: "a", Title: "First", Status: db.StatusBacklog, Position: 0},
+		{ID: "b", Title: "Second", Status: db.StatusBacklog, Position: 1},
+	}
+	if _, err := s.apply(mustMessage(t, server.MsgSyncFull, full)); err != nil {
+		t.Fatalf("sync.full: %v", err)
+	}
+	if got := len(s.snapshot()); got != 2 {
+		t.Fatalf("after sync.full got %d tasks, want 2", got)
+	}
+
+	s.apply(mustMessage(t, server.MsgTaskCreate, db.Task{ID: "c", Title: "Third", Status: db.StatusPlanning}))
+	s.apply(mustMessage(t, server.MsgTaskMove, server.TaskMovePayload{TaskID: "a", ToColumn: "planning"}))
+	s.apply(mustMessage(t, server.MsgTaskClaim, server.TaskClaimPayload{TaskID: "b", Assignee: "alice"}))
+	s.apply(mustMessage(t, server.MsgTaskDelete, server.TaskDeletePayload{TaskID: "c"}))
+	s.apply(mustMessage(t, server.MsgTaskComment, db.Comment{ID: "x", TaskID: "a", Author: "bob", Body: "hi"}))
+
+	byID := map[string]db.Task{}
+	for _, task := range s.snapshot() {
+		byID[task.ID] = task
+	}
+	if len(byID) != 2 {
+		t.Fatalf("got %d tasks, want 2 after delete", len(byID))
+	}
+	if byID["a"].Status != db.StatusPlanning {
+		t.Errorf("task a status = %s, want planning", byID["a"].Status)
+	}
+	if byID["b"].Assignee != "alice" {
+		t.Errorf("task b assignee = %q, want alice", byID["b"].Assignee)
+	}
+	if got := s.commentsFor("a"); len(got) != 1 || got[0].Body != "hi" {
+		t.Errorf("comments for a = %+v", got)
+	}
+
+	s.apply(mustMessage(t, server.MsgTaskUnclaim, server.TaskUnclaimPayload{TaskID: "b"}))
+	for _, task := range s.snapshot() {
+		if task.ID == "b" && task.Assignee != "" {
+			t.Errorf("task b still assigned to %q after unclaim", task.Assignee)
+		}
+	}
+}
+
+func TestRemoteStoreReorder(t *testing.T) {
+	s := newRemoteStore()
+	s.apply(mustMessage(t, server.MsgSyncFull, []db.Task{
+		{ID: "a", Status: db.StatusBacklog, Position: 0},
+		{ID: "b", Status: db.StatusBacklog, Position: 1},
+		{ID: "c", Status: db.StatusBacklog, Position: 2},
+	}))
+	s.apply(mustMessage(t, server.MsgTaskReorder,
+		server.TaskReorderPayload{TaskID: "c", BeforeID: "a", Order: []string{"c", "a", "b"}}))
+
+	var got []string
+	for _, task := range s.snapshot() {
+		got = append(got, task.ID)
+	}
+	if len(got) != 3 || got[0] != "c" || got[1] != "a" || got[2] != "b" {
+		t.Errorf("order = %v, want [c a b]", got)
+	}
+}
+
+func TestRemoteStoreRejectNotice(t *testing.T) {
+	s := newRemoteStore()
+	notice, err := s.apply(mustMessage(t, server.MsgSyncReject, server.SyncRejectPayload{Reason: "invalid status"}))
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if notice != "Rejected by server: invalid status" {
+		t.Errorf("notice = %q", notice)
+	}
+}
+
+func TestRemoteStoreDependencies(t *testing.T) {
+	s := newRemoteStore()
+	s.apply(mustMessage(t, server.MsgSyncFull, []db.Task{
+		{ID: "a", Title: "First", BlockedBy: []string{"b"}},
+		{ID: "b", Title: "Second"},
+		{ID: "c", Title: "Third"},
+	}))
+
+	s.apply(mustMessage(t, server.MsgDepAdd, server.DepPayload{TaskID: "a", DependsOn: "c"}))
+	s.apply(mustMessage(t, server.MsgDepRemove, server.DepPayload{TaskID: "a", DependsOn: "b"}))
+	// A single-task update keeps the dependencies the store already knows.
+	s.apply(mustMessage(t, server.MsgTaskUpdate, db.Task{ID: "a", Title: "Renamed"}))
+
+	deps := s.deps()
+	if len(deps) != 1 || len(deps["a"]) != 1 || deps["a"][0] != "c" {
+		t.Errorf("deps = %v, want a blocked by c only", deps)
+	}
+}
+
+func TestRemoteStoreSetComments(t *testing.T) {
+	s := newRemoteStore()
+	s.apply(mustMessage(t, server.MsgTaskComment, db.Comment{ID: "y", TaskID: "a", Body: "broadcast"}))
+	s.apply(mustMessage(t, server.MsgTaskComment, db.Comment{ID: "z", TaskID: "a", Body: "newest"}))
+
+	// The fetch holds comments from before this session and one that was
+	// also broadcast; z arrived after the server answered.
+	s.setComments("a", []db.Comment{{ID: "w", TaskID: "a", Body: "old"}, {ID: "y", TaskID: "a", Body: "broadcast"}})
+
+	var got []string
+	for _, c := range s.commentsFor("a") {
+		got = append(got, c.ID)
+	}
+	if len(got) != 3 || got[0] != "w" || got[1] != "y" || got[2] != "z" {
+		t.Errorf("comments = %v, want [w y z]", got)
+	}
+}
+
+func TestFollowerReloadsFromDatabase(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("opening test db: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	svc := board.NewLocalService(database)
+	ctx := context.Background()
+	task, err := svc.CreateTask(ctx, "Shared", "")
+	if err != nil {
+		t.Fatalf("creating task: %v", err)
+	}
+
+	a := NewApp(svc, WithFollower("127.0.0.1:1", peersync.NewConnector("127.0.0.1:1", "")))
+	a.overlay = overlayDetail
+	a.detail = taskDetail{task: *task}
+
+	// Another TUI on the board comments; the leader broadcasts it.
+	c, err := svc.AddComment(ctx, task.ID, "bob", "looks good")
+	if err != nil {
+		t.Fatalf("adding comment: %v", err)
+	}
+	if cmd := a.followChange(mustMessage(t, server.MsgTaskComment, c)); cmd == nil {
+		t.Fatal("followChange returned no command")
+	}
+	if len(a.detail.comments) != 1 || a.detail.comments[0].Body != "looks good" {
+		t.Errorf("comments = %+v, want the new comment from the database", a.detail.comments)
+	}
+	if len(a.detail.history) == 0 {
+		t.Error("history was not reloaded")
+	}
+}
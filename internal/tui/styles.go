@@ -36,6 +36,7 @@ var (
 	agentActiveStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#f1fa8c"))
 	agentErrorStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5555"))
 	agentIdleStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	agentQueuedStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#bd93f9"))
 
 	cardDoneBg      = lipgloss.NewStyle().Background(lipgloss.Color("#1a3a2a"))
 	cardCompletedBg = lipgloss.NewStyle().Background(lipgloss.Color("#1a2a3a"))
@@ -87,6 +88,30 @@ var (
 	blockedStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#ff79c6"))
 
+	// Priority and due date badges on cards
+	priorityUrgentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5555")).Bold(true)
+	priorityHighStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#ffb86c"))
+	priorityMediumStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#f1fa8c"))
+	priorityLowStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	overdueStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5555")).Bold(true)
+	dueTodayStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("#ffb86c"))
+	dueStyle            = lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+
+	// Label chips on cards and in the detail view; the background is the
+	// label's own color.
+	labelChipStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#282a36")).
+			Background(lipgloss.Color("#888888")).
+			Padding(0, 1)
+
+	// Checklist progress on cards and ticked items in the detail view
+	checklistStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#8be9fd"))
+	checklistDoneStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#50fa7b"))
+
+	// Task history timeline in the detail view
+	historyStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#888888"))
+
 	// Suggestion badge in summary bar
 	suggestionBadgeStyle = lipgloss.NewStyle().
 				Foreground(lipgloss.Color("#e6b450")).
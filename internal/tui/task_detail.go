@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -29,6 +30,8 @@ type taskDetail struct {
 	task         db.Task
 	dependencies []string
 	comments     []db.Comment
+	history      []db.TaskEvent
+	lastColumn   db.TaskStatus // the workflow's last column
 	width        int
 	height       int
 	vp           viewport.Model
@@ -39,13 +42,18 @@ type taskDetail struct {
 	inputs     [4]textinput.Model
 	descInput  textarea.Model
 	titleEmpty bool
+
+	// Comment mode
+	commenting   bool
+	commentInput textinput.Model
 }
 
 func newTaskDetail(task db.Task, svc board.Service) taskDetail {
 	ctx := context.Background()
 	deps, _ := svc.ListDependencies(ctx, task.ID)
 	comments, _ := svc.ListComments(ctx, task.ID)
-	return taskDetail{task: task, dependencies: deps, comments: comments}
+	history, _ := svc.ListTaskEvents(ctx, task.ID)
+	return taskDetail{task: task, dependencies: deps, comments: comments, history: history}
 }
 
 func (d *taskDetail) SetSize(w, h int) {
@@ -94,6 +102,16 @@ func (d *taskDetail) enterEditMode() {
 	d.descInput.SetWidth(fieldWidth)
 }
 
+func (d *taskDetail) startComment() {
+	d.commenting = true
+	d.commentInput = textinput.New()
+	d.commentInput.Prompt = "comment: "
+	d.commentInput.Placeholder = "enter to send, esc to cancel"
+	d.commentInput.CharLimit = 10000
+	d.commentInput.Width = d.width/2 - 14
+	d.commentInput.Focus()
+}
+
 func (d *taskDetail) focusField(field int) {
 	for i := range d.inputs {
 		d.inputs[i].Blur()
@@ -137,6 +155,29 @@ func (d *taskDetail) applyEdits() (bool, tea.Cmd) {
 }
 
 func (d taskDetail) Update(msg tea.Msg) (taskDetail, tea.Cmd) {
+	if d.commenting {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "esc":
+				d.commenting = false
+				return d, nil
+			case "enter":
+				d.commenting = false
+				body := strings.TrimSpace(d.commentInput.Value())
+				if body == "" {
+					return d, nil
+				}
+				taskID := d.task.ID
+				return d, func() tea.Msg {
+					return commentRequestedMsg{taskID: taskID, body: body}
+				}
+			}
+		}
+		var cmd tea.Cmd
+		d.commentInput, cmd = d.commentInput.Update(msg)
+		return d, cmd
+	}
+
 	if !d.editing {
 		// Populate vp.lines so scroll guards (len == 0, AtBottom) work correctly.
 		// View() sets content on a local copy that doesn't persist to a.detail.
@@ -245,6 +286,26 @@ func (d taskDetail) buildReadContent() string {
 		lines = append(lines, fmt.Sprintf("Assignee: @%s", t.Assignee))
 	}
 
+	if t.Priority != db.PriorityNone {
+		lines = append(lines, "Priority: "+priorityBadge(t.Priority))
+	}
+
+	if t.DueDate != "" {
+		due := fmt.Sprintf("Due:     %s", t.DueDate)
+		if t.Overdue(time.Now(), d.lastColumn) {
+			due = overdueStyle.Render(due + " (overdue)")
+		}
+		lines = append(lines, due)
+	}
+
+	if len(t.Labels) > 0 {
+		chips := make([]string, len(t.Labels))
+		for i, l := range t.Labels {
+			chips[i] = labelChip(l)
+		}
+		lines = append(lines, "Labels:  "+strings.Join(chips, " "))
+	}
+
 	if t.AgentName != "" {
 		displayName := t.AgentName
 		if r := agent.GetRunner(t.AgentName); r != nil {
@@ -274,6 +335,10 @@ func (d taskDetail) buildReadContent() string {
 		lines = append(lines, fmt.Sprintf("PR:      %s", t.PRUrl))
 	}
 
+	if t.IssueNumber > 0 {
+		lines = append(lines, fmt.Sprintf("Issue:   %s#%d", t.IssueRepo, t.IssueNumber))
+	}
+
 	if t.EnrichmentStatus != "" && t.EnrichmentStatus != db.EnrichmentNone {
 		enrichStr := fmt.Sprintf("Enrich:  %s", t.EnrichmentStatus)
 		switch t.EnrichmentStatus {
@@ -311,6 +376,18 @@ func (d taskDetail) buildReadContent() string {
 		lines = append(lines, blockedStyle.Render(fmt.Sprintf("Blocked: %s", strings.Join(shortIDs, ", "))))
 	}
 
+	if len(t.Checklist) > 0 {
+		done, total := t.ChecklistProgress()
+		lines = append(lines, "", fmt.Sprintf("Checklist (%d/%d):", done, total))
+		for _, item := range t.Checklist {
+			if item.Done {
+				lines = append(lines, checklistDoneStyle.Render(wrap("  [x] "+item.Text)))
+			} else {
+				lines = append(lines, wrap("  [ ] "+item.Text))
+			}
+		}
+	}
+
 	lines = append(lines, "", fmt.Sprintf("Created: %s", t.CreatedAt.Format("2006-01-02 15:04")))
 
 	if len(d.comments) > 0 {
@@ -322,12 +399,51 @@ func (d taskDetail) buildReadContent() string {
 		}
 	}
 
+	if len(d.history) > 0 {
+		lines = append(lines, "", "History:")
+		for _, ev := range d.history {
+			line := fmt.Sprintf("  [%s] %s %s", ev.CreatedAt.Local().Format("01-02 15:04"), ev.Actor, historyChange(ev))
+			lines = append(lines, historyStyle.Render(wrap(line)))
+		}
+	}
+
 	return strings.Join(lines, "\n")
 }
 
+// historyChange describes one history event, e.g. "status: todo → review".
+func historyChange(ev db.TaskEvent) string {
+	switch ev.Field {
+	case "created":
+		return "created the task"
+	case "comment":
+		return "commented"
+	case "description":
+		return "edited the description"
+	case "blocked_by":
+		ev.OldValue, ev.NewValue = shortTaskID(ev.OldValue), shortTaskID(ev.NewValue)
+	}
+	switch {
+	case ev.OldValue == "":
+		return fmt.Sprintf("%s: %s", ev.Field, ev.NewValue)
+	case ev.NewValue == "":
+		return fmt.Sprintf("%s: removed %s", ev.Field, ev.OldValue)
+	}
+	return fmt.Sprintf("%s: %s → %s", ev.Field, ev.OldValue, ev.NewValue)
+}
+
+func shortTaskID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
 func (d taskDetail) readView() string {
 	d.vp.SetContent(d.buildReadContent())
-	help := helpStyle.Render("esc:close  e:edit  j/k:scroll  g/G:top/btm  m/M:move  a:agent  v:view  A:kill  x:del  E:enrich")
+	help := helpStyle.Render("esc:close  e:edit  c:comment  j/k:scroll  g/G:top/btm  m/M:move  a:agent  v:view  A:kill  x:del  E:enrich")
+	if d.commenting {
+		help = d.commentInput.View()
+	}
 	inner := d.vp.View() + "\n" + help
 	return overlayStyle.Width(d.width / 2).Render(inner)
 }
@@ -3,6 +3,8 @@ package tui
 import (
 	"strings"
 	"testing"
+
+	"github.com/markx3/agentboard/internal/db"
 )
 
 func TestRenderMarkdown_Headers(t *testing.T) {
@@ -67,3 +69,16 @@ func TestRenderMarkdown_TrailingNewlineStripped(t *testing.T) {
 		t.Errorf("renderMarkdown output has trailing newline that would cause double blank line: %q", result)
 	}
 }
+
+func TestDetailOverdueSkipsFinishedTasks(t *testing.T) {
+	d := taskDetail{lastColumn: db.StatusDone}
+	d.vp.Width = 80
+	d.task = db.Task{Title: "Ship it", Status: db.StatusInProgress, DueDate: "2000-01-01"}
+	if !strings.Contains(d.buildReadContent(), "(overdue)") {
+		t.Error("open task past its due date is not marked overdue")
+	}
+	d.task.Status = db.StatusDone
+	if strings.Contains(d.buildReadContent(), "(overdue)") {
+		t.Error("finished task is marked overdue")
+	}
+}
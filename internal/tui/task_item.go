@@ -13,6 +13,7 @@ import (
 type taskItem struct {
 	task     db.Task
 	depCount int
+	last     db.TaskStatus // the workflow's last column
 }
 
 func (t taskItem) Title() string {
@@ -34,6 +35,15 @@ func (t taskItem) Description() string {
 	}
 
 	var parts []string
+	if t.task.AgentQueued {
+		parts = append(parts, agentQueuedStyle.Render("[queued]"))
+	}
+	if badge := priorityBadge(t.task.Priority); badge != "" {
+		parts = append(parts, badge)
+	}
+	if badge := t.dueBadge(time.Now()); badge != "" {
+		parts = append(parts, badge)
+	}
 	if t.task.Assignee != "" {
 		parts = append(parts, fmt.Sprintf("@%s", t.task.Assignee))
 	}
@@ -43,6 +53,12 @@ func (t taskItem) Description() string {
 	if t.depCount > 0 {
 		parts = append(parts, fmt.Sprintf("[%d deps]", t.depCount))
 	}
+	if badge := checklistBadge(t.task); badge != "" {
+		parts = append(parts, badge)
+	}
+	for _, l := range t.task.Labels {
+		parts = append(parts, labelChip(l))
+	}
 	if len(parts) == 0 {
 		return ""
 	}
@@ -64,6 +80,73 @@ func (t taskItem) enrichmentBadge() string {
 	}
 }
 
+// checklistBadge shows checklist progress, e.g. "☑ 2/5", in green once
+// every item is ticked.
+func checklistBadge(task db.Task) string {
+	done, total := task.ChecklistProgress()
+	if total == 0 {
+		return ""
+	}
+	badge := fmt.Sprintf("☑ %d/%d", done, total)
+	if done == total {
+		return checklistDoneStyle.Render(badge)
+	}
+	return checklistStyle.Render(badge)
+}
+
+func priorityBadge(p db.TaskPriority) string {
+	switch p {
+	case db.PriorityUrgent:
+		return priorityUrgentStyle.Render("!!urgent")
+	case db.PriorityHigh:
+		return priorityHighStyle.Render("!high")
+	case db.PriorityMedium:
+		return priorityMediumStyle.Render("medium")
+	case db.PriorityLow:
+		return priorityLowStyle.Render("low")
+	default:
+		return ""
+	}
+}
+
+// done reports whether the task is in the workflow's last column.
+func (t taskItem) done() bool {
+	return t.task.Status == t.last
+}
+
+// dueBadge shows the due date, highlighted once it is today or past.
+// Finished tasks don't show it.
+func (t taskItem) dueBadge(now time.Time) string {
+	due := t.task.DueDate
+	switch {
+	case due == "" || t.done():
+		return ""
+	case t.task.Overdue(now, t.last):
+		return overdueStyle.Render("overdue " + shortDate(due))
+	case due == now.Format(db.DueDateLayout):
+		return dueTodayStyle.Render("due today")
+	default:
+		return dueStyle.Render("due " + shortDate(due))
+	}
+}
+
+// shortDate drops the year from a due date: 2026-10-17 becomes 10-17.
+func shortDate(due string) string {
+	if len(due) == len(db.DueDateLayout) {
+		return due[5:]
+	}
+	return due
+}
+
+// labelChip renders a label as a small tag in the label's color.
+func labelChip(l db.Label) string {
+	style := labelChipStyle
+	if l.Color != "" {
+		style = style.Background(lipgloss.Color(l.Color))
+	}
+	return style.Render(l.Name)
+}
+
 func (t taskItem) FilterValue() string {
 	return t.task.Title
 }
@@ -81,7 +164,7 @@ func (t taskItem) statusPrefix() string {
 			return agentActiveStyle.Render(prefix + label + " " + elapsed + " ")
 		}
 		return agentActiveStyle.Render(prefix + label + " ")
-	case t.task.Status == db.StatusDone:
+	case t.done():
 		return agentDoneStyle.Render("● ")
 	case t.task.AgentStatus == db.AgentCompleted:
 		return agentCompletedStyle.Render("● ")
@@ -96,7 +179,7 @@ func (t taskItem) cardTintStyle() lipgloss.Style {
 	switch {
 	case t.task.AgentStatus == db.AgentActive:
 		return cardActiveBg
-	case t.task.Status == db.StatusDone:
+	case t.done():
 		return cardDoneBg
 	case t.task.AgentStatus == db.AgentCompleted:
 		return cardCompletedBg
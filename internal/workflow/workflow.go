@@ -0,0 +1,198 @@
+// Package workflow defines the board's columns: which statuses exist, their
+// order, display names and the instructions agents get in each.
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/markx3/agentboard/internal/db"
+)
+
+// Column is one stage of the board.
+type Column struct {
+	Status db.TaskStatus `toml:"status" json:"status"`
+	// Name is shown in the TUI and `status`. Defaults to the status with
+	// underscores turned into spaces and words capitalised.
+	Name string `toml:"name" json:"name"`
+	// Prompt replaces the runner's built-in instructions for agents spawned
+	// on tasks in this column.
+	Prompt string `toml:"prompt" json:"prompt,omitempty"`
+	// Review makes agents spawned in this column review the task's changes
+	// rather than work on it.
+	Review bool `toml:"review" json:"review,omitempty"`
+
+	// Rules for moving a task into this column.
+
+	// From lists the columns a task may enter this one from. Empty allows
+	// any column.
+	From []db.TaskStatus `toml:"from" json:"from,omitempty"`
+	// Requires lists task fields that must be set first (see Fields).
+	Requires []string `toml:"requires" json:"requires,omitempty"`
+	// BlockersDone refuses the move while any task this one depends on is
+	// outside the last column.
+	BlockersDone bool `toml:"blockers_done" json:"blockers_done,omitempty"`
+	// ChecklistDone refuses the move while any of the task's checklist
+	// items is unchecked.
+	ChecklistDone bool `toml:"checklist_done" json:"checklist_done,omitempty"`
+}
+
+// Fields are the task fields a column can require.
+var Fields = []string{"assignee", "branch", "description", "pr_url"}
+
+// Workflow is an ordered list of columns. New tasks start in the first
+// column; the last one holds finished work.
+type Workflow struct {
+	columns []Column
+}
+
+var statusPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// Default returns the built-in six-column workflow. Work can't start or be
+// finished while the task is blocked by unfinished dependencies, and
+// agents in the review column review the work.
+func Default() *Workflow {
+	return &Workflow{columns: []Column{
+		{Status: db.StatusBacklog, Name: "Backlog"},
+		{Status: db.StatusBrainstorm, Name: "Brainstorm"},
+		{Status: db.StatusPlanning, Name: "Planning"},
+		{Status: db.StatusInProgress, Name: "In Progress", BlockersDone: true},
+		{Status: db.StatusReview, Name: "Review", BlockersDone: true, Review: true},
+		{Status: db.StatusDone, Name: "Done", BlockersDone: true},
+	}}
+}
+
+// New builds a workflow from columns. An empty list means Default().
+func New(columns []Column) (*Workflow, error) {
+	if len(columns) == 0 {
+		return Default(), nil
+	}
+	if len(columns) < 2 {
+		return nil, fmt.Errorf("a workflow needs at least two columns")
+	}
+	seen := make(map[db.TaskStatus]bool, len(columns))
+	cols := make([]Column, len(columns))
+	for i, c := range columns {
+		if !statusPattern.MatchString(string(c.Status)) {
+			return nil, fmt.Errorf("column status %q must be lowercase letters, digits and underscores (max 32)", c.Status)
+		}
+		if seen[c.Status] {
+			return nil, fmt.Errorf("column status %q is defined twice", c.Status)
+		}
+		seen[c.Status] = true
+		if c.Name == "" {
+			c.Name = displayName(c.Status)
+		}
+		for _, f := range c.Requires {
+			if !slices.Contains(Fields, f) {
+				return nil, fmt.Errorf("column %q requires unknown field %q (use: %s)", c.Status, f, strings.Join(Fields, ", "))
+			}
+		}
+		cols[i] = c
+	}
+	for _, c := range cols {
+		for _, from := range c.From {
+			if !seen[from] {
+				return nil, fmt.Errorf("column %q allows moves from unknown column %q", c.Status, from)
+			}
+		}
+	}
+	return &Workflow{columns: cols}, nil
+}
+
+// displayName turns "in_progress" into "In Progress".
+func displayName(status db.TaskStatus) string {
+	words := strings.Split(string(status), "_")
+	for i, w := range words {
+		if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// Columns returns the columns in board order.
+func (w *Workflow) Columns() []Column {
+	return append([]Column(nil), w.columns...)
+}
+
+// Statuses returns the column statuses in board order.
+func (w *Workflow) Statuses() []db.TaskStatus {
+	statuses := make([]db.TaskStatus, len(w.columns))
+	for i, c := range w.columns {
+		statuses[i] = c.Status
+	}
+	return statuses
+}
+
+// Index returns the position of status in the board, or -1.
+func (w *Workflow) Index(status db.TaskStatus) int {
+	for i, c := range w.columns {
+		if c.Status == status {
+			return i
+		}
+	}
+	return -1
+}
+
+// Valid reports whether status is one of the workflow's columns.
+func (w *Workflow) Valid(status db.TaskStatus) bool {
+	return w.Index(status) >= 0
+}
+
+// Column returns the column for status.
+func (w *Workflow) Column(status db.TaskStatus) (Column, bool) {
+	if i := w.Index(status); i >= 0 {
+		return w.columns[i], true
+	}
+	return Column{}, false
+}
+
+// Name returns the display name of status, or the status itself if it is
+// not part of the workflow.
+func (w *Workflow) Name(status db.TaskStatus) string {
+	if c, ok := w.Column(status); ok {
+		return c.Name
+	}
+	return string(status)
+}
+
+// First is the column new tasks are created in.
+func (w *Workflow) First() db.TaskStatus {
+	return w.columns[0].Status
+}
+
+// Last is the column for finished tasks.
+func (w *Workflow) Last() db.TaskStatus {
+	return w.columns[len(w.columns)-1].Status
+}
+
+// Next returns the column after status, or status itself at the end of the
+// board or when status is unknown.
+func (w *Workflow) Next(status db.TaskStatus) db.TaskStatus {
+	if i := w.Index(status); i >= 0 && i < len(w.columns)-1 {
+		return w.columns[i+1].Status
+	}
+	return status
+}
+
+// Prev returns the column before status, or status itself at the start of
+// the board or when status is unknown.
+func (w *Workflow) Prev(status db.TaskStatus) db.TaskStatus {
+	if i := w.Index(status); i > 0 {
+		return w.columns[i-1].Status
+	}
+	return status
+}
+
+// ValidList returns the statuses as a comma-separated list for error
+// messages.
+func (w *Workflow) ValidList() string {
+	parts := make([]string, len(w.columns))
+	for i, c := range w.columns {
+		parts[i] = string(c.Status)
+	}
+	return strings.Join(parts, ", ")
+}
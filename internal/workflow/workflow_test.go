@@ -0,0 +1,65 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/markx3/agentboard/internal/db"
+)
+
+func TestDefaultWorkflow(t *testing.T) {
+	wf := Default()
+	if wf.First() != db.StatusBacklog || wf.Last() != db.StatusDone {
+		t.Errorf("got first/last %s/%s, want backlog/done", wf.First(), wf.Last())
+	}
+	if got := wf.Next(db.StatusPlanning); got != db.StatusInProgress {
+		t.Errorf("Next(planning) = %s, want in_progress", got)
+	}
+	if got := wf.Next(db.StatusDone); got != db.StatusDone {
+		t.Errorf("Next(done) = %s, want done", got)
+	}
+	if got := wf.Prev(db.StatusBacklog); got != db.StatusBacklog {
+		t.Errorf("Prev(backlog) = %s, want backlog", got)
+	}
+	if wf.Valid("qa") {
+		t.Error("qa should not be valid in the default workflow")
+	}
+}
+
+func TestNewValidatesColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns []Column
+		wantErr bool
+	}{
+		{"empty means default", nil, false},
+		{"custom", []Column{{Status: "todo"}, {Status: "qa"}, {Status: "deployed"}}, false},
+		{"one column", []Column{{Status: "todo"}}, true},
+		{"duplicate", []Column{{Status: "todo"}, {Status: "todo"}}, true},
+		{"uppercase", []Column{{Status: "Todo"}, {Status: "done"}}, true},
+		{"empty status", []Column{{Status: ""}, {Status: "done"}}, true},
+		{"rules", []Column{{Status: "todo"}, {Status: "done", From: []db.TaskStatus{"todo"}, Requires: []string{"pr_url"}}}, false},
+		{"unknown from", []Column{{Status: "todo"}, {Status: "done", From: []db.TaskStatus{"qa"}}}, true},
+		{"unknown field", []Column{{Status: "todo"}, {Status: "done", Requires: []string{"reviewer"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.columns)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDisplayNameDefault(t *testing.T) {
+	wf, err := New([]Column{{Status: "ready_for_qa"}, {Status: "done", Name: "Shipped"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := wf.Name("ready_for_qa"); got != "Ready For Qa" {
+		t.Errorf("Name(ready_for_qa) = %q", got)
+	}
+	if got := wf.Name("done"); got != "Shipped" {
+		t.Errorf("Name(done) = %q, want Shipped", got)
+	}
+}
@@ -0,0 +1,213 @@
+// Package worktree wraps the git worktree commands agentboard uses to give
+// each task its own checkout.
+package worktree
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// BranchPrefix namespaces the branches agentboard creates for tasks.
+const BranchPrefix = "agentboard/"
+
+// ErrNotRepo is returned when the project directory is not inside a git
+// repository.
+var ErrNotRepo = errors.New("not a git repository")
+
+// Worktree describes one entry of `git worktree list`.
+type Worktree struct {
+	Path     string
+	Head     string
+	Branch   string // short branch name, empty when detached
+	Detached bool
+	Bare     bool
+}
+
+// git runs a git command in dir and returns its trimmed stdout. Stderr is
+// folded into the error so callers can surface git's own message.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// RepoRoot returns the top-level directory of the repository containing dir.
+func RepoRoot(dir string) (string, error) {
+	out, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", dir, ErrNotRepo)
+	}
+	return filepath.Clean(out), nil
+}
+
+// List returns all worktrees of the repository containing repo. The main
+// worktree is always first.
+func List(repo string) ([]Worktree, error) {
+	out, err := git(repo, "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+	return parsePorcelain(out), nil
+}
+
+func parsePorcelain(out string) []Worktree {
+	var (
+		list []Worktree
+		cur  *Worktree
+	)
+	for _, line := range strings.Split(out, "\n") {
+		key, val, _ := strings.Cut(line, " ")
+		switch key {
+		case "worktree":
+			list = append(list, Worktree{Path: filepath.Clean(val)})
+			cur = &list[len(list)-1]
+		case "HEAD":
+			if cur != nil {
+				cur.Head = val
+			}
+		case "branch":
+			if cur != nil {
+				cur.Branch = strings.TrimPrefix(val, "refs/heads/")
+			}
+		case "detached":
+			if cur != nil {
+				cur.Detached = true
+			}
+		case "bare":
+			if cur != nil {
+				cur.Bare = true
+			}
+		}
+	}
+	return list
+}
+
+// FindByBranch returns the linked worktree that has branch checked out, or
+// nil if there is none. The main worktree is never returned.
+func FindByBranch(repo, branch string) (*Worktree, error) {
+	list, err := List(repo)
+	if err != nil {
+		return nil, err
+	}
+	for i, wt := range list {
+		if i == 0 {
+			continue
+		}
+		if wt.Branch == branch {
+			return &list[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// BranchExists reports whether a local branch with the given name exists.
+func BranchExists(repo, branch string) bool {
+	_, err := git(repo, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	return err == nil
+}
+
+// Add creates a worktree at path with branch checked out. The branch is
+// created from HEAD if it does not exist yet.
+func Add(repo, path, branch string) error {
+	var err error
+	if BranchExists(repo, branch) {
+		_, err = git(repo, "worktree", "add", path, branch)
+	} else {
+		_, err = git(repo, "worktree", "add", "-b", branch, path)
+	}
+	return err
+}
+
+// Remove deletes the worktree at path. With force, uncommitted changes are
+// discarded.
+func Remove(repo, path string, force bool) error {
+	args := []string{"worktree", "remove"}
+	if force {
+		args = append(args, "--force")
+	}
+	_, err := git(repo, append(args, path)...)
+	return err
+}
+
+// DeleteBranch deletes a local branch. With force, unmerged branches are
+// deleted too.
+func DeleteBranch(repo, branch string, force bool) error {
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	_, err := git(repo, "branch", flag, branch)
+	return err
+}
+
+// Prune removes administrative entries for worktrees whose directories no
+// longer exist.
+func Prune(repo string) error {
+	_, err := git(repo, "worktree", "prune")
+	return err
+}
+
+// IsDirty reports whether the worktree at path has uncommitted changes,
+// including untracked files.
+func IsDirty(path string) (bool, error) {
+	out, err := git(path, "status", "--porcelain")
+	if err != nil {
+		return false, err
+	}
+	return out != "", nil
+}
+
+// Unpushed returns the number of commits on HEAD that are not on any remote
+// branch.
+func Unpushed(path string) (int, error) {
+	out, err := git(path, "rev-list", "--count", "HEAD", "--not", "--remotes")
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(out)
+	if err != nil {
+		return 0, fmt.Errorf("parsing commit count %q: %w", out, err)
+	}
+	return n, nil
+}
+
+// MainBranch returns the branch checked out in the repository's main
+// worktree, which task branches are created from. It is empty when the
+// main worktree is detached.
+func MainBranch(repo string) (string, error) {
+	wts, err := List(repo)
+	if err != nil {
+		return "", err
+	}
+	if len(wts) == 0 {
+		return "", fmt.Errorf("git worktree list: no worktrees")
+	}
+	return wts[0].Branch, nil
+}
+
+// Diff returns the changes committed at path since it branched off base,
+// and their --stat summary.
+func Diff(path, base string) (diff, stat string, err error) {
+	rng := base + "...HEAD"
+	if stat, err = git(path, "diff", "--stat", rng); err != nil {
+		return "", "", err
+	}
+	if diff, err = git(path, "diff", rng); err != nil {
+		return "", "", err
+	}
+	return diff, stat, nil
+}
@@ -0,0 +1,173 @@
+package worktree_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/markx3/agentboard/internal/worktree"
+)
+
+// setupRepo creates a git repository with one commit and returns its path.
+func setupRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("resolving temp dir: %v", err)
+	}
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func TestRepoRootNotRepo(t *testing.T) {
+	_, err := worktree.RepoRoot(t.TempDir())
+	if !errors.Is(err, worktree.ErrNotRepo) {
+		t.Errorf("got %v, want ErrNotRepo", err)
+	}
+}
+
+func TestAddFindRemove(t *testing.T) {
+	repo := setupRepo(t)
+	path := filepath.Join(repo, ".agentboard", "worktrees", "fix-login")
+
+	if err := worktree.Add(repo, path, "agentboard/fix-login"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("worktree dir missing: %v", err)
+	}
+	if !worktree.BranchExists(repo, "agentboard/fix-login") {
+		t.Error("branch should exist after Add")
+	}
+
+	list, err := worktree.List(repo)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 2 || list[0].Path != repo {
+		t.Fatalf("got %+v, want main worktree plus one", list)
+	}
+
+	wt, err := worktree.FindByBranch(repo, "agentboard/fix-login")
+	if err != nil {
+		t.Fatalf("FindByBranch: %v", err)
+	}
+	if wt == nil || wt.Path != path {
+		t.Fatalf("got %+v, want worktree at %s", wt, path)
+	}
+
+	// The main worktree is never matched.
+	if wt, _ := worktree.FindByBranch(repo, "main"); wt != nil {
+		t.Errorf("FindByBranch(main) = %+v, want nil", wt)
+	}
+
+	if err := worktree.Remove(repo, path, false); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if wt, _ := worktree.FindByBranch(repo, "agentboard/fix-login"); wt != nil {
+		t.Errorf("worktree still listed after Remove: %+v", wt)
+	}
+	if err := worktree.DeleteBranch(repo, "agentboard/fix-login", false); err != nil {
+		t.Fatalf("DeleteBranch: %v", err)
+	}
+}
+
+func TestAddExistingBranch(t *testing.T) {
+	repo := setupRepo(t)
+	if out, err := exec.Command("git", "-C", repo, "branch", "feature").CombinedOutput(); err != nil {
+		t.Fatalf("git branch: %v\n%s", err, out)
+	}
+	path := filepath.Join(repo, "wt")
+	if err := worktree.Add(repo, path, "feature"); err != nil {
+		t.Fatalf("Add with existing branch: %v", err)
+	}
+	wt, _ := worktree.FindByBranch(repo, "feature")
+	if wt == nil {
+		t.Fatal("expected worktree for existing branch")
+	}
+}
+
+func TestIsDirtyAndUnpushed(t *testing.T) {
+	repo := setupRepo(t)
+
+	dirty, err := worktree.IsDirty(repo)
+	if err != nil {
+		t.Fatalf("IsDirty: %v", err)
+	}
+	if dirty {
+		t.Error("fresh repo should be clean")
+	}
+
+	if err := os.WriteFile(filepath.Join(repo, "new.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if dirty, _ := worktree.IsDirty(repo); !dirty {
+		t.Error("untracked file should make the worktree dirty")
+	}
+
+	// No remotes, so the initial commit counts as unpushed.
+	n, err := worktree.Unpushed(repo)
+	if err != nil {
+		t.Fatalf("Unpushed: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Unpushed = %d, want 1", n)
+	}
+}
+
+func TestMainBranchAndDiff(t *testing.T) {
+	repo := setupRepo(t)
+	path := filepath.Join(repo, ".agentboard", "worktrees", "feature")
+	if err := worktree.Add(repo, path, "agentboard/feature"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	base, err := worktree.MainBranch(path)
+	if err != nil {
+		t.Fatalf("MainBranch: %v", err)
+	}
+	if base != "main" {
+		t.Errorf("MainBranch = %q, want main", base)
+	}
+
+	if err := os.WriteFile(filepath.Join(path, "login.go"), []byte("package login\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"add", "login.go"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "add login"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", path}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	diff, stat, err := worktree.Diff(path, base)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if !strings.Contains(diff, "+package login") {
+		t.Errorf("diff = %q, want the new file", diff)
+	}
+	if !strings.Contains(stat, "login.go | 1 +") {
+		t.Errorf("stat = %q", stat)
+	}
+	// The base itself has no changes of its own.
+	if diff, _, _ := worktree.Diff(repo, base); diff != "" {
+		t.Errorf("diff on main = %q, want none", diff)
+	}
+}